@@ -76,7 +76,7 @@ const docTemplate = `{
         },
         "/auth/logout": {
             "post": {
-                "description": "Logout the current user (client-side token invalidation)",
+                "description": "Logout the current user (client-side token invalidation). Clears the session cookie used to view private pages.",
                 "produces": [
                     "application/json"
                 ],
@@ -243,70 +243,75 @@ const docTemplate = `{
                 }
             }
         },
-        "/groups": {
+        "/change-requests": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all groups the current user is a member of",
+                "description": "Get change requests to protected links that the current user requested or can approve",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "change-requests"
+                ],
+                "summary": "List change requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status: pending (default), approved, rejected or all",
+                        "name": "status",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List groups",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/groups.GroupResponse"
+                                "$ref": "#/definitions/links.ChangeRequestResponse"
                             }
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/change-requests/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new group with the current user as admin",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get a change request the current user requested or can approve",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "change-requests"
                 ],
-                "summary": "Create a group",
+                "summary": "Get a change request",
                 "parameters": [
                     {
-                        "description": "Group details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/groups.CreateGroupRequest"
-                        }
+                        "type": "integer",
+                        "description": "Change request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/groups.GroupResponse"
+                            "$ref": "#/definitions/links.ChangeRequestResponse"
                         }
                     },
-                    "400": {
-                        "description": "Validation error",
+                    "404": {
+                        "description": "Change request not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -317,39 +322,77 @@ const docTemplate = `{
                 }
             }
         },
-        "/groups/{id}": {
-            "get": {
+        "/change-requests/{id}/approve": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get details of a specific group",
+                "description": "Approve a pending change to a protected link and apply it on behalf of the requester, whose role in the group must still allow the change. Requesters can't approve their own changes.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "change-requests"
                 ],
-                "summary": "Get a group",
+                "summary": "Approve a change request",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
+                        "description": "Change request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Review comment",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/links.ReviewChangeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/groups.GroupResponse"
+                            "$ref": "#/definitions/links.ChangeRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The change is no longer valid",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not an approver",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
-                        "description": "Group not found",
+                        "description": "Change request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Already reviewed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -358,14 +401,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/change-requests/{id}/reject": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a group (requires admin role in group)",
+                "description": "Reject a pending change to a protected link. The change is not applied.",
                 "consumes": [
                     "application/json"
                 ],
@@ -373,24 +418,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "change-requests"
                 ],
-                "summary": "Update a group",
+                "summary": "Reject a change request",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
+                        "description": "Change request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated group details",
+                        "description": "Review comment",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/groups.UpdateGroupRequest"
+                            "$ref": "#/definitions/links.ReviewChangeRequest"
                         }
                     }
                 ],
@@ -398,11 +442,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/groups.GroupResponse"
+                            "$ref": "#/definitions/links.ChangeRequestResponse"
                         }
                     },
-                    "400": {
-                        "description": "Validation error",
+                    "403": {
+                        "description": "Not an approver",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -410,8 +454,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Admin access required",
+                    "404": {
+                        "description": "Change request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Already reviewed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -420,33 +473,80 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/collections": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a group (requires admin role in group)",
+                "description": "Get the curated collections owned by the user's groups",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "collections"
                 ],
-                "summary": "Delete a group",
+                "summary": "List collections",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only collections owned by this group",
+                        "name": "group_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Group deleted",
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/collections.CollectionResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a curated, hand-ordered collection of links owned by a group. Public collections are rendered read-only at /c/{slug}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Create a collection",
+                "parameters": [
+                    {
+                        "description": "Collection",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/collections.CreateCollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/collections.CollectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -455,7 +555,25 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
+                        "description": "Role doesn't allow adding collections",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Slug already taken",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -466,63 +584,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/groups/{id}/links": {
+        "/collections/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all links belonging to a specific group",
+                "description": "Get a collection and its items in order. Links the user can't see are left out.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "links"
+                    "collections"
                 ],
-                "summary": "List links in a group",
+                "summary": "Get a collection",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
+                        "description": "Collection ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Filter by unread status",
-                        "name": "is_unread",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Filter by public status",
-                        "name": "is_public",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/links.LinkResponse"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid group ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/collections.CollectionResponse"
                         }
                     },
                     "404": {
-                        "description": "Group not found",
+                        "description": "Collection not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -532,13 +626,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new shortened link in a group",
+                "description": "Update a collection's title, slug, description or public sharing",
                 "consumes": [
                     "application/json"
                 ],
@@ -546,32 +640,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "links"
+                    "collections"
                 ],
-                "summary": "Create a link",
+                "summary": "Update a collection",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
+                        "description": "Collection ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Link details",
+                        "description": "Collection changes",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/links.CreateLinkRequest"
+                            "$ref": "#/definitions/collections.UpdateCollectionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/links.LinkResponse"
+                            "$ref": "#/definitions/collections.CollectionResponse"
                         }
                     },
                     "400": {
@@ -583,8 +677,26 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Role doesn't allow changing the collection",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
-                        "description": "Group not found",
+                        "description": "Collection not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Slug already taken",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -593,113 +705,124 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/links": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Search links across all groups the user has access to",
+                "description": "Delete a collection. The links it references are not affected.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "links"
+                    "collections"
                 ],
-                "summary": "Search links",
+                "summary": "Delete a collection",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Search query (searches title, description, URL)",
-                        "name": "q",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Filter by unread status",
-                        "name": "is_unread",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Filter by public status",
-                        "name": "is_public",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by group ID",
-                        "name": "group_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by tag name",
-                        "name": "tag",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Max results (default 50, max 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "description": "Offset for pagination",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Collection deleted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/links.LinkResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Role doesn't allow changing the collection",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/links/{slug}": {
-            "get": {
+        "/collections/{id}/items": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get link details by its short slug",
+                "description": "Add a link (by link_id or link_slug) or a section heading to a collection, at the given position or at the end",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "links"
+                    "collections"
                 ],
-                "summary": "Get a link by slug",
+                "summary": "Add a collection item",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Link slug",
-                        "name": "slug",
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Item",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/collections.AddItemRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/links.LinkResponse"
+                            "$ref": "#/definitions/collections.CollectionItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Role doesn't allow changing the collection",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
-                        "description": "Link not found",
+                        "description": "Collection or link not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -708,14 +831,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/collections/{id}/items/{itemId}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing link by slug",
+                "description": "Edit a section's heading or any item's note",
                 "consumes": [
                     "application/json"
                 ],
@@ -723,24 +848,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "links"
+                    "collections"
                 ],
-                "summary": "Update a link",
+                "summary": "Update a collection item",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Link slug",
-                        "name": "slug",
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated link details",
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item changes",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/links.UpdateLinkRequest"
+                            "$ref": "#/definitions/collections.UpdateItemRequest"
                         }
                     }
                 ],
@@ -748,7 +880,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/links.LinkResponse"
+                            "$ref": "#/definitions/collections.CollectionItemResponse"
                         }
                     },
                     "400": {
@@ -760,8 +892,17 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Role doesn't allow changing the collection",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
-                        "description": "Link not found",
+                        "description": "Collection or item not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -777,26 +918,33 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a link by slug",
+                "description": "Remove a link or section heading from a collection. The link itself is not affected.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "links"
+                    "collections"
                 ],
-                "summary": "Delete a link",
+                "summary": "Remove a collection item",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Link slug",
-                        "name": "slug",
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "itemId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Link deleted",
+                        "description": "Item removed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -804,52 +952,35 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Link not found",
+                    "403": {
+                        "description": "Role doesn't allow changing the collection",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/organizations": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get all organizations the current user is a member of",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "organizations"
-                ],
-                "summary": "List organizations",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Collection or item not found",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/organizations.OrgResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/collections/{id}/order": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new organization with the current user as admin",
+                "description": "Set the order of a collection's items. item_ids must list every item in the collection exactly once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -857,25 +988,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "organizations"
+                    "collections"
                 ],
-                "summary": "Create an organization",
+                "summary": "Reorder collection items",
                 "parameters": [
                     {
-                        "description": "Organization details",
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New order",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/organizations.CreateOrgRequest"
+                            "$ref": "#/definitions/collections.ReorderItemsRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/organizations.OrgResponse"
+                            "$ref": "#/definitions/collections.CollectionResponse"
                         }
                     },
                     "400": {
@@ -886,59 +1024,72 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "403": {
+                        "description": "Role doesn't allow changing the collection",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/organizations/{id}": {
+        "/duplicates": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get details of a specific organization",
+                "description": "List clusters of links with the same canonical URL (ignoring host case, default ports, query parameter order and tracking parameters). Only links in the caller's groups are considered.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "organizations"
+                    "links"
                 ],
-                "summary": "Get an organization",
+                "summary": "List duplicate links",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Organization ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only report duplicates in this organization",
+                        "name": "organization_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/organizations.OrgResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Organization not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.DuplicateCluster"
                             }
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/duplicates/merge": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an organization (requires admin role in org)",
+                "description": "Turn links into aliases of a target link in the same organization with the same destination. Pages can't be merged. Aliases keep their slugs but redirect to the target's URL, and clicks are credited to the target. Links already aliased to a merged link are re-pointed at the target.",
                 "consumes": [
                     "application/json"
                 ],
@@ -946,24 +1097,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "organizations"
+                    "links"
                 ],
-                "summary": "Update an organization",
+                "summary": "Merge duplicate links",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Organization ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated organization details",
+                        "description": "Links to merge",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/organizations.UpdateOrgRequest"
+                            "$ref": "#/definitions/links.MergeDuplicatesRequest"
                         }
                     }
                 ],
@@ -971,7 +1115,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/organizations.OrgResponse"
+                            "$ref": "#/definitions/links.MergeDuplicatesResponse"
                         }
                     },
                     "400": {
@@ -984,51 +1128,90 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
+                        "description": "Role doesn't allow editing a merged link, or it is protected",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            },
-            "delete": {
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/groups": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an organization (requires admin role, soft delete)",
+                "description": "Get all groups the current user is a member of, including subgroups of their groups",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "organizations"
+                    "groups"
                 ],
-                "summary": "Delete an organization",
+                "summary": "List groups",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/groups.GroupResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new group with the current user as admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Create a group",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Organization ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Group details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/groups.CreateGroupRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Organization deleted",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/groups.GroupResponse"
                         }
                     },
-                    "403": {
-                        "description": "Admin access required",
+                    "400": {
+                        "description": "Validation error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1039,25 +1222,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/organizations/{id}/members": {
+        "/groups/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all members of an organization",
+                "description": "Get details of a specific group",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "organizations"
+                    "groups"
                 ],
-                "summary": "List organization members",
+                "summary": "Get a group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Organization ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1067,14 +1250,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/organizations.MemberResponse"
-                            }
+                            "$ref": "#/definitions/groups.GroupResponse"
                         }
                     },
                     "404": {
-                        "description": "Organization not found",
+                        "description": "Group not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1084,13 +1264,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add a user to an organization by email (requires admin role)",
+                "description": "Update a group (requires admin role in group). Setting public_slug and is_public_page publishes the group's public links at /g/{public_slug}.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1098,32 +1278,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "organizations"
+                    "groups"
                 ],
-                "summary": "Add a member to an organization",
+                "summary": "Update a group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Organization ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Member details",
+                        "description": "Updated group details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/organizations.AddMemberRequest"
+                            "$ref": "#/definitions/groups.UpdateGroupRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/organizations.MemberResponse"
+                            "$ref": "#/definitions/groups.GroupResponse"
                         }
                     },
                     "400": {
@@ -1145,56 +1325,146 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{id}/members/{userId}": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a member's role in an organization (requires admin role)",
-                "consumes": [
+                "description": "Delete a group (requires admin role in group)",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Delete a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Group deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/invites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a group's email invites and invite links with their status (requires admin role in group)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "organizations"
+                    "groups"
                 ],
-                "summary": "Update a member's role",
+                "summary": "List group invites",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Organization ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/groups.InviteResponse"
+                            }
+                        }
                     },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invite an email address to the group, or leave out the email to create an invite link that can be used max_uses times. Email invites are mailed to the invitee and accepted when they accept the invite, register with its token, or log in via OIDC with the verified address. The token is only returned once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Create a group invite",
+                "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "userId",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated role",
+                        "description": "Invite details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/organizations.UpdateMemberRequest"
+                            "$ref": "#/definitions/groups.CreateInviteRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/organizations.MemberResponse"
+                            "$ref": "#/definitions/groups.CreateInviteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "403": {
@@ -1205,42 +1475,59 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Already a member",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/groups/{id}/invites/{inviteId}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove a member from an organization (requires admin role)",
+                "description": "Revoke an email invite or invite link (requires admin role in group). Members who already joined stay in the group.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "organizations"
+                    "groups"
                 ],
-                "summary": "Remove a member from an organization",
+                "summary": "Revoke a group invite",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Organization ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "userId",
+                        "description": "Invite ID",
+                        "name": "inviteId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Member removed",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/groups.InviteResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1248,8 +1535,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Admin access required",
+                    "404": {
+                        "description": "Invite not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1259,150 +1546,4344 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.AuthResponse": {
-            "type": "object",
+        },
+        "/groups/{id}/links": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all links belonging to a specific group",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "List links in a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by unread status",
+                        "name": "is_unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by public status",
+                        "name": "is_public",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag name, including tags below it",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a tag expression, like tag=oncall AND NOT tag=deprecated",
+                        "name": "tag_query",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.LinkResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group ID or tag query",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new shortened link in a group. Set kind to \"page\" to create a page whose Markdown content is shown at the short URL instead of redirecting. Links with a protected slug are not created immediately; a change request is returned for approval instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Create a link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Link details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/links.CreateLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/links.LinkResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Awaiting approval",
+                        "schema": {
+                            "$ref": "#/definitions/links.ChangeRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error, including links that break the group's rules",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Role doesn't allow adding links",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/links/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move or copy every link in a group to another group. Moving requires admin access to the source group; both modes require admin access to the target group.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Move or copy a group's links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Source group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transfer details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/links.TransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required, or a link is protected",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Slug conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/pinned-searches": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the saved searches pinned to a group's dashboard, with the number of links each currently matches for the caller",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved-searches"
+                ],
+                "summary": "List a group's pinned searches",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.PinnedSearchResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/policy": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the defaults applied to new links in the group and the rules they must follow",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get a group's link policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/groups.LinkPolicyResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the defaults applied to new and imported links in the group (visibility, tags and expiry) and the rules they must follow (allowed destination domains, a required title and a slug prefix). Existing links are unchanged. Requires admin role in group.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Update a group's link policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Policy changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/groups.UpdateLinkPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/groups.LinkPolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/invites/{token}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Join a group with an invite token, with the invited role. Email invites can only be accepted by the invited address. Members already in the group keep their role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Accept a group invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/groups.GroupResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Invite is for a different email address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Invite not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search links across all groups the user has access to, and links shared with them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Search links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query (searches title, description, URL and page content)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by unread status",
+                        "name": "is_unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by public status",
+                        "name": "is_public",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by group ID",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag name, including tags below it (infra matches infra/monitoring)",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a tag expression, like tag=oncall AND (tag=sre OR tag=infra) AND NOT tag=deprecated",
+                        "name": "tag_query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 50, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.LinkResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tag query",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply an action (delete, move, add_tags, remove_tags, set_public, set_unread, set_expiry) to a set of links selected by slug, ID or search query. Each link is updated in its own transaction unless atomic is set. Moves stay within the links' organization. Use dry_run to preview the affected links.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Bulk link operations",
+                "parameters": [
+                    {
+                        "description": "Bulk operation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/links.BulkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.BulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Atomic operation rolled back",
+                        "schema": {
+                            "$ref": "#/definitions/links.BulkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get link details by its short slug",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Get a link by slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.LinkResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing link by slug. Changes to links with a protected slug, or renames to one, are not applied immediately; a change request is returned for approval instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Update a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated link details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/links.UpdateLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.LinkResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Awaiting approval",
+                        "schema": {
+                            "$ref": "#/definitions/links.ChangeRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Read-only access to the link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a link by slug",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Delete a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Link deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Awaiting approval",
+                        "schema": {
+                            "$ref": "#/definitions/links.ChangeRequestResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Role doesn't allow deleting the link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the comments on a link, oldest first, with replies nested under their parent comment",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "List link comments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/comments.CommentResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a comment or reply to a link. Mention users who can see the link with @ followed by their email address (e.g. @jane@example.com); other addresses are ignored. Replying to a reply adds to the same thread.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Comment on a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/comments.CreateCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/comments.CommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/comments/{commentId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Edit the body of a comment. Only the author can edit a comment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Edit a comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated comment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/comments.UpdateCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/comments.CommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Only the author can edit a comment",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a comment. Deleting a top-level comment also deletes its replies. The author or an admin of the link's group can delete a comment.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Delete a comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comment deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to delete this comment",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/grants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the users and groups outside the owning group that a link is shared with",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "List link grants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.GrantResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Share a single link with a user or group in the organization as a viewer or editor, without adding them to the owning group. Sharing again with the same user or group changes their role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Share a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Grantee and role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/links.CreateGrantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role changed",
+                        "schema": {
+                            "$ref": "#/definitions/links.GrantResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/links.GrantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/grants/{grantId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop sharing a link with a user or group",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Revoke a link grant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Grant ID",
+                        "name": "grantId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Grant revoked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Grant not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get changes made to a link by organization admins, such as its tags being renamed, merged or deleted, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Get link history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.LinkEventResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/metadata": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch the link's destination page and update its title, description, preview image and favicon. The title and description are only replaced when empty unless overwrite is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Refresh link metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refresh options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/links.RefreshMetadataRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.LinkResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Overwriting a protected link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Failed to fetch metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the revision history of a page link, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "List page revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.RevisionResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Not a page",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/revisions/{revision}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the title and content of a page as of a revision",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Get a page revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.RevisionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Not a page",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/revisions/{revision}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a page's title and content from an earlier revision. The restore is saved as a new revision. Pages with a protected slug return a change request for approval instead.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Restore a page revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.LinkResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Awaiting approval",
+                        "schema": {
+                            "$ref": "#/definitions/links.ChangeRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Not a page",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Read-only access to the link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/state": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current user's favorite, pinned, unread and notes state for a link",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Get my link state",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.LinkStateResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the current user's favorite, pinned, unread or notes state for a link. This doesn't affect other group members; the link's own is_unread remains the group-level default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Update my link state",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "State changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/links.UpdateLinkStateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.LinkStateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{slug}/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move or copy a link to another group the caller administers, possibly in another organization. Slug conflicts in the target organization are skipped, renamed or fail the request depending on on_conflict.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Move or copy a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transfer details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/links.TransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access to the target group required, or the link is protected",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Slug conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/links/favorites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the links the current user has marked as favorites across all their groups",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "List my favorite links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 50, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.MyLinkResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/links/pinned": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the links the current user has pinned across all their groups, most recently pinned first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "List my pinned links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 50, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.MyLinkResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/links/unread": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the links that are unread for the current user across all their groups. A link the user hasn't marked read or unread themselves follows its group-level is_unread flag.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "List my unread links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 50, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.MyLinkResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all organizations the current user is a member of",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List organizations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/organizations.OrgResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new organization with the current user as admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Create an organization",
+                "parameters": [
+                    {
+                        "description": "Organization details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.CreateOrgRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.OrgResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get details of a specific organization",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Get an organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.OrgResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an organization (requires admin role in org)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Update an organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated organization details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.UpdateOrgRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.OrgResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an organization (requires admin role, soft delete)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Delete an organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Organization deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all members of an organization",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List organization members",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/organizations.MemberResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Organization not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a user to an organization by email (requires admin role)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Add a member to an organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Member details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.AddMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.MemberResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/members/{userId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a member's role in an organization (requires admin role)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Update a member's role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.UpdateMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.MemberResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a member from an organization (requires admin role). Their links in the organization are transferred, kept or deleted according to its departure policy.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Remove a member from an organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Member removed",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.DepartureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/members/{userId}/departure-preview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Show which of a member's links would be transferred, kept or deleted by the organization's departure policy if they were removed (requires admin role)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Preview removing a member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.DepartureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Member not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/protected-slugs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the slugs and patterns whose links only change with approval",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List protected slugs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/organizations.ProtectedSlugResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Organization not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Protect a slug or pattern so that creating or changing matching links needs approval (requires admin role)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Protect a slug",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Protected slug",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.ProtectedSlugRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.ProtectedSlugResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/protected-slugs/{ruleId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change a protected slug's pattern or approvers (requires admin role)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Update a protected slug",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Protected slug ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Protected slug",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.ProtectedSlugRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organizations.ProtectedSlugResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Protected slug not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop protecting a slug or pattern. Pending change requests can still be reviewed. (requires admin role)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Remove a protected slug",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Protected slug ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Protected slug removed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Protected slug not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/stale-links": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the organization's links that are candidates for archiving: links whose creator has left, that haven't been clicked for a year, or whose destination is broken. Scores of 50 or more are stale. (requires admin role)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List stale links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/stale.StaleLinkResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/saved-searches": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current user's saved searches and searches shared with their groups",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved-searches"
+                ],
+                "summary": "List saved searches",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only searches shared with this group",
+                        "name": "group_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.SavedSearchResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Save a named search. Set group_id to share it with a group's members, which needs a role that can edit the group's links, and is_pinned to show it on the group's dashboard, which needs admin access to the group.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved-searches"
+                ],
+                "summary": "Create a saved search",
+                "parameters": [
+                    {
+                        "description": "Saved search",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/links.CreateSavedSearchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/links.SavedSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Role doesn't allow sharing or pinning in the group",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/saved-searches/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a saved search owned by the user or shared with one of their groups",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved-searches"
+                ],
+                "summary": "Get a saved search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.SavedSearchResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a saved search. The owner or an admin of the group it is shared with can update it. Sharing it with a group needs a role that can edit the group's links, and pinning it needs admin access to the group.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved-searches"
+                ],
+                "summary": "Update a saved search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated saved search",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/links.UpdateSavedSearchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/links.SavedSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to edit this saved search, or to share or pin it in the group",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a saved search. The owner or an admin of the group it is shared with can delete it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved-searches"
+                ],
+                "summary": "Delete a saved search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Saved search deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to delete this saved search",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/saved-searches/{id}/links": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the links matching a saved search. Results are limited to links the current user can see, so a shared search may return different links for different members.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved-searches"
+                ],
+                "summary": "Run a saved search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 50, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/links.LinkResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/auth.UserResponse"
+                }
+            }
+        },
+        "auth.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "auth.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "invite": {
+                    "description": "Optional group invite token to accept",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "auth.UserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "has_password": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "system_role": {
+                    "type": "string"
+                }
+            }
+        },
+        "collections.AddItemRequest": {
+            "type": "object",
+            "properties": {
+                "heading": {
+                    "description": "Required for section items",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "kind": {
+                    "description": "Defaults to link",
+                    "type": "string",
+                    "enum": [
+                        "link",
+                        "section"
+                    ]
+                },
+                "link_id": {
+                    "description": "Link items: the link's ID...",
+                    "type": "integer"
+                },
+                "link_slug": {
+                    "description": "...or its slug",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "position": {
+                    "description": "Zero-based; appended if omitted",
+                    "type": "integer"
+                }
+            }
+        },
+        "collections.CollectionItemResponse": {
+            "type": "object",
+            "properties": {
+                "heading": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "link": {
+                    "description": "Null for sections and for links that have been deleted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/collections.CollectionLink"
+                        }
+                    ]
+                },
+                "note": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "collections.CollectionLink": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "collections.CollectionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/collections.CollectionItemResponse"
+                    }
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "collections.CreateCollectionRequest": {
+            "type": "object",
+            "required": [
+                "group_id",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "slug": {
+                    "description": "Derived from the title if omitted",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 1
+                }
+            }
+        },
+        "collections.ReorderItemsRequest": {
+            "type": "object",
+            "required": [
+                "item_ids"
+            ],
+            "properties": {
+                "item_ids": {
+                    "description": "Every item ID, in the new order",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "collections.UpdateCollectionRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 1
+                }
+            }
+        },
+        "collections.UpdateItemRequest": {
+            "type": "object",
+            "properties": {
+                "heading": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 1
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 10000
+                }
+            }
+        },
+        "comments.CommentResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/comments.CommentUser"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link_id": {
+                    "type": "integer"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/comments.CommentUser"
+                    }
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/comments.CommentResponse"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "comments.CommentUser": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "comments.CreateCommentRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "minLength": 1
+                },
+                "parent_id": {
+                    "description": "Comment being replied to",
+                    "type": "integer"
+                }
+            }
+        },
+        "comments.UpdateCommentRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "minLength": 1
+                }
+            }
+        },
+        "groups.CreateGroupRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "Optional slug prefix owned by the group, e.g. \"sre\"",
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "Optional - defaults to org from context or global",
+                    "type": "integer"
+                },
+                "parent_id": {
+                    "description": "Optional group to nest under; its members become members of this group",
+                    "type": "integer"
+                }
+            }
+        },
+        "groups.CreateInviteRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Default 7 days from now",
+                    "type": "string"
+                },
+                "max_uses": {
+                    "description": "Invite links only; 0 means unlimited",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "role": {
+                    "description": "Default member",
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "editor",
+                        "member",
+                        "contributor",
+                        "viewer"
+                    ]
+                }
+            }
+        },
+        "groups.CreateInviteResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "token_prefix": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "uses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "groups.GroupResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inherited": {
+                    "description": "Membership comes from a group above this one",
+                    "type": "boolean"
+                },
+                "is_public_page": {
+                    "type": "boolean"
+                },
+                "member_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "public_slug": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "User's effective role in this group",
+                    "type": "string"
+                }
+            }
+        },
+        "groups.InviteResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "token_prefix": {
+                    "type": "string"
+                },
+                "uses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "groups.LinkPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "allowed_domains": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "default_expiry_days": {
+                    "type": "integer"
+                },
+                "default_is_public": {
+                    "type": "boolean"
+                },
+                "default_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "require_title": {
+                    "type": "boolean"
+                },
+                "slug_prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "groups.UpdateGroupRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "is_public_page": {
+                    "description": "Turns the public page on or off; needs a public_slug",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "An empty string releases the namespace",
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "Group to nest under; 0 makes it a top-level group",
+                    "type": "integer"
+                },
+                "public_slug": {
+                    "description": "Path of the public page, e.g. \"sre\" for /g/sre",
+                    "type": "string"
+                }
+            }
+        },
+        "groups.UpdateLinkPolicyRequest": {
+            "type": "object",
+            "properties": {
+                "allowed_domains": {
+                    "description": "Destination domains, including subdomains; empty allows any",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "default_expiry_days": {
+                    "description": "0 means new links don't expire",
+                    "type": "integer",
+                    "maximum": 3650,
+                    "minimum": 0
+                },
+                "default_is_public": {
+                    "description": "Visibility of new links that don't choose one",
+                    "type": "boolean"
+                },
+                "default_tags": {
+                    "description": "Tags added to every new link",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "require_title": {
+                    "type": "boolean"
+                },
+                "slug_prefix": {
+                    "description": "New slugs must start with this, e.g. \"sre-\"",
+                    "type": "string"
+                }
+            }
+        },
+        "links.BulkItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "links.BulkRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "delete",
+                        "move",
+                        "add_tags",
+                        "remove_tags",
+                        "set_public",
+                        "set_unread",
+                        "set_expiry"
+                    ]
+                },
+                "atomic": {
+                    "description": "Roll back every item if any item fails",
+                    "type": "boolean"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "description": "Expiry for set_expiry (null clears it)",
+                    "type": "string"
+                },
+                "group_id": {
+                    "description": "Target group for move",
+                    "type": "integer"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "on_conflict": {
+                    "description": "Slug conflict strategy for move (default fail)",
+                    "type": "string",
+                    "enum": [
+                        "skip",
+                        "rename",
+                        "fail"
+                    ]
+                },
+                "query": {
+                    "$ref": "#/definitions/links.SearchParams"
+                },
+                "slugs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "description": "Tags for add_tags/remove_tags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "value": {
+                    "description": "Value for set_public/set_unread",
+                    "type": "boolean"
+                }
+            }
+        },
+        "links.BulkResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/links.BulkItemResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "links.ChangeRequestResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "description": "Link is the created or updated link. Only set when approving.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/links.LinkResponse"
+                        }
+                    ]
+                },
+                "link_id": {
+                    "description": "The link being updated, or the created link once approved",
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "description": "The requested create or update",
+                    "type": "object"
+                },
+                "protected_slug_id": {
+                    "type": "integer"
+                },
+                "requested_by_id": {
+                    "type": "integer"
+                },
+                "review_comment": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by_id": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "links.CreateGrantRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "links.CreateLinkRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Markdown, required for pages",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Defaults to the group's default expiry",
+                    "type": "string"
+                },
+                "is_public": {
+                    "description": "Defaults to the group's default visibility",
+                    "type": "boolean"
+                },
+                "is_unread": {
+                    "type": "boolean"
+                },
+                "kind": {
+                    "description": "Defaults to redirect",
+                    "type": "string",
+                    "enum": [
+                        "redirect",
+                        "page"
+                    ]
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Required for redirects",
+                    "type": "string"
+                }
+            }
+        },
+        "links.CreateSavedSearchRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "group_id": {
+                    "description": "Share with this group's members",
+                    "type": "integer"
+                },
+                "is_pinned": {
+                    "description": "Show on the group's dashboard (requires group_id)",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "params": {
+                    "$ref": "#/definitions/links.SearchParams"
+                }
+            }
+        },
+        "links.DuplicateCluster": {
+            "type": "object",
+            "properties": {
+                "canonical_url": {
+                    "type": "string"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/links.LinkResponse"
+                    }
+                },
+                "organization_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "links.DuplicateLink": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "links.GrantResponse": {
+            "type": "object",
             "properties": {
-                "token": {
+                "created_at": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/auth.UserResponse"
+                "granted_by_id": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "group_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_email": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_name": {
+                    "type": "string"
                 }
             }
         },
-        "auth.ChangePasswordRequest": {
+        "links.LinkEventResponse": {
             "type": "object",
-            "required": [
-                "current_password",
-                "new_password"
-            ],
             "properties": {
-                "current_password": {
+                "action": {
                     "type": "string"
                 },
-                "new_password": {
-                    "type": "string",
-                    "minLength": 8
+                "actor_id": {
+                    "type": "integer"
+                },
+                "actor_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
                 }
             }
         },
-        "auth.LoginRequest": {
+        "links.LinkResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "alias_of_id": {
+                    "type": "integer"
+                },
+                "click_count": {
+                    "type": "integer"
+                },
+                "comment_count": {
+                    "type": "integer"
+                },
+                "content": {
                     "type": "string"
                 },
-                "password": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duplicates": {
+                    "description": "Duplicates lists existing links in the organization with the same\ncanonical URL. Only set when creating a link.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/links.DuplicateLink"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "favicon_url": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "is_unread": {
+                    "type": "boolean"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "auth.RegisterRequest": {
+        "links.LinkStateResponse": {
+            "type": "object",
+            "properties": {
+                "is_favorite": {
+                    "type": "boolean"
+                },
+                "is_pinned": {
+                    "type": "boolean"
+                },
+                "is_unread": {
+                    "description": "The user's own status, or the group-level default if unset",
+                    "type": "boolean"
+                },
+                "link_id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "pinned_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "links.MergeDuplicatesRequest": {
             "type": "object",
             "required": [
-                "email",
-                "name",
-                "password"
+                "link_ids",
+                "target_id"
             ],
             "properties": {
-                "email": {
+                "link_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "target_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "links.MergeDuplicatesResponse": {
+            "type": "object",
+            "properties": {
+                "merged": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/links.LinkResponse"
+                    }
+                },
+                "target": {
+                    "$ref": "#/definitions/links.LinkResponse"
+                }
+            }
+        },
+        "links.MyLinkResponse": {
+            "type": "object",
+            "properties": {
+                "alias_of_id": {
+                    "type": "integer"
+                },
+                "click_count": {
+                    "type": "integer"
+                },
+                "comment_count": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duplicates": {
+                    "description": "Duplicates lists existing links in the organization with the same\ncanonical URL. Only set when creating a link.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/links.DuplicateLink"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "favicon_url": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_favorite": {
+                    "type": "boolean"
+                },
+                "is_pinned": {
+                    "type": "boolean"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "is_unread": {
+                    "type": "boolean"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "links.PinnedSearchResponse": {
+            "type": "object",
+            "properties": {
+                "can_edit": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_pinned": {
+                    "type": "boolean"
+                },
+                "link_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "params": {
+                    "$ref": "#/definitions/links.SearchParams"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "links.RefreshMetadataRequest": {
+            "type": "object",
+            "properties": {
+                "overwrite": {
+                    "description": "Replace an existing title and description",
+                    "type": "boolean"
+                }
+            }
+        },
+        "links.ReviewChangeRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 10000
+                }
+            }
+        },
+        "links.RevisionResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "edited_by_id": {
+                    "type": "integer"
+                },
+                "edited_by_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "links.SavedSearchResponse": {
+            "type": "object",
+            "properties": {
+                "can_edit": {
+                    "type": "boolean"
+                },
+                "created_at": {
                     "type": "string"
                 },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_pinned": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "params": {
+                    "$ref": "#/definitions/links.SearchParams"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "auth.UserResponse": {
+        "links.SearchParams": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "group_id": {
+                    "type": "integer"
                 },
-                "has_password": {
+                "is_public": {
                     "type": "boolean"
                 },
-                "id": {
-                    "type": "integer"
+                "is_unread": {
+                    "type": "boolean"
                 },
-                "name": {
+                "q": {
                     "type": "string"
                 },
-                "system_role": {
+                "tag": {
+                    "type": "string"
+                },
+                "tag_query": {
+                    "description": "Boolean tag expression, see tagquery.Parse",
                     "type": "string"
                 }
             }
         },
-        "groups.CreateGroupRequest": {
+        "links.TransferRequest": {
             "type": "object",
             "required": [
-                "name"
+                "target_group_id"
             ],
             "properties": {
-                "description": {
-                    "type": "string"
+                "mode": {
+                    "description": "Defaults to move",
+                    "type": "string",
+                    "enum": [
+                        "move",
+                        "copy"
+                    ]
                 },
-                "name": {
-                    "type": "string"
+                "on_conflict": {
+                    "description": "Defaults to fail",
+                    "type": "string",
+                    "enum": [
+                        "skip",
+                        "rename",
+                        "fail"
+                    ]
                 },
-                "organization_id": {
-                    "description": "Optional - defaults to org from context or global",
+                "target_group_id": {
                     "type": "integer"
                 }
             }
         },
-        "groups.GroupResponse": {
+        "links.TransferResponse": {
             "type": "object",
             "properties": {
-                "description": {
+                "mode": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/links.TransferResult"
+                    }
                 },
-                "member_count": {
+                "skipped": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "target_group_id": {
+                    "type": "integer"
                 },
-                "role": {
-                    "description": "User's role in this group",
-                    "type": "string"
+                "transferred": {
+                    "type": "integer"
                 }
             }
         },
-        "groups.UpdateGroupRequest": {
+        "links.TransferResult": {
             "type": "object",
             "properties": {
-                "description": {
+                "id": {
+                    "type": "integer"
+                },
+                "new_id": {
+                    "type": "integer"
+                },
+                "new_slug": {
                     "type": "string"
                 },
-                "name": {
+                "slug": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "links.CreateLinkRequest": {
+        "links.UpdateLinkRequest": {
             "type": "object",
-            "required": [
-                "url"
-            ],
             "properties": {
+                "clear_expiry": {
+                    "description": "Removes the expiry; can't be combined with expires_at",
+                    "type": "boolean"
+                },
+                "content": {
+                    "description": "Pages only",
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
+                "expires_at": {
+                    "type": "string"
+                },
                 "is_public": {
                     "type": "boolean"
                 },
@@ -1422,66 +5903,40 @@ const docTemplate = `{
                 }
             }
         },
-        "links.LinkResponse": {
+        "links.UpdateLinkStateRequest": {
             "type": "object",
             "properties": {
-                "click_count": {
-                    "type": "integer"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "group_id": {
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "integer"
+                "is_favorite": {
+                    "type": "boolean"
                 },
-                "is_public": {
+                "is_pinned": {
                     "type": "boolean"
                 },
                 "is_unread": {
                     "type": "boolean"
                 },
-                "slug": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "url": {
-                    "type": "string"
+                "notes": {
+                    "type": "string",
+                    "maxLength": 10000
                 }
             }
         },
-        "links.UpdateLinkRequest": {
+        "links.UpdateSavedSearchRequest": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "is_public": {
-                    "type": "boolean"
+                "group_id": {
+                    "type": "integer"
                 },
-                "is_unread": {
+                "is_pinned": {
                     "type": "boolean"
                 },
-                "slug": {
+                "name": {
                     "type": "string",
-                    "maxLength": 50,
+                    "maxLength": 100,
                     "minLength": 1
                 },
-                "title": {
-                    "type": "string"
-                },
-                "url": {
-                    "type": "string"
+                "params": {
+                    "$ref": "#/definitions/links.SearchParams"
                 }
             }
         },
@@ -1523,6 +5978,20 @@ const docTemplate = `{
                 }
             }
         },
+        "organizations.DepartureResponse": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ownership.Outcome"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
         "organizations.MemberResponse": {
             "type": "object",
             "properties": {
@@ -1552,6 +6021,12 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "departure_owner_id": {
+                    "type": "integer"
+                },
+                "departure_policy": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -1564,12 +6039,75 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "reserved_namespaces": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "role": {
                     "description": "User's role in this org",
                     "type": "string"
                 },
                 "slug": {
                     "type": "string"
+                },
+                "slug_length": {
+                    "type": "integer"
+                },
+                "slug_strategy": {
+                    "type": "string"
+                },
+                "tracking_params": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "organizations.ProtectedSlugRequest": {
+            "type": "object",
+            "required": [
+                "pattern"
+            ],
+            "properties": {
+                "approver_ids": {
+                    "description": "Org members who approve changes; org admins if empty",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "pattern": {
+                    "description": "A slug, or a pattern such as \"security*\"",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                }
+            }
+        },
+        "organizations.ProtectedSlugResponse": {
+            "type": "object",
+            "properties": {
+                "approvers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/organizations.MemberResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pattern": {
+                    "type": "string"
                 }
             }
         },
@@ -1591,10 +6129,141 @@ const docTemplate = `{
         "organizations.UpdateOrgRequest": {
             "type": "object",
             "properties": {
+                "departure_owner_id": {
+                    "description": "Member who receives transferred links; 0 for the admins of each link's group",
+                    "type": "integer"
+                },
+                "departure_policy": {
+                    "description": "What happens to a departing member's links",
+                    "type": "string",
+                    "enum": [
+                        "transfer",
+                        "keep",
+                        "delete"
+                    ]
+                },
                 "name": {
                     "type": "string",
                     "maxLength": 100,
                     "minLength": 1
+                },
+                "reserved_namespaces": {
+                    "description": "Slug namespaces no group may claim",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "slug_length": {
+                    "description": "Length of random slugs",
+                    "type": "integer",
+                    "maximum": 50,
+                    "minimum": 4
+                },
+                "slug_strategy": {
+                    "type": "string",
+                    "enum": [
+                        "random",
+                        "words",
+                        "title",
+                        "sequential"
+                    ]
+                },
+                "tracking_params": {
+                    "description": "Extra query params ignored for duplicate detection; \"prefix_*\" matches a prefix",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "ownership.Outcome": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "transfer, keep or delete",
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "link_id": {
+                    "type": "integer"
+                },
+                "new_owner_id": {
+                    "description": "Set when the link is transferred",
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "stale.StaleLinkResponse": {
+            "type": "object",
+            "properties": {
+                "archive_after": {
+                    "description": "When the link will be archived if it is still stale",
+                    "type": "string"
+                },
+                "click_count": {
+                    "type": "integer"
+                },
+                "created_by_id": {
+                    "type": "integer"
+                },
+                "creator_active": {
+                    "type": "boolean"
+                },
+                "creator_email": {
+                    "description": "Empty if the creator has been deleted",
+                    "type": "string"
+                },
+                "fetch_status": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "group_name": {
+                    "type": "string"
+                },
+                "last_clicked_at": {
+                    "type": "string"
+                },
+                "link_created_at": {
+                    "type": "string"
+                },
+                "link_id": {
+                    "type": "integer"
+                },
+                "notified_at": {
+                    "description": "When the group's admins were notified",
+                    "type": "string"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         }
@@ -1,24 +1,33 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/admin"
 	"github.com/mikepea/shorty/pkg/shorty/apikeys"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/collections"
+	"github.com/mikepea/shorty/pkg/shorty/comments"
 	"github.com/mikepea/shorty/pkg/shorty/database"
 	"github.com/mikepea/shorty/pkg/shorty/groups"
 	"github.com/mikepea/shorty/pkg/shorty/importexport"
+	"github.com/mikepea/shorty/pkg/shorty/landing"
 	"github.com/mikepea/shorty/pkg/shorty/links"
+	"github.com/mikepea/shorty/pkg/shorty/mail"
 	"github.com/mikepea/shorty/pkg/shorty/models"
 	"github.com/mikepea/shorty/pkg/shorty/oidc"
 	"github.com/mikepea/shorty/pkg/shorty/organizations"
 	"github.com/mikepea/shorty/pkg/shorty/redirect"
 	"github.com/mikepea/shorty/pkg/shorty/scim"
+	"github.com/mikepea/shorty/pkg/shorty/slugs"
+	"github.com/mikepea/shorty/pkg/shorty/stale"
 	"github.com/mikepea/shorty/pkg/shorty/tags"
+	"github.com/mikepea/shorty/pkg/shorty/urlcanon"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
@@ -78,14 +87,48 @@ func main() {
 		log.Fatalf("Failed to ensure admin user exists: %v", err)
 	}
 
+	// Compute canonical URL hashes for links created before duplicate detection
+	if err := urlcanon.Backfill(database.GetDB()); err != nil {
+		log.Printf("Warning: Error backfilling canonical URL hashes: %v", err)
+	}
+
+	// Split tags shared across organizations before tags were scoped to one
+	if err := tags.ScopeToOrganizations(database.GetDB()); err != nil {
+		log.Fatalf("Failed to scope tags to organizations: %v", err)
+	}
+
+	// Namespaces reserved since a group claimed them hide that group's links
+	if claims, err := slugs.ReservedClaims(database.GetDB()); err != nil {
+		log.Printf("Warning: Error checking group namespaces: %v", err)
+	} else {
+		for _, group := range claims {
+			log.Printf("Warning: Group %d (%s) claims the reserved namespace %q, so its %s/ links can't be reached; give the group another namespace",
+				group.ID, group.Name, group.Namespace, group.Namespace)
+		}
+	}
+
 	// Get base URL from environment or use default
 	baseURL := os.Getenv("SHORTY_BASE_URL")
 	if baseURL == "" {
 		baseURL = "http://localhost:8080"
 	}
 
+	// Links added before their insert time was tracked are idle since their last update
+	if err := stale.BackfillTrackedSince(database.GetDB()); err != nil {
+		log.Printf("Warning: Error backfilling link tracking times: %v", err)
+	}
+
+	// Notify group admins about stale links and archive them after a grace period
+	if os.Getenv("SHORTY_STALE_LINKS") != "false" {
+		staleJob := stale.NewJob(database.GetDB(), mail.FromEnv(), baseURL)
+		go staleJob.Run(context.Background(), 24*time.Hour)
+	}
+
 	// Set up Gin router
 	r := gin.Default()
+	// Match routes against the raw path so namespaced slugs can be passed to
+	// the API with an escaped slash, e.g. /api/links/sre%2Frunbook
+	r.UseRawPath = true
 
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
@@ -124,6 +167,11 @@ func main() {
 		orgsGroup.Use(combinedAuth)
 		orgsHandler.RegisterRoutes(orgsGroup)
 		orgsHandler.RegisterMemberRoutes(orgsGroup)
+		orgsHandler.RegisterProtectedSlugRoutes(orgsGroup)
+
+		// Stale link report (protected - accepts JWT or API key)
+		staleHandler := stale.NewHandler(database.GetDB())
+		staleHandler.RegisterRoutes(orgsGroup)
 
 		// Groups routes (protected - accepts JWT or API key)
 		groupsHandler := groups.NewHandler(database.GetDB())
@@ -132,6 +180,11 @@ func main() {
 		groupsHandler.RegisterRoutes(groupsGroup)
 		groupsHandler.RegisterMemberRoutes(groupsGroup)
 
+		// Group invites (protected - accepts JWT or API key)
+		invitesHandler := groups.NewInviteHandler(database.GetDB(), mail.FromEnv(), baseURL)
+		invitesHandler.RegisterRoutes(groupsGroup)
+		invitesHandler.RegisterAcceptRoutes(api.Group("", combinedAuth))
+
 		// Links routes (protected - accepts JWT or API key)
 		linksHandler := links.NewHandler(database.GetDB())
 		linksHandler.RegisterRoutes(api.Group("", combinedAuth))
@@ -139,6 +192,15 @@ func main() {
 		// Tags routes (protected - accepts JWT or API key)
 		tagsHandler := tags.NewHandler(database.GetDB())
 		tagsHandler.RegisterRoutes(api.Group("", combinedAuth))
+		tagsHandler.RegisterOrgRoutes(orgsGroup)
+
+		// Comments routes (protected - accepts JWT or API key)
+		commentsHandler := comments.NewHandler(database.GetDB())
+		commentsHandler.RegisterRoutes(api.Group("", combinedAuth))
+
+		// Collections routes (protected - accepts JWT or API key)
+		collectionsHandler := collections.NewHandler(database.GetDB())
+		collectionsHandler.RegisterRoutes(api.Group("", combinedAuth))
 
 		// Import/Export routes (protected - accepts JWT or API key)
 		importExportHandler := importexport.NewHandler(database.GetDB())
@@ -206,6 +268,14 @@ func main() {
 		log.Println("No frontend build found at ./web/dist - API only mode")
 	}
 
+	// Public collection pages (must be registered before redirects)
+	publicCollectionsHandler := collections.NewHandler(database.GetDB())
+	publicCollectionsHandler.RegisterPublicRoutes(r)
+
+	// Public tag and group pages (must be registered before redirects)
+	landingHandler := landing.NewHandler(database.GetDB())
+	landingHandler.RegisterPublicRoutes(r)
+
 	// Redirect routes (public, must be registered LAST to avoid conflicts)
 	redirectHandler := redirect.NewHandler(database.GetDB())
 	redirectHandler.RegisterRoutes(r)
@@ -0,0 +1,68 @@
+// Package access decides who can see and change a link. Members of the group
+// that owns a link, or of a group above it, have the access their group role
+// allows (see Authorize); link grants share a single link with other users
+// and groups as a viewer or editor.
+package access
+
+import (
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// GrantedLinks returns a subquery selecting the IDs of links shared with the
+// user, directly or through one of their groups. With GrantRoleEditor only
+// editor grants count.
+func GrantedLinks(db *gorm.DB, userID uint, role models.GrantRole) *gorm.DB {
+	query := db.Model(&models.LinkGrant{}).Select("link_id").
+		Where("user_id = ? OR group_id IN (?)", userID, MemberGroups(db, userID))
+	if role == models.GrantRoleEditor {
+		query = query.Where("role = ?", models.GrantRoleEditor)
+	}
+	return query
+}
+
+// Visible returns a scope limiting a links query to links owned by the user's
+// groups or shared with them
+func Visible(db *gorm.DB, userID uint) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		return query.Where("links.group_id IN (?) OR links.id IN (?)",
+			MemberGroups(db, userID), GrantedLinks(db, userID, models.GrantRoleViewer))
+	}
+}
+
+// Role returns the user's access to a link: editor for members of its group
+// or a group above it whose role lets them change it and holders of an
+// editor grant, viewer for other members and viewer grants, or "" for none.
+// Public links are readable by everyone, but that isn't a role.
+func Role(db *gorm.DB, userID uint, link *models.Link) models.GrantRole {
+	var role models.GrantRole
+	switch Authorize(db, userID, link.GroupID, EditAction(userID, link)) {
+	case nil:
+		return models.GrantRoleEditor
+	case ErrForbidden:
+		role = models.GrantRoleViewer
+	}
+
+	var grants []models.LinkGrant
+	db.Where("link_id = ?", link.ID).
+		Where("user_id = ? OR group_id IN (?)", userID, MemberGroups(db, userID)).
+		Find(&grants)
+
+	for _, g := range grants {
+		if g.Role == models.GrantRoleEditor {
+			return models.GrantRoleEditor
+		}
+		role = models.GrantRoleViewer
+	}
+	return role
+}
+
+// CanView reports whether the user can see a link, including public links
+func CanView(db *gorm.DB, userID uint, link *models.Link) bool {
+	return link.IsPublic || Role(db, userID, link) != ""
+}
+
+// CanEdit reports whether the user can change a link
+func CanEdit(db *gorm.DB, userID uint, link *models.Link) bool {
+	return Role(db, userID, link) == models.GrantRoleEditor
+}
@@ -0,0 +1,68 @@
+package access
+
+import (
+	"errors"
+
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// Action is something a user does in a group
+type Action string
+
+const (
+	ActionView    Action = "view"     // See the group and its links
+	ActionCreate  Action = "create"   // Add links to the group
+	ActionEditOwn Action = "edit_own" // Change or delete a link they created
+	ActionEdit    Action = "edit"     // Change or delete any link in the group
+	ActionManage  Action = "manage"   // Change the group and its members
+)
+
+var (
+	// ErrNotMember is returned when the user isn't a member of the group
+	ErrNotMember = errors.New("not a member of this group")
+	// ErrForbidden is returned when the user's role doesn't allow the action
+	ErrForbidden = errors.New("your role in this group doesn't allow this")
+)
+
+// roleActions lists what each group role allows
+var roleActions = map[models.GroupRole][]Action{
+	models.GroupRoleViewer:      {ActionView},
+	models.GroupRoleContributor: {ActionView, ActionCreate, ActionEditOwn},
+	models.GroupRoleMember:      {ActionView, ActionCreate, ActionEditOwn, ActionEdit},
+	models.GroupRoleEditor:      {ActionView, ActionCreate, ActionEditOwn, ActionEdit},
+	models.GroupRoleAdmin:       {ActionView, ActionCreate, ActionEditOwn, ActionEdit, ActionManage},
+}
+
+// Allows reports whether a group role allows an action
+func Allows(role models.GroupRole, action Action) bool {
+	for _, a := range roleActions[role] {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize checks the user's effective role in a group, which may come from
+// a group above it, allows an action. It returns ErrNotMember or ErrForbidden
+// if not. Every group permission check goes through here.
+func Authorize(db *gorm.DB, userID, groupID uint, action Action) error {
+	role := GroupRole(db, userID, groupID)
+	if role == "" {
+		return ErrNotMember
+	}
+	if !Allows(role, action) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// EditAction returns the action changing a link is for the user: editing
+// their own link or someone else's
+func EditAction(userID uint, link *models.Link) Action {
+	if link.CreatedByID == userID {
+		return ActionEditOwn
+	}
+	return ActionEdit
+}
@@ -0,0 +1,90 @@
+package access
+
+import (
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// Groups nest: a group's parent_id points at the group above it. Members of
+// a group are members of every group below it, with the same role, so an
+// admin of Engineering is an admin of Engineering/SRE. The hierarchy is walked
+// with recursive queries; UNION drops rows already seen, so even a cycle in
+// the data can't make them loop forever.
+
+// MemberGroups returns a subquery selecting the IDs of the groups the user
+// belongs to, directly or through a group above them
+func MemberGroups(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Raw(`WITH RECURSIVE member_groups(id) AS (
+		SELECT group_id FROM group_memberships WHERE user_id = ? AND deleted_at IS NULL
+		UNION
+		SELECT groups.id FROM groups JOIN member_groups ON groups.parent_id = member_groups.id WHERE groups.deleted_at IS NULL
+	) SELECT id FROM member_groups`, userID)
+}
+
+// Subgroups returns a subquery selecting the IDs of the group and every group
+// below it
+func Subgroups(db *gorm.DB, groupID uint) *gorm.DB {
+	return db.Raw(`WITH RECURSIVE subgroups(id) AS (
+		SELECT ?
+		UNION
+		SELECT groups.id FROM groups JOIN subgroups ON groups.parent_id = subgroups.id WHERE groups.deleted_at IS NULL
+	) SELECT id FROM subgroups`, groupID)
+}
+
+// ancestors returns a subquery selecting the IDs of the group and every group
+// above it
+func ancestors(db *gorm.DB, groupID uint) *gorm.DB {
+	return db.Raw(`WITH RECURSIVE ancestors(id) AS (
+		SELECT ?
+		UNION
+		SELECT groups.parent_id FROM groups JOIN ancestors ON groups.id = ancestors.id WHERE groups.parent_id IS NOT NULL AND groups.deleted_at IS NULL
+	) SELECT id FROM ancestors`, groupID)
+}
+
+// GroupRole returns the user's effective role in a group: the role allowing
+// the most they hold in the group or any group above it, or "" if they
+// aren't a member
+func GroupRole(db *gorm.DB, userID, groupID uint) models.GroupRole {
+	var roles []models.GroupRole
+	db.Model(&models.GroupMembership{}).
+		Where("user_id = ? AND group_id IN (?)", userID, ancestors(db, groupID)).
+		Pluck("role", &roles)
+
+	var role models.GroupRole
+	for _, r := range roles {
+		if role == "" || len(roleActions[r]) > len(roleActions[role]) {
+			role = r
+		}
+	}
+	return role
+}
+
+// IsGroupAdmin reports whether the user is an admin of the group or of a
+// group above it
+func IsGroupAdmin(db *gorm.DB, userID, groupID uint) bool {
+	return Authorize(db, userID, groupID, ActionManage) == nil
+}
+
+// GroupAdmins returns a subquery selecting the IDs of the users who are
+// admins of the group or of a group above it
+func GroupAdmins(db *gorm.DB, groupID uint) *gorm.DB {
+	return db.Model(&models.GroupMembership{}).Select("user_id").
+		Where("role = ? AND group_id IN (?)", models.GroupRoleAdmin, ancestors(db, groupID))
+}
+
+// GroupIDs returns the IDs of every group the user belongs to, directly or
+// through a group above them
+func GroupIDs(db *gorm.DB, userID uint) ([]uint, error) {
+	var groupIDs []uint
+	if err := MemberGroups(db, userID).Scan(&groupIDs).Error; err != nil {
+		return nil, err
+	}
+	return groupIDs, nil
+}
+
+// IsSubgroup reports whether a group is the other group or below it
+func IsSubgroup(db *gorm.DB, groupID, ofGroupID uint) bool {
+	var count int64
+	db.Model(&models.Group{}).Where("id = ? AND id IN (?)", groupID, Subgroups(db, ofGroupID)).Count(&count)
+	return count > 0
+}
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
 	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/ownership"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -283,6 +285,57 @@ func TestDeleteUser(t *testing.T) {
 	}
 }
 
+func TestDeleteUserTransfersLinks(t *testing.T) {
+	db := setupTestDB(t)
+	r := setupTestRouter(db)
+	h := NewHandler(db)
+
+	admin := createTestUser(t, db, "admin@test.com", "Admin", models.SystemRoleAdmin)
+	user := createTestUser(t, db, "user@test.com", "Test User", models.SystemRoleUser)
+	teamLead := createTestUser(t, db, "lead@test.com", "Team Lead", models.SystemRoleUser)
+
+	group := createTestGroup(t, db, "Test Group")
+	db.Create(&models.GroupMembership{UserID: teamLead.ID, GroupID: group.ID, Role: models.GroupRoleAdmin})
+	link := createTestLink(t, db, user.ID, group.ID, "team-wiki")
+
+	withAdmin := func(handler gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			c.Set(auth.ContextKeyUserID, admin.ID)
+			c.Set(auth.ContextKeySystemRole, "admin")
+			handler(c)
+		}
+	}
+	r.GET("/admin/users/:id/departure-preview", withAdmin(h.PreviewDeleteUser))
+	r.DELETE("/admin/users/:id", withAdmin(h.DeleteUser))
+
+	req := httptest.NewRequest("GET", "/admin/users/"+strconv.FormatUint(uint64(user.ID), 10)+"/departure-preview", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var preview struct {
+		Links []ownership.Outcome `json:"links"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &preview)
+	if len(preview.Links) != 1 || preview.Links[0].Action != "transfer" || preview.Links[0].NewOwnerID != teamLead.ID {
+		t.Fatalf("Expected the link to be transferred to the group admin, got %+v", preview.Links)
+	}
+
+	req = httptest.NewRequest("DELETE", "/admin/users/"+strconv.FormatUint(uint64(user.ID), 10), nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var reloaded models.Link
+	if err := db.First(&reloaded, link.ID).Error; err != nil {
+		t.Fatal("Expected the link to survive its creator's deletion")
+	}
+	if reloaded.CreatedByID != teamLead.ID {
+		t.Errorf("Expected the link to belong to %d, got %d", teamLead.ID, reloaded.CreatedByID)
+	}
+}
+
 func TestDeleteUserCannotDeleteSelf(t *testing.T) {
 	db := setupTestDB(t)
 	r := setupTestRouter(db)
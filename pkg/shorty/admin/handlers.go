@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
 	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/ownership"
 	"gorm.io/gorm"
 )
 
@@ -185,7 +186,31 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 	})
 }
 
-// DeleteUser soft-deletes a user (admin only)
+// PreviewDeleteUser shows what deleting a user would do to their links (admin only)
+func (h *Handler) PreviewDeleteUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	outcomes, err := ownership.Preview(h.db, user.ID, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview links"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"links": outcomes})
+}
+
+// DeleteUser soft-deletes a user (admin only).
+// Their links are transferred, kept or deleted according to each organization's departure policy.
 func (h *Handler) DeleteUser(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -207,6 +232,7 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 	}
 
 	// Delete user and related data in a transaction
+	var outcomes []ownership.Outcome
 	err = h.db.Transaction(func(tx *gorm.DB) error {
 		// Delete API keys
 		if err := tx.Where("user_id = ?", user.ID).Delete(&models.APIKey{}).Error; err != nil {
@@ -216,8 +242,9 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 		if err := tx.Where("user_id = ?", user.ID).Delete(&models.GroupMembership{}).Error; err != nil {
 			return err
 		}
-		// Delete links
-		if err := tx.Where("created_by_id = ?", user.ID).Delete(&models.Link{}).Error; err != nil {
+		// Hand over links
+		var err error
+		if outcomes, err = ownership.Apply(tx, user.ID, 0); err != nil {
 			return err
 		}
 		// Delete user
@@ -229,7 +256,7 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully", "links": outcomes})
 }
 
 // GetStats returns system-wide statistics (admin only)
@@ -260,4 +287,5 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("/users/:id", h.GetUser)
 	rg.PUT("/users/:id", h.UpdateUser)
 	rg.DELETE("/users/:id", h.DeleteUser)
+	rg.GET("/users/:id/departure-preview", h.PreviewDeleteUser)
 }
@@ -178,6 +178,26 @@ func UpdateLastUsed(db *gorm.DB, apiKeyID uint) {
 	db.Model(&models.APIKey{}).Where("id = ?", apiKeyID).Update("last_used_at", now)
 }
 
+// UserIDForToken returns the user a bearer token belongs to, for handlers
+// outside the API that can't use CombinedAuthMiddleware. Like the middleware,
+// it accepts a JWT or an API key.
+func UserIDForToken(db *gorm.DB, token string) (uint, error) {
+	if strings.Contains(token, ".") {
+		claims, err := auth.ValidateToken(token)
+		if err != nil {
+			return 0, err
+		}
+		return claims.UserID, nil
+	}
+
+	apiKey, err := ValidateAPIKey(db, token)
+	if err != nil {
+		return 0, err
+	}
+	go UpdateLastUsed(db, apiKey.ID)
+	return apiKey.UserID, nil
+}
+
 // CombinedAuthMiddleware returns a middleware that authenticates via JWT or API key
 // Both are passed in the Authorization header as "Bearer <token>"
 // JWTs contain dots, API keys are hex strings without dots
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/invites"
 	"github.com/mikepea/shorty/pkg/shorty/models"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -144,6 +145,50 @@ func TestRegisterDuplicateEmail(t *testing.T) {
 	}
 }
 
+func TestRegisterWithInvite(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+
+	group := models.Group{Name: "SRE"}
+	db.Create(&group)
+	invite := models.GroupInvite{GroupID: group.ID, Email: "New@Example.com", Role: models.GroupRoleViewer, MaxUses: 1, CreatedByID: 1}
+	token, err := invites.Create(db, &invite)
+	if err != nil {
+		t.Fatalf("Failed to create invite: %v", err)
+	}
+
+	register := func(email, invite string) int {
+		jsonBody, _ := json.Marshal(RegisterRequest{Email: email, Password: "password123", Name: "New User", Invite: invite})
+		req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	if code := register("new@example.com", "not-a-token"); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid invite, got %d", code)
+	}
+	if code := register("new@example.com", token); code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", code)
+	}
+
+	var membership models.GroupMembership
+	if err := db.Joins("JOIN users ON users.id = group_memberships.user_id").
+		Where("users.email = ? AND group_memberships.group_id = ?", "new@example.com", group.ID).
+		First(&membership).Error; err != nil {
+		t.Fatalf("Expected the user to join the group: %v", err)
+	}
+	if membership.Role != models.GroupRoleViewer {
+		t.Errorf("Expected the invited role viewer, got %s", membership.Role)
+	}
+
+	// The email invite is used up
+	if code := register("other@example.com", token); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 reusing the invite, got %d", code)
+	}
+}
+
 func TestLogin(t *testing.T) {
 	db := setupTestDB(t)
 	router := setupTestRouter(db)
@@ -181,6 +226,31 @@ func TestLogin(t *testing.T) {
 	if response.Token == "" {
 		t.Error("Expected token in response")
 	}
+
+	// The session cookie carries the same token, for private pages
+	var session *http.Cookie
+	for _, cookie := range resp.Result().Cookies() {
+		if cookie.Name == SessionCookie {
+			session = cookie
+		}
+	}
+	if session == nil || session.Value != response.Token || !session.HttpOnly {
+		t.Errorf("Expected an HTTP-only session cookie with the token, got %+v", session)
+	}
+
+	// Logging out clears it
+	req, _ = http.NewRequest("POST", "/auth/logout", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	cleared := false
+	for _, cookie := range resp.Result().Cookies() {
+		if cookie.Name == SessionCookie && cookie.MaxAge < 0 {
+			cleared = true
+		}
+	}
+	if !cleared {
+		t.Error("Expected logout to clear the session cookie")
+	}
 }
 
 func TestLoginWrongPassword(t *testing.T) {
@@ -1,9 +1,11 @@
 package auth
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/invites"
 	"github.com/mikepea/shorty/pkg/shorty/models"
 	"gorm.io/gorm"
 )
@@ -23,6 +25,7 @@ type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
 	Name     string `json:"name" binding:"required"`
+	Invite   string `json:"invite"` // Optional group invite token to accept
 }
 
 // LoginRequest represents the login request body
@@ -70,6 +73,16 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
+	// Check the invite before creating the account
+	var invite *models.GroupInvite
+	if req.Invite != "" {
+		var err error
+		if invite, err = invites.Find(h.db, req.Invite); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invite is invalid, expired or revoked"})
+			return
+		}
+	}
+
 	// Check if email already exists
 	var existingUser models.User
 	if err := h.db.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
@@ -120,12 +133,21 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
+	// Join the group the user was invited to. Email addresses aren't
+	// verified here, so other invites for the address wait for the token.
+	if invite != nil {
+		if err := invites.Accept(h.db, invite, &user); err != nil {
+			log.Printf("Warning: Failed to accept invite for %s: %v", user.Email, err)
+		}
+	}
+
 	// Generate token
 	token, err := GenerateToken(user.ID, user.Email, string(user.SystemRole))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
+	SetSessionCookie(c, token)
 
 	c.JSON(http.StatusCreated, AuthResponse{
 		Token: token,
@@ -176,6 +198,7 @@ func (h *Handler) Login(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
+	SetSessionCookie(c, token)
 
 	c.JSON(http.StatusOK, AuthResponse{
 		Token: token,
@@ -222,12 +245,13 @@ func (h *Handler) Me(c *gin.Context) {
 
 // Logout handles user logout (client-side token invalidation)
 // @Summary Logout
-// @Description Logout the current user (client-side token invalidation)
+// @Description Logout the current user (client-side token invalidation). Clears the session cookie used to view private pages.
 // @Tags auth
 // @Produce json
 // @Success 200 {object} map[string]string "Logged out successfully"
 // @Router /auth/logout [post]
 func (h *Handler) Logout(c *gin.Context) {
+	ClearSessionCookie(c)
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
 
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionCookie holds the signed-in user's JWT. The web UI sends the token in
+// the Authorization header, which browsers can't add when following a link,
+// so the cookie lets the redirect handler show private pages to the same
+// session.
+const SessionCookie = "shorty_session"
+
+// SetSessionCookie stores a newly issued token in the session cookie
+func SetSessionCookie(c *gin.Context, token string) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookie, token, int(getTokenDuration().Seconds()), "/", "", isHTTPS(c), true)
+}
+
+// ClearSessionCookie removes the session cookie
+func ClearSessionCookie(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookie, "", -1, "/", "", isHTTPS(c), true)
+}
+
+// SessionUserID returns the user signed in through the session cookie
+func SessionUserID(c *gin.Context) (uint, bool) {
+	token, err := c.Cookie(SessionCookie)
+	if err != nil || token == "" {
+		return 0, false
+	}
+	claims, err := ValidateToken(token)
+	if err != nil {
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+// isHTTPS reports whether the request reached the server, or the proxy in
+// front of it, over TLS
+func isHTTPS(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
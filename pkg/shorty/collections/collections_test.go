@@ -0,0 +1,351 @@
+package collections
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	models.AutoMigrate(db)
+	return db
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, email string) models.User {
+	hash, _ := auth.HashPassword("password123")
+	user := models.User{
+		Email:        email,
+		PasswordHash: hash,
+		Name:         "Test User",
+		SystemRole:   models.SystemRoleUser,
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	return user
+}
+
+// createTestGroup creates a group in the global organization, which public
+// pages fall back to for unrecognized hosts
+func createTestGroup(t *testing.T, db *gorm.DB, name string, userID uint) models.Group {
+	var org models.Organization
+	if err := db.Where("is_global = ?", true).First(&org).Error; err != nil {
+		org = models.Organization{Name: "Shorty Global", Slug: "shorty-global", IsGlobal: true}
+		if err := db.Create(&org).Error; err != nil {
+			t.Fatalf("Failed to create global organization: %v", err)
+		}
+	}
+	group := models.Group{Name: name, OrganizationID: org.ID}
+	if err := db.Create(&group).Error; err != nil {
+		t.Fatalf("Failed to create test group: %v", err)
+	}
+	membership := models.GroupMembership{
+		UserID:  userID,
+		GroupID: group.ID,
+		Role:    models.GroupRoleAdmin,
+	}
+	if err := db.Create(&membership).Error; err != nil {
+		t.Fatalf("Failed to create test membership: %v", err)
+	}
+	return group
+}
+
+func createTestLink(t *testing.T, db *gorm.DB, group models.Group, userID uint, slug string, isPublic bool) models.Link {
+	link := models.Link{
+		OrganizationID: group.OrganizationID,
+		GroupID:        group.ID,
+		CreatedByID:    userID,
+		Slug:           slug,
+		URL:            "https://example.com/" + slug,
+		Title:          "Link " + slug,
+		IsPublic:       isPublic,
+	}
+	if err := db.Create(&link).Error; err != nil {
+		t.Fatalf("Failed to create test link: %v", err)
+	}
+	return link
+}
+
+func setupTestRouter(db *gorm.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handler := NewHandler(db)
+
+	api := r.Group("/api")
+	api.Use(auth.AuthMiddleware())
+	handler.RegisterRoutes(api)
+	handler.RegisterPublicRoutes(r)
+
+	return r
+}
+
+func getAuthHeader(user models.User) string {
+	token, _ := auth.GenerateToken(user.ID, user.Email, string(user.SystemRole))
+	return "Bearer " + token
+}
+
+func doRequest(router *gin.Engine, user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func createCollection(t *testing.T, router *gin.Engine, user models.User, req CreateCollectionRequest) CollectionResponse {
+	resp := doRequest(router, user, "POST", "/api/collections", req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var collection CollectionResponse
+	json.Unmarshal(resp.Body.Bytes(), &collection)
+	return collection
+}
+
+func addItem(t *testing.T, router *gin.Engine, user models.User, collectionID uint, req AddItemRequest) CollectionItemResponse {
+	resp := doRequest(router, user, "POST", fmt.Sprintf("/api/collections/%d/items", collectionID), req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var item CollectionItemResponse
+	json.Unmarshal(resp.Body.Bytes(), &item)
+	return item
+}
+
+func getCollection(t *testing.T, router *gin.Engine, user models.User, collectionID uint) CollectionResponse {
+	resp := doRequest(router, user, "GET", fmt.Sprintf("/api/collections/%d", collectionID), nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var collection CollectionResponse
+	json.Unmarshal(resp.Body.Bytes(), &collection)
+	return collection
+}
+
+func itemLabels(collection CollectionResponse) []string {
+	labels := make([]string, len(collection.Items))
+	for i, item := range collection.Items {
+		if item.Link != nil {
+			labels[i] = item.Link.Slug
+		} else {
+			labels[i] = "# " + item.Heading
+		}
+	}
+	return labels
+}
+
+func TestCreateCollection(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	outsider := createTestUser(t, db, "outsider@example.com")
+	group := createTestGroup(t, db, "Onboarding", user.ID)
+
+	collection := createCollection(t, router, user, CreateCollectionRequest{GroupID: group.ID, Title: "Day 1 links"})
+	if collection.Slug != "day-1-links" {
+		t.Errorf("Expected slug derived from the title, got %q", collection.Slug)
+	}
+
+	// Slugs are unique within the organization
+	resp := doRequest(router, user, "POST", "/api/collections", CreateCollectionRequest{GroupID: group.ID, Title: "Day 1 links"})
+	if resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", resp.Code)
+	}
+
+	// Only members of the owning group can see it
+	resp = doRequest(router, outsider, "GET", fmt.Sprintf("/api/collections/%d", collection.ID), nil)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+	resp = doRequest(router, outsider, "POST", "/api/collections", CreateCollectionRequest{GroupID: group.ID, Title: "Mine"})
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+}
+
+func TestCollectionItemsAndOrdering(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Onboarding", user.ID)
+	createTestLink(t, db, group, user.ID, "handbook", true)
+	laptop := createTestLink(t, db, group, user.ID, "laptop", true)
+
+	collection := createCollection(t, router, user, CreateCollectionRequest{GroupID: group.ID, Title: "Day 1"})
+	addItem(t, router, user, collection.ID, AddItemRequest{Kind: "section", Heading: "Morning"})
+	addItem(t, router, user, collection.ID, AddItemRequest{LinkSlug: "handbook", Note: "Read this first"})
+	addItem(t, router, user, collection.ID, AddItemRequest{LinkID: laptop.ID})
+	position := 0
+	intro := addItem(t, router, user, collection.ID, AddItemRequest{Kind: "section", Heading: "Welcome", Position: &position})
+
+	got := getCollection(t, router, user, collection.ID)
+	want := []string{"# Welcome", "# Morning", "handbook", "laptop"}
+	if strings.Join(itemLabels(got), ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected items %v, got %v", want, itemLabels(got))
+	}
+	if got.Items[2].Note != "Read this first" {
+		t.Errorf("Expected note on handbook, got %q", got.Items[2].Note)
+	}
+
+	// Reordering must list every item exactly once
+	ids := []uint{got.Items[1].ID, got.Items[3].ID, got.Items[2].ID}
+	resp := doRequest(router, user, "PUT", fmt.Sprintf("/api/collections/%d/order", collection.ID), ReorderItemsRequest{ItemIDs: ids})
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.Code)
+	}
+
+	ids = append(ids, got.Items[0].ID)
+	resp = doRequest(router, user, "PUT", fmt.Sprintf("/api/collections/%d/order", collection.ID), ReorderItemsRequest{ItemIDs: ids})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var reordered CollectionResponse
+	json.Unmarshal(resp.Body.Bytes(), &reordered)
+	want = []string{"# Morning", "laptop", "handbook", "# Welcome"}
+	if strings.Join(itemLabels(reordered), ",") != strings.Join(want, ",") {
+		t.Errorf("Expected items %v, got %v", want, itemLabels(reordered))
+	}
+
+	// Removing an item closes the gap
+	resp = doRequest(router, user, "DELETE", fmt.Sprintf("/api/collections/%d/items/%d", collection.ID, reordered.Items[1].ID), nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.Code)
+	}
+	got = getCollection(t, router, user, collection.ID)
+	for i, item := range got.Items {
+		if item.Position != i {
+			t.Errorf("Expected item %d at position %d, got %d", item.ID, i, item.Position)
+		}
+	}
+	if got.Items[2].ID != intro.ID {
+		t.Errorf("Expected Welcome last, got %v", itemLabels(got))
+	}
+}
+
+func TestCollectionHidesLinksUserCannotSee(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	owner := createTestUser(t, db, "owner@example.com")
+	member := createTestUser(t, db, "member@example.com")
+	group := createTestGroup(t, db, "Onboarding", owner.ID)
+	db.Create(&models.GroupMembership{UserID: member.ID, GroupID: group.ID, Role: models.GroupRoleMember})
+	secretGroup := createTestGroup(t, db, "Security", owner.ID)
+	createTestLink(t, db, secretGroup, owner.ID, "incident-runbook", false)
+
+	collection := createCollection(t, router, owner, CreateCollectionRequest{GroupID: group.ID, Title: "Day 1"})
+	addItem(t, router, owner, collection.ID, AddItemRequest{LinkSlug: "incident-runbook"})
+
+	if got := getCollection(t, router, owner, collection.ID); len(got.Items) != 1 {
+		t.Errorf("Expected owner to see 1 item, got %d", len(got.Items))
+	}
+	if got := getCollection(t, router, member, collection.ID); len(got.Items) != 0 {
+		t.Errorf("Expected private link to be hidden from member, got %v", itemLabels(got))
+	}
+
+	// Members can't add links they can't see
+	resp := doRequest(router, member, "POST", fmt.Sprintf("/api/collections/%d/items", collection.ID), AddItemRequest{LinkSlug: "incident-runbook"})
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+}
+
+func TestCollectionRoles(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	owner := createTestUser(t, db, "owner@example.com")
+	viewer := createTestUser(t, db, "viewer@example.com")
+	contributor := createTestUser(t, db, "contributor@example.com")
+	group := createTestGroup(t, db, "Onboarding", owner.ID)
+	db.Create(&models.GroupMembership{UserID: viewer.ID, GroupID: group.ID, Role: models.GroupRoleViewer})
+	db.Create(&models.GroupMembership{UserID: contributor.ID, GroupID: group.ID, Role: models.GroupRoleContributor})
+	createTestLink(t, db, group, owner.ID, "handbook", true)
+
+	collection := createCollection(t, router, owner, CreateCollectionRequest{GroupID: group.ID, Title: "Day 1"})
+	path := fmt.Sprintf("/api/collections/%d", collection.ID)
+
+	// Viewers can read collections but not create or change them
+	getCollection(t, router, viewer, collection.ID)
+	if resp := doRequest(router, viewer, "POST", "/api/collections", CreateCollectionRequest{GroupID: group.ID, Title: "Mine"}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 creating, got %d", resp.Code)
+	}
+	if resp := doRequest(router, viewer, "PUT", path, UpdateCollectionRequest{Title: "Changed"}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 updating, got %d", resp.Code)
+	}
+	if resp := doRequest(router, viewer, "POST", path+"/items", AddItemRequest{LinkSlug: "handbook"}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 adding an item, got %d", resp.Code)
+	}
+	if resp := doRequest(router, viewer, "DELETE", path, nil); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 deleting, got %d", resp.Code)
+	}
+
+	// Contributors change their own collections only
+	own := createCollection(t, router, contributor, CreateCollectionRequest{GroupID: group.ID, Title: "Contributor picks"})
+	addItem(t, router, contributor, own.ID, AddItemRequest{LinkSlug: "handbook"})
+	if resp := doRequest(router, contributor, "POST", path+"/items", AddItemRequest{LinkSlug: "handbook"}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 changing someone else's collection, got %d", resp.Code)
+	}
+}
+
+func TestPublicCollectionPage(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Onboarding", user.ID)
+	createTestLink(t, db, group, user.ID, "handbook", true)
+	createTestLink(t, db, group, user.ID, "payroll", false)
+
+	collection := createCollection(t, router, user, CreateCollectionRequest{GroupID: group.ID, Title: "Day 1", Slug: "day1"})
+	addItem(t, router, user, collection.ID, AddItemRequest{Kind: "section", Heading: "Reading <list>"})
+	addItem(t, router, user, collection.ID, AddItemRequest{LinkSlug: "handbook", Note: "Start here"})
+	addItem(t, router, user, collection.ID, AddItemRequest{Kind: "section", Heading: "Internal only"})
+	addItem(t, router, user, collection.ID, AddItemRequest{LinkSlug: "payroll"})
+
+	// Not shared yet
+	req, _ := http.NewRequest("GET", "/c/day1", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404 for a private collection, got %d", resp.Code)
+	}
+
+	isPublic := true
+	doRequest(router, user, "PUT", fmt.Sprintf("/api/collections/%d", collection.ID), UpdateCollectionRequest{IsPublic: &isPublic})
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.Code)
+	}
+
+	body := resp.Body.String()
+	for _, want := range []string{`<a href="/handbook">Link handbook</a>`, "Start here", "Reading &lt;list&gt;"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected page to contain %q", want)
+		}
+	}
+	// Private links, and sections left empty, are not shown
+	for _, unwanted := range []string{"payroll", "Internal only"} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("Expected page not to contain %q", unwanted)
+		}
+	}
+}
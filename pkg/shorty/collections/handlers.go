@@ -0,0 +1,741 @@
+package collections
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/slugs"
+	"gorm.io/gorm"
+)
+
+var slugRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Handler handles collection requests
+type Handler struct {
+	db *gorm.DB
+}
+
+// NewHandler creates a new collections handler
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// CreateCollectionRequest represents the request to create a collection
+type CreateCollectionRequest struct {
+	GroupID     uint   `json:"group_id" binding:"required"`
+	Title       string `json:"title" binding:"required,min=1,max=200"`
+	Slug        string `json:"slug" binding:"omitempty,max=50"` // Derived from the title if omitted
+	Description string `json:"description" binding:"max=10000"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+// UpdateCollectionRequest represents the request to update a collection.
+// Omitted fields are left unchanged.
+type UpdateCollectionRequest struct {
+	Title       string  `json:"title" binding:"omitempty,min=1,max=200"`
+	Slug        string  `json:"slug" binding:"omitempty,max=50"`
+	Description *string `json:"description" binding:"omitempty,max=10000"`
+	IsPublic    *bool   `json:"is_public"`
+}
+
+// AddItemRequest represents the request to add a link or section heading to a collection
+type AddItemRequest struct {
+	Kind     string `json:"kind" binding:"omitempty,oneof=link section"` // Defaults to link
+	LinkID   uint   `json:"link_id"`                                     // Link items: the link's ID...
+	LinkSlug string `json:"link_slug"`                                   // ...or its slug
+	Heading  string `json:"heading" binding:"max=200"`                   // Required for section items
+	Note     string `json:"note" binding:"max=10000"`
+	Position *int   `json:"position"` // Zero-based; appended if omitted
+}
+
+// UpdateItemRequest represents the request to edit a collection item.
+// Omitted fields are left unchanged.
+type UpdateItemRequest struct {
+	Heading *string `json:"heading" binding:"omitempty,min=1,max=200"`
+	Note    *string `json:"note" binding:"omitempty,max=10000"`
+}
+
+// ReorderItemsRequest represents the request to reorder a collection's items
+type ReorderItemsRequest struct {
+	ItemIDs []uint `json:"item_ids" binding:"required"` // Every item ID, in the new order
+}
+
+// CollectionLink represents a link referenced by a collection item
+type CollectionLink struct {
+	ID          uint   `json:"id"`
+	Slug        string `json:"slug"`
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+// CollectionItemResponse represents a collection item in API responses
+type CollectionItemResponse struct {
+	ID       uint            `json:"id"`
+	Position int             `json:"position"`
+	Kind     string          `json:"kind"`
+	Heading  string          `json:"heading,omitempty"`
+	Note     string          `json:"note,omitempty"`
+	Link     *CollectionLink `json:"link,omitempty"` // Null for sections and for links that have been deleted
+}
+
+// CollectionResponse represents a collection in API responses
+type CollectionResponse struct {
+	ID             uint                     `json:"id"`
+	OrganizationID uint                     `json:"organization_id"`
+	GroupID        uint                     `json:"group_id"`
+	CreatedByID    uint                     `json:"created_by_id"`
+	Slug           string                   `json:"slug"`
+	Title          string                   `json:"title"`
+	Description    string                   `json:"description"`
+	IsPublic       bool                     `json:"is_public"`
+	Items          []CollectionItemResponse `json:"items,omitempty"`
+	CreatedAt      string                   `json:"created_at"`
+	UpdatedAt      string                   `json:"updated_at"`
+}
+
+func collectionToResponse(collection models.Collection) CollectionResponse {
+	return CollectionResponse{
+		ID:             collection.ID,
+		OrganizationID: collection.OrganizationID,
+		GroupID:        collection.GroupID,
+		CreatedByID:    collection.CreatedByID,
+		Slug:           collection.Slug,
+		Title:          collection.Title,
+		Description:    collection.Description,
+		IsPublic:       collection.IsPublic,
+		CreatedAt:      collection.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:      collection.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func itemToResponse(item models.CollectionItem) CollectionItemResponse {
+	response := CollectionItemResponse{
+		ID:       item.ID,
+		Position: item.Position,
+		Kind:     item.Kind,
+		Heading:  item.Heading,
+		Note:     item.Note,
+	}
+	if item.Link != nil {
+		response.Link = &CollectionLink{
+			ID:          item.Link.ID,
+			Slug:        item.Link.Slug,
+			URL:         item.Link.URL,
+			Title:       item.Link.Title,
+			Description: item.Link.Description,
+			IsPublic:    item.Link.IsPublic,
+		}
+	}
+	return response
+}
+
+// getUserGroupIDs returns all group IDs the user is a member of, including
+// groups below the ones they belong to
+func (h *Handler) getUserGroupIDs(userID uint) ([]uint, error) {
+	return access.GroupIDs(h.db, userID)
+}
+
+// canViewLink reports whether the user can see a link: public links are visible
+// to everyone, private links to members of the link's group and grant holders
+func (h *Handler) canViewLink(userID uint, link *models.Link) bool {
+	return access.CanView(h.db, userID, link)
+}
+
+// validateSlug checks a collection slug is well-formed and unused in the organization.
+// Soft-deleted collections still hold their slug in the unique index.
+func (h *Handler) validateSlug(orgID uint, slug string, excludeID uint) (int, string) {
+	if slug == "" {
+		return http.StatusBadRequest, "Slug is required when the title contains no letters or numbers"
+	}
+	if !slugRegex.MatchString(slug) {
+		return http.StatusBadRequest, "Slug must contain only letters, numbers, hyphens, and underscores"
+	}
+
+	var count int64
+	query := h.db.Unscoped().Model(&models.Collection{}).Where("organization_id = ? AND slug = ?", orgID, slug)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return http.StatusInternalServerError, "Failed to check slug"
+	}
+	if count > 0 {
+		return http.StatusConflict, "This slug is already taken"
+	}
+	return 0, ""
+}
+
+// findCollection looks up a collection by the id path parameter and checks the
+// user's role in the group that owns it allows the action. Changing a
+// collection needs ActionEdit, or only ActionEditOwn for its creator.
+func (h *Handler) findCollection(c *gin.Context, userID uint, action access.Action) (*models.Collection, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return nil, false
+	}
+
+	var collection models.Collection
+	if err := h.db.First(&collection, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return nil, false
+	}
+
+	if action == access.ActionEdit && collection.CreatedByID == userID {
+		action = access.ActionEditOwn
+	}
+	switch access.Authorize(h.db, userID, collection.GroupID, action) {
+	case nil:
+	case access.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your role in this group doesn't allow changing this collection"})
+		return nil, false
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return nil, false
+	}
+
+	return &collection, true
+}
+
+// findItem looks up an item in a collection by the itemId path parameter
+func (h *Handler) findItem(c *gin.Context, collectionID uint) (*models.CollectionItem, bool) {
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return nil, false
+	}
+
+	var item models.CollectionItem
+	if err := h.db.Where("id = ? AND collection_id = ?", itemID, collectionID).First(&item).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return nil, false
+	}
+
+	return &item, true
+}
+
+// loadItems returns a collection's items in order with their links. Deleted
+// links are left unset so editors can see and remove the dangling entries.
+func (h *Handler) loadItems(collectionID uint) ([]models.CollectionItem, error) {
+	var items []models.CollectionItem
+	err := h.db.Preload("Link").Where("collection_id = ?", collectionID).Order("position, id").Find(&items).Error
+	return items, err
+}
+
+// List returns the collections in the user's groups
+// @Summary List collections
+// @Description Get the curated collections owned by the user's groups
+// @Tags collections
+// @Produce json
+// @Param group_id query int false "Only collections owned by this group"
+// @Success 200 {array} CollectionResponse
+// @Security BearerAuth
+// @Router /collections [get]
+func (h *Handler) List(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	groupIDs, err := h.getUserGroupIDs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
+		return
+	}
+
+	if len(groupIDs) == 0 {
+		c.JSON(http.StatusOK, []CollectionResponse{})
+		return
+	}
+
+	query := h.db.Where("group_id IN ?", groupIDs).Order("title, id")
+	if groupID, err := strconv.ParseUint(c.Query("group_id"), 10, 32); err == nil {
+		query = query.Where("group_id = ?", groupID)
+	}
+
+	var collections []models.Collection
+	if err := query.Find(&collections).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collections"})
+		return
+	}
+
+	responses := make([]CollectionResponse, len(collections))
+	for i, collection := range collections {
+		responses[i] = collectionToResponse(collection)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// Create creates a collection
+// @Summary Create a collection
+// @Description Create a curated, hand-ordered collection of links owned by a group. Public collections are rendered read-only at /c/{slug}.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param request body CreateCollectionRequest true "Collection"
+// @Success 201 {object} CollectionResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Role doesn't allow adding collections"
+// @Failure 404 {object} map[string]string "Group not found"
+// @Failure 409 {object} map[string]string "Slug already taken"
+// @Security BearerAuth
+// @Router /collections [post]
+func (h *Handler) Create(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	var req CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check the user's role lets them add collections
+	switch access.Authorize(h.db, userID, req.GroupID, access.ActionCreate) {
+	case nil:
+	case access.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your role in this group doesn't allow adding collections"})
+		return
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	var group models.Group
+	if err := h.db.First(&group, req.GroupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	slug := req.Slug
+	if slug == "" {
+		slug = slugs.FromTitle(req.Title)
+	}
+	if status, msg := h.validateSlug(group.OrganizationID, slug, 0); status != 0 {
+		c.JSON(status, gin.H{"error": msg})
+		return
+	}
+
+	collection := models.Collection{
+		OrganizationID: group.OrganizationID,
+		GroupID:        group.ID,
+		CreatedByID:    userID,
+		Slug:           slug,
+		Title:          strings.TrimSpace(req.Title),
+		Description:    req.Description,
+		IsPublic:       req.IsPublic,
+	}
+
+	if err := h.db.Create(&collection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create collection"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, collectionToResponse(collection))
+}
+
+// Get returns a collection with its items
+// @Summary Get a collection
+// @Description Get a collection and its items in order. Links the user can't see are left out.
+// @Tags collections
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Success 200 {object} CollectionResponse
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Security BearerAuth
+// @Router /collections/{id} [get]
+func (h *Handler) Get(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	collection, ok := h.findCollection(c, userID, access.ActionView)
+	if !ok {
+		return
+	}
+
+	items, err := h.loadItems(collection.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collection items"})
+		return
+	}
+
+	response := collectionToResponse(*collection)
+	response.Items = []CollectionItemResponse{}
+	for _, item := range items {
+		if item.Link != nil && !h.canViewLink(userID, item.Link) {
+			continue
+		}
+		response.Items = append(response.Items, itemToResponse(item))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Update updates a collection
+// @Summary Update a collection
+// @Description Update a collection's title, slug, description or public sharing
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param request body UpdateCollectionRequest true "Collection changes"
+// @Success 200 {object} CollectionResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Role doesn't allow changing the collection"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 409 {object} map[string]string "Slug already taken"
+// @Security BearerAuth
+// @Router /collections/{id} [put]
+func (h *Handler) Update(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	collection, ok := h.findCollection(c, userID, access.ActionEdit)
+	if !ok {
+		return
+	}
+
+	var req UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Slug != "" && req.Slug != collection.Slug {
+		if status, msg := h.validateSlug(collection.OrganizationID, req.Slug, collection.ID); status != 0 {
+			c.JSON(status, gin.H{"error": msg})
+			return
+		}
+		collection.Slug = req.Slug
+	}
+	if req.Title != "" {
+		collection.Title = strings.TrimSpace(req.Title)
+	}
+	if req.Description != nil {
+		collection.Description = *req.Description
+	}
+	if req.IsPublic != nil {
+		collection.IsPublic = *req.IsPublic
+	}
+
+	if err := h.db.Save(collection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, collectionToResponse(*collection))
+}
+
+// Delete deletes a collection and its items
+// @Summary Delete a collection
+// @Description Delete a collection. The links it references are not affected.
+// @Tags collections
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Success 200 {object} map[string]string "Collection deleted"
+// @Failure 403 {object} map[string]string "Role doesn't allow changing the collection"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Security BearerAuth
+// @Router /collections/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	collection, ok := h.findCollection(c, userID, access.ActionEdit)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", collection.ID).Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(collection).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted"})
+}
+
+// AddItem adds a link or section heading to a collection
+// @Summary Add a collection item
+// @Description Add a link (by link_id or link_slug) or a section heading to a collection, at the given position or at the end
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param request body AddItemRequest true "Item"
+// @Success 201 {object} CollectionItemResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Role doesn't allow changing the collection"
+// @Failure 404 {object} map[string]string "Collection or link not found"
+// @Security BearerAuth
+// @Router /collections/{id}/items [post]
+func (h *Handler) AddItem(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	collection, ok := h.findCollection(c, userID, access.ActionEdit)
+	if !ok {
+		return
+	}
+
+	var req AddItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item := models.CollectionItem{
+		CollectionID: collection.ID,
+		Kind:         req.Kind,
+		Note:         req.Note,
+	}
+	if item.Kind == "" {
+		item.Kind = models.CollectionItemLink
+	}
+
+	if item.Kind == models.CollectionItemSection {
+		item.Heading = strings.TrimSpace(req.Heading)
+		if item.Heading == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Section items require a heading"})
+			return
+		}
+	} else {
+		// Links must belong to the collection's organization and be visible to the user
+		query := h.db.Where("organization_id = ?", collection.OrganizationID)
+		switch {
+		case req.LinkID != 0:
+			query = query.Where("id = ?", req.LinkID)
+		case req.LinkSlug != "":
+			query = query.Where("slug = ?", req.LinkSlug)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link items require a link_id or link_slug"})
+			return
+		}
+
+		var link models.Link
+		if err := query.First(&link).Error; err != nil || !h.canViewLink(userID, &link) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+			return
+		}
+		item.LinkID = &link.ID
+		item.Link = &link
+	}
+
+	var count int64
+	if err := h.db.Model(&models.CollectionItem{}).Where("collection_id = ?", collection.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add item"})
+		return
+	}
+	item.Position = int(count)
+	if req.Position != nil && *req.Position >= 0 && *req.Position < int(count) {
+		item.Position = *req.Position
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		// Make room for the new item
+		if err := tx.Model(&models.CollectionItem{}).
+			Where("collection_id = ? AND position >= ?", collection.ID, item.Position).
+			UpdateColumn("position", gorm.Expr("position + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Link").Create(&item).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add item"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, itemToResponse(item))
+}
+
+// UpdateItem edits a collection item's heading or note
+// @Summary Update a collection item
+// @Description Edit a section's heading or any item's note
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param itemId path int true "Item ID"
+// @Param request body UpdateItemRequest true "Item changes"
+// @Success 200 {object} CollectionItemResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Role doesn't allow changing the collection"
+// @Failure 404 {object} map[string]string "Collection or item not found"
+// @Security BearerAuth
+// @Router /collections/{id}/items/{itemId} [put]
+func (h *Handler) UpdateItem(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	collection, ok := h.findCollection(c, userID, access.ActionEdit)
+	if !ok {
+		return
+	}
+
+	item, ok := h.findItem(c, collection.ID)
+	if !ok {
+		return
+	}
+
+	var req UpdateItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Heading != nil {
+		if item.Kind != models.CollectionItemSection {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only section items have a heading"})
+			return
+		}
+		item.Heading = strings.TrimSpace(*req.Heading)
+	}
+	if req.Note != nil {
+		item.Note = *req.Note
+	}
+
+	if err := h.db.Save(item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+		return
+	}
+
+	if item.LinkID != nil {
+		var link models.Link
+		if err := h.db.First(&link, *item.LinkID).Error; err == nil {
+			item.Link = &link
+		}
+	}
+
+	c.JSON(http.StatusOK, itemToResponse(*item))
+}
+
+// DeleteItem removes an item from a collection
+// @Summary Remove a collection item
+// @Description Remove a link or section heading from a collection. The link itself is not affected.
+// @Tags collections
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param itemId path int true "Item ID"
+// @Success 200 {object} map[string]string "Item removed"
+// @Failure 403 {object} map[string]string "Role doesn't allow changing the collection"
+// @Failure 404 {object} map[string]string "Collection or item not found"
+// @Security BearerAuth
+// @Router /collections/{id}/items/{itemId} [delete]
+func (h *Handler) DeleteItem(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	collection, ok := h.findCollection(c, userID, access.ActionEdit)
+	if !ok {
+		return
+	}
+
+	item, ok := h.findItem(c, collection.ID)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(item).Error; err != nil {
+			return err
+		}
+		// Close the gap left by the item
+		return tx.Model(&models.CollectionItem{}).
+			Where("collection_id = ? AND position > ?", collection.ID, item.Position).
+			UpdateColumn("position", gorm.Expr("position - 1")).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove item"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item removed"})
+}
+
+// reorderMismatch is returned when a reorder request doesn't list every item exactly once
+const reorderMismatch = "item_ids must list every item in the collection exactly once"
+
+// ReorderItems sets the order of a collection's items
+// @Summary Reorder collection items
+// @Description Set the order of a collection's items. item_ids must list every item in the collection exactly once.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param request body ReorderItemsRequest true "New order"
+// @Success 200 {object} CollectionResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Role doesn't allow changing the collection"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Security BearerAuth
+// @Router /collections/{id}/order [put]
+func (h *Handler) ReorderItems(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	collection, ok := h.findCollection(c, userID, access.ActionEdit)
+	if !ok {
+		return
+	}
+
+	var req ReorderItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items, err := h.loadItems(collection.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collection items"})
+		return
+	}
+
+	byID := make(map[uint]*models.CollectionItem, len(items))
+	for i := range items {
+		byID[items[i].ID] = &items[i]
+	}
+	if len(req.ItemIDs) != len(items) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": reorderMismatch})
+		return
+	}
+	for _, id := range req.ItemIDs {
+		if _, ok := byID[id]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": reorderMismatch})
+			return
+		}
+		delete(byID, id)
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		for position, id := range req.ItemIDs {
+			if err := tx.Model(&models.CollectionItem{}).Where("id = ?", id).UpdateColumn("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder items"})
+		return
+	}
+
+	h.Get(c)
+}
+
+// RegisterRoutes registers collection routes
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/collections", h.List)
+	rg.POST("/collections", h.Create)
+	rg.GET("/collections/:id", h.Get)
+	rg.PUT("/collections/:id", h.Update)
+	rg.DELETE("/collections/:id", h.Delete)
+
+	// Items
+	rg.POST("/collections/:id/items", h.AddItem)
+	rg.PUT("/collections/:id/items/:itemId", h.UpdateItem)
+	rg.DELETE("/collections/:id/items/:itemId", h.DeleteItem)
+	rg.PUT("/collections/:id/order", h.ReorderItems)
+}
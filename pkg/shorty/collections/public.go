@@ -0,0 +1,120 @@
+package collections
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/redirect"
+)
+
+// publicPage is the read-only rendering of a public collection
+var publicPage = template.Must(template.New("collection").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+ul { padding-left: 1.25rem; }
+li { margin: 0.5rem 0; }
+.note, .description { color: #555; margin: 0.25rem 0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Description}}<p class="description">{{.Description}}</p>{{end}}
+{{range .Sections}}
+<section>
+{{if .Heading}}<h2>{{.Heading}}</h2>{{end}}
+{{if .Note}}<p class="note">{{.Note}}</p>{{end}}
+<ul>
+{{range .Entries}}<li><a href="/{{.Slug}}">{{.Title}}</a>{{if .Note}}<p class="note">{{.Note}}</p>{{end}}</li>
+{{end}}</ul>
+</section>
+{{end}}
+</body>
+</html>
+`))
+
+// publicEntry is a link as shown on a public collection page
+type publicEntry struct {
+	Slug  string
+	Title string
+	Note  string
+}
+
+// publicSection is a section heading and the links that follow it
+type publicSection struct {
+	Heading string
+	Note    string
+	Entries []publicEntry
+}
+
+// publicSections groups a collection's items under their section headings,
+// keeping only public links that haven't expired. Sections left with no
+// links are dropped.
+func publicSections(items []models.CollectionItem, now time.Time) []publicSection {
+	var sections []publicSection
+	current := publicSection{}
+	flush := func() {
+		if len(current.Entries) > 0 {
+			sections = append(sections, current)
+		}
+	}
+
+	for _, item := range items {
+		if item.Kind == models.CollectionItemSection {
+			flush()
+			current = publicSection{Heading: item.Heading, Note: item.Note}
+			continue
+		}
+		if item.Link == nil || !item.Link.IsPublic || item.Link.IsExpired(now) {
+			continue
+		}
+		title := item.Link.Title
+		if title == "" {
+			title = item.Link.URL
+		}
+		current.Entries = append(current.Entries, publicEntry{Slug: item.Link.Slug, Title: title, Note: item.Note})
+	}
+	flush()
+
+	return sections
+}
+
+// Public renders a public collection as a read-only HTML page.
+// The organization is resolved from the Host header, as for redirects, and
+// only links that are themselves public are shown.
+func (h *Handler) Public(c *gin.Context) {
+	orgID := redirect.ResolveOrganization(h.db, c.Request.Host)
+
+	var collection models.Collection
+	if err := h.db.Where("organization_id = ? AND slug = ? AND is_public = ?", orgID, c.Param("slug"), true).First(&collection).Error; err != nil {
+		c.String(http.StatusNotFound, "Collection not found")
+		return
+	}
+
+	items, err := h.loadItems(collection.ID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to load collection")
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	publicPage.Execute(c.Writer, gin.H{
+		"Title":       collection.Title,
+		"Description": collection.Description,
+		"Sections":    publicSections(items, time.Now()),
+	})
+}
+
+// RegisterPublicRoutes registers the public collection page on the root router.
+// This must be called before the redirect routes.
+func (h *Handler) RegisterPublicRoutes(r *gin.Engine) {
+	r.GET("/c/:slug", h.Public)
+}
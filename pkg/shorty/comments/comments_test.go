@@ -0,0 +1,257 @@
+package comments
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	models.AutoMigrate(db)
+	return db
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, email string) models.User {
+	hash, _ := auth.HashPassword("password123")
+	user := models.User{
+		Email:        email,
+		PasswordHash: hash,
+		Name:         "Test User",
+		SystemRole:   models.SystemRoleUser,
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	return user
+}
+
+func createTestGroup(t *testing.T, db *gorm.DB, name string, userID uint) models.Group {
+	group := models.Group{Name: name}
+	if err := db.Create(&group).Error; err != nil {
+		t.Fatalf("Failed to create test group: %v", err)
+	}
+	membership := models.GroupMembership{
+		UserID:  userID,
+		GroupID: group.ID,
+		Role:    models.GroupRoleAdmin,
+	}
+	if err := db.Create(&membership).Error; err != nil {
+		t.Fatalf("Failed to create test membership: %v", err)
+	}
+	return group
+}
+
+func createTestLink(t *testing.T, db *gorm.DB, groupID, userID uint, slug string) models.Link {
+	link := models.Link{
+		GroupID:     groupID,
+		CreatedByID: userID,
+		Slug:        slug,
+		URL:         "https://example.com",
+		Title:       "Test Link",
+	}
+	if err := db.Create(&link).Error; err != nil {
+		t.Fatalf("Failed to create test link: %v", err)
+	}
+	return link
+}
+
+func setupTestRouter(db *gorm.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handler := NewHandler(db)
+
+	api := r.Group("/api")
+	api.Use(auth.AuthMiddleware())
+	handler.RegisterRoutes(api)
+
+	return r
+}
+
+func getAuthHeader(user models.User) string {
+	token, _ := auth.GenerateToken(user.ID, user.Email, string(user.SystemRole))
+	return "Bearer " + token
+}
+
+func doRequest(router *gin.Engine, user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func createComment(t *testing.T, router *gin.Engine, user models.User, slug string, req CreateCommentRequest) CommentResponse {
+	resp := doRequest(router, user, "POST", "/api/links/"+slug+"/comments", req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var comment CommentResponse
+	json.Unmarshal(resp.Body.Bytes(), &comment)
+	return comment
+}
+
+func TestParseMentions(t *testing.T) {
+	got := parseMentions("Moved, ask @Jane@Example.com or @bob@example.com. cc @jane@example.com, not foo@bar.com")
+	want := []string{"jane@example.com", "bob@example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMentions() = %v, want %v", got, want)
+	}
+}
+
+func TestCreateAndListThreads(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	jane := createTestUser(t, db, "jane@example.com")
+	createTestUser(t, db, "outsider@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+	db.Create(&models.GroupMembership{UserID: jane.ID, GroupID: group.ID, Role: models.GroupRoleViewer})
+	createTestLink(t, db, group.ID, user.ID, "grafana")
+
+	// Users who can't see the link are ignored, like unknown addresses
+	root := createComment(t, router, user, "grafana", CreateCommentRequest{Body: "This dashboard moved, use go/grafana2. @jane@example.com @nobody@example.com @outsider@example.com"})
+	if len(root.Mentions) != 1 || root.Mentions[0].ID != jane.ID {
+		t.Errorf("Expected only jane to be mentioned, got %+v", root.Mentions)
+	}
+	if root.Author.Email != "test@example.com" {
+		t.Errorf("Expected author test@example.com, got %s", root.Author.Email)
+	}
+
+	reply := createComment(t, router, user, "grafana", CreateCommentRequest{Body: "Updated the runbook", ParentID: &root.ID})
+	// Replying to a reply stays in the same thread
+	nested := createComment(t, router, user, "grafana", CreateCommentRequest{Body: "Thanks", ParentID: &reply.ID})
+	if nested.ParentID == nil || *nested.ParentID != root.ID {
+		t.Errorf("Expected reply to attach to the root comment, got %v", nested.ParentID)
+	}
+	createComment(t, router, user, "grafana", CreateCommentRequest{Body: "Second thread"})
+
+	resp := doRequest(router, user, "GET", "/api/links/grafana/comments", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var threads []CommentResponse
+	json.Unmarshal(resp.Body.Bytes(), &threads)
+	if len(threads) != 2 {
+		t.Fatalf("Expected 2 threads, got %d", len(threads))
+	}
+	if len(threads[0].Replies) != 2 {
+		t.Errorf("Expected 2 replies in the first thread, got %d", len(threads[0].Replies))
+	}
+
+	var link models.Link
+	db.Where("slug = ?", "grafana").First(&link)
+	if link.CommentCount != 4 {
+		t.Errorf("Expected comment count 4, got %d", link.CommentCount)
+	}
+}
+
+func TestCommentAccess(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	owner := createTestUser(t, db, "owner@example.com")
+	outsider := createTestUser(t, db, "outsider@example.com")
+	group := createTestGroup(t, db, "Test Group", owner.ID)
+	createTestLink(t, db, group.ID, owner.ID, "private")
+	public := createTestLink(t, db, group.ID, owner.ID, "public")
+	db.Model(&public).Update("is_public", true)
+
+	// Private links are hidden from non-members, like GetBySlug
+	resp := doRequest(router, outsider, "GET", "/api/links/private/comments", nil)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for private link, got %d", resp.Code)
+	}
+	resp = doRequest(router, outsider, "POST", "/api/links/private/comments", CreateCommentRequest{Body: "hi"})
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for private link, got %d", resp.Code)
+	}
+
+	// Users the link is shared with can discuss it
+	private := models.Link{}
+	db.Where("slug = ?", "private").First(&private)
+	db.Create(&models.LinkGrant{LinkID: private.ID, UserID: &outsider.ID, Role: models.GrantRoleViewer, GrantedByID: owner.ID})
+	createComment(t, router, outsider, "private", CreateCommentRequest{Body: "Thanks for sharing"})
+
+	// Public links can be discussed by anyone
+	comment := createComment(t, router, outsider, "public", CreateCommentRequest{Body: "Useful link"})
+
+	// Only the author can edit
+	path := "/api/links/public/comments/" + strconv.FormatUint(uint64(comment.ID), 10)
+	resp = doRequest(router, owner, "PUT", path, UpdateCommentRequest{Body: "Edited"})
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 when editing someone else's comment, got %d", resp.Code)
+	}
+	resp = doRequest(router, outsider, "PUT", path, UpdateCommentRequest{Body: "Very useful link @owner@example.com"})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var updated CommentResponse
+	json.Unmarshal(resp.Body.Bytes(), &updated)
+	if updated.Body != "Very useful link @owner@example.com" || len(updated.Mentions) != 1 {
+		t.Errorf("Expected updated body with one mention, got %+v", updated)
+	}
+
+	// On public links, mentions are limited to the link's organization
+	org := models.Organization{Name: "Acme", Slug: "acme"}
+	db.Create(&org)
+	db.Model(&public).Update("organization_id", org.ID)
+	colleague := createTestUser(t, db, "colleague@example.com")
+	db.Create(&models.OrganizationMembership{OrganizationID: org.ID, UserID: colleague.ID})
+	other := createComment(t, router, outsider, "public", CreateCommentRequest{Body: "@colleague@example.com @outsider@example.com"})
+	if len(other.Mentions) != 1 || other.Mentions[0].ID != colleague.ID {
+		t.Errorf("Expected only the organization member to be mentioned, got %+v", other.Mentions)
+	}
+
+	// Group admins can delete any comment on their links
+	resp = doRequest(router, owner, "DELETE", path, nil)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestDeleteThread(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+	createTestLink(t, db, group.ID, user.ID, "docs")
+
+	root := createComment(t, router, user, "docs", CreateCommentRequest{Body: "Root"})
+	createComment(t, router, user, "docs", CreateCommentRequest{Body: "Reply", ParentID: &root.ID})
+	createComment(t, router, user, "docs", CreateCommentRequest{Body: "Other"})
+
+	resp := doRequest(router, user, "DELETE", "/api/links/docs/comments/"+strconv.FormatUint(uint64(root.ID), 10), nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var link models.Link
+	db.Where("slug = ?", "docs").First(&link)
+	if link.CommentCount != 1 {
+		t.Errorf("Expected comment count 1 after deleting a thread, got %d", link.CommentCount)
+	}
+
+	var remaining int64
+	db.Model(&models.LinkComment{}).Count(&remaining)
+	if remaining != 1 {
+		t.Errorf("Expected 1 remaining comment, got %d", remaining)
+	}
+}
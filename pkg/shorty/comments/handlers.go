@@ -0,0 +1,399 @@
+package comments
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// mentionRegex matches "@" followed by an email address, e.g. "@jane@example.com"
+var mentionRegex = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+// Handler handles link comment requests
+type Handler struct {
+	db *gorm.DB
+}
+
+// NewHandler creates a new comments handler
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// CreateCommentRequest represents the request to comment on a link
+type CreateCommentRequest struct {
+	Body     string `json:"body" binding:"required,min=1,max=10000"`
+	ParentID *uint  `json:"parent_id"` // Comment being replied to
+}
+
+// UpdateCommentRequest represents the request to edit a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=10000"`
+}
+
+// CommentUser represents a comment author or mentioned user in API responses
+type CommentUser struct {
+	ID    uint   `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// CommentResponse represents a comment in API responses
+type CommentResponse struct {
+	ID        uint              `json:"id"`
+	LinkID    uint              `json:"link_id"`
+	ParentID  *uint             `json:"parent_id,omitempty"`
+	Author    CommentUser       `json:"author"`
+	Body      string            `json:"body"`
+	Mentions  []CommentUser     `json:"mentions"`
+	Replies   []CommentResponse `json:"replies,omitempty"`
+	CreatedAt string            `json:"created_at"`
+	UpdatedAt string            `json:"updated_at"`
+}
+
+func userToResponse(user models.User) CommentUser {
+	return CommentUser{ID: user.ID, Email: user.Email, Name: user.Name}
+}
+
+func commentToResponse(comment models.LinkComment) CommentResponse {
+	mentions := make([]CommentUser, len(comment.Mentions))
+	for i, user := range comment.Mentions {
+		mentions[i] = userToResponse(user)
+	}
+	return CommentResponse{
+		ID:        comment.ID,
+		LinkID:    comment.LinkID,
+		ParentID:  comment.ParentID,
+		Author:    userToResponse(comment.User),
+		Body:      comment.Body,
+		Mentions:  mentions,
+		CreatedAt: comment.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: comment.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// parseMentions returns the distinct email addresses mentioned in a comment body
+func parseMentions(body string) []string {
+	var emails []string
+	seen := make(map[string]bool)
+	for _, match := range mentionRegex.FindAllStringSubmatch(body, -1) {
+		email := strings.ToLower(strings.TrimRight(match[1], "."))
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// resolveMentions finds the users mentioned in a comment on a link. Only
+// users who can see the link through their group or a grant, or members of
+// its organization for public links, can be mentioned; anyone else is
+// ignored like an unknown email address, so comments can't be used to look
+// up accounts.
+func (h *Handler) resolveMentions(link *models.Link, body string) ([]models.User, error) {
+	emails := parseMentions(body)
+	if len(emails) == 0 {
+		return nil, nil
+	}
+
+	var users []models.User
+	if err := h.db.Where("LOWER(email) IN ?", emails).Order("id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	var mentioned []models.User
+	for _, user := range users {
+		if access.Role(h.db, user.ID, link) != "" || (link.IsPublic && h.isOrgMember(user.ID, link.OrganizationID)) {
+			mentioned = append(mentioned, user)
+		}
+	}
+	return mentioned, nil
+}
+
+// isOrgMember reports whether the user belongs to the organization
+func (h *Handler) isOrgMember(userID, orgID uint) bool {
+	var count int64
+	h.db.Model(&models.OrganizationMembership{}).
+		Where("user_id = ? AND organization_id = ?", userID, orgID).Count(&count)
+	return count > 0
+}
+
+// findLink looks up a link by slug with the same access rules as viewing it:
+// public links are visible to everyone, private links to group members and
+// grant holders only
+func (h *Handler) findLink(c *gin.Context, userID uint) (*models.Link, bool) {
+	var link models.Link
+	if err := h.db.Where("slug = ?", c.Param("slug")).First(&link).Error; err != nil || !access.CanView(h.db, userID, &link) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return nil, false
+	}
+
+	return &link, true
+}
+
+// findComment looks up a comment on a link by the commentId path parameter
+func (h *Handler) findComment(c *gin.Context, linkID uint) (*models.LinkComment, bool) {
+	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return nil, false
+	}
+
+	var comment models.LinkComment
+	if err := h.db.Preload("User").Preload("Mentions").
+		Where("id = ? AND link_id = ?", commentID, linkID).First(&comment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return nil, false
+	}
+
+	return &comment, true
+}
+
+// List returns the comments on a link as threads
+// @Summary List link comments
+// @Description Get the comments on a link, oldest first, with replies nested under their parent comment
+// @Tags comments
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Success 200 {array} CommentResponse
+// @Failure 404 {object} map[string]string "Link not found"
+// @Security BearerAuth
+// @Router /links/{slug}/comments [get]
+func (h *Handler) List(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findLink(c, userID)
+	if !ok {
+		return
+	}
+
+	var comments []models.LinkComment
+	if err := h.db.Preload("User").Preload("Mentions").
+		Where("link_id = ?", link.ID).Order("created_at, id").Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	// Build threads: top-level comments in order, each with its replies
+	threads := []CommentResponse{}
+	index := make(map[uint]int)
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			index[comment.ID] = len(threads)
+			threads = append(threads, commentToResponse(comment))
+		}
+	}
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			if i, ok := index[*comment.ParentID]; ok {
+				threads[i].Replies = append(threads[i].Replies, commentToResponse(comment))
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, threads)
+}
+
+// Create adds a comment to a link
+// @Summary Comment on a link
+// @Description Add a comment or reply to a link. Mention users who can see the link with @ followed by their email address (e.g. @jane@example.com); other addresses are ignored. Replying to a reply adds to the same thread.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Param request body CreateCommentRequest true "Comment"
+// @Success 201 {object} CommentResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 404 {object} map[string]string "Link not found"
+// @Security BearerAuth
+// @Router /links/{slug}/comments [post]
+func (h *Handler) Create(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findLink(c, userID)
+	if !ok {
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment body is required"})
+		return
+	}
+
+	// Replies always attach to the top-level comment of the thread
+	var parentID *uint
+	if req.ParentID != nil {
+		var parent models.LinkComment
+		if err := h.db.Where("id = ? AND link_id = ?", *req.ParentID, link.ID).First(&parent).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent comment not found"})
+			return
+		}
+		parentID = &parent.ID
+		if parent.ParentID != nil {
+			parentID = parent.ParentID
+		}
+	}
+
+	mentions, err := h.resolveMentions(link, body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve mentions"})
+		return
+	}
+
+	comment := models.LinkComment{
+		LinkID:   link.ID,
+		UserID:   userID,
+		ParentID: parentID,
+		Body:     body,
+		Mentions: mentions,
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		return tx.Model(link).UpdateColumn("comment_count", gorm.Expr("comment_count + 1")).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		return
+	}
+
+	h.db.First(&comment.User, userID)
+	c.JSON(http.StatusCreated, commentToResponse(comment))
+}
+
+// Update edits a comment (author only)
+// @Summary Edit a comment
+// @Description Edit the body of a comment. Only the author can edit a comment.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Param commentId path int true "Comment ID"
+// @Param request body UpdateCommentRequest true "Updated comment"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Only the author can edit a comment"
+// @Failure 404 {object} map[string]string "Comment not found"
+// @Security BearerAuth
+// @Router /links/{slug}/comments/{commentId} [put]
+func (h *Handler) Update(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findLink(c, userID)
+	if !ok {
+		return
+	}
+
+	comment, ok := h.findComment(c, link.ID)
+	if !ok {
+		return
+	}
+
+	if comment.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author can edit a comment"})
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment body is required"})
+		return
+	}
+
+	mentions, err := h.resolveMentions(link, body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve mentions"})
+		return
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(comment).Update("body", body).Error; err != nil {
+			return err
+		}
+		return tx.Model(comment).Association("Mentions").Replace(mentions)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	comment.Mentions = mentions
+	c.JSON(http.StatusOK, commentToResponse(*comment))
+}
+
+// Delete removes a comment and its replies (author or group admin)
+// @Summary Delete a comment
+// @Description Delete a comment. Deleting a top-level comment also deletes its replies. The author or an admin of the link's group can delete a comment.
+// @Tags comments
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Param commentId path int true "Comment ID"
+// @Success 200 {object} map[string]string "Comment deleted"
+// @Failure 403 {object} map[string]string "Not allowed to delete this comment"
+// @Failure 404 {object} map[string]string "Comment not found"
+// @Security BearerAuth
+// @Router /links/{slug}/comments/{commentId} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findLink(c, userID)
+	if !ok {
+		return
+	}
+
+	comment, ok := h.findComment(c, link.ID)
+	if !ok {
+		return
+	}
+
+	if comment.UserID != userID {
+		if !access.IsGroupAdmin(h.db, userID, link.GroupID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to delete this comment"})
+			return
+		}
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? OR parent_id = ?", comment.ID, comment.ID).Delete(&models.LinkComment{})
+		if result.Error != nil {
+			return result.Error
+		}
+		return tx.Model(link).UpdateColumn("comment_count", gorm.Expr("CASE WHEN comment_count > ? THEN comment_count - ? ELSE 0 END", result.RowsAffected, result.RowsAffected)).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+}
+
+// RegisterRoutes registers comment routes
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/links/:slug/comments", h.List)
+	rg.POST("/links/:slug/comments", h.Create)
+	rg.PUT("/links/:slug/comments/:commentId", h.Update)
+	rg.DELETE("/links/:slug/comments/:commentId", h.Delete)
+}
@@ -5,10 +5,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/mail"
 	"github.com/mikepea/shorty/pkg/shorty/models"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -388,3 +392,421 @@ func TestCannotRemoveLastAdmin(t *testing.T) {
 		t.Errorf("Expected status 400, got %d: %s", resp.Code, resp.Body.String())
 	}
 }
+
+func TestGroupNamespace(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	db.Model(&models.Organization{}).Where("is_global = ?", true).Update("reserved_namespaces", "go,docs")
+
+	doRequest := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := doRequest("POST", "/groups", CreateGroupRequest{Name: "SRE", Namespace: "SRE"})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var sre GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &sre)
+	if sre.Namespace != "sre" {
+		t.Errorf("Expected namespace 'sre', got %q", sre.Namespace)
+	}
+
+	tests := []struct {
+		namespace string
+		status    int
+	}{
+		{"sre", http.StatusConflict},     // Owned by another group
+		{"api", http.StatusBadRequest},   // Application route
+		{"c", http.StatusBadRequest},     // Public collection pages
+		{"t", http.StatusBadRequest},     // Public tag pages
+		{"docs", http.StatusBadRequest},  // Reserved by the organization
+		{"a/b", http.StatusBadRequest},   // Invalid characters
+		{"-ops", http.StatusBadRequest},  // Must start with a letter or number
+		{"platform", http.StatusCreated}, // Available
+	}
+	for _, tt := range tests {
+		resp := doRequest("POST", "/groups", CreateGroupRequest{Name: "Team", Namespace: tt.namespace})
+		if resp.Code != tt.status {
+			t.Errorf("Namespace %q: expected status %d, got %d", tt.namespace, tt.status, resp.Code)
+		}
+	}
+
+	// Releasing a namespace lets another group claim it
+	empty := ""
+	resp = doRequest("PUT", "/groups/"+strconv.FormatUint(uint64(sre.ID), 10), UpdateGroupRequest{Namespace: &empty})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp := doRequest("POST", "/groups", CreateGroupRequest{Name: "New SRE", Namespace: "sre"}); resp.Code != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestGroupPublicPage(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	admin := createTestUser(t, db, "admin@example.com")
+	member := createTestUser(t, db, "member@example.com")
+
+	doRequest := func(user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := doRequest(admin, "POST", "/groups", CreateGroupRequest{Name: "SRE"})
+	var sre GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &sre)
+	resp = doRequest(admin, "POST", "/groups", CreateGroupRequest{Name: "Platform"})
+	var platform GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &platform)
+	db.Create(&models.GroupMembership{UserID: member.ID, GroupID: sre.ID, Role: models.GroupRoleMember})
+	path := "/groups/" + strconv.FormatUint(uint64(sre.ID), 10)
+
+	on, off, slug := true, false, "SRE"
+
+	// Only group admins publish the page, and it needs a slug
+	if resp := doRequest(member, "PUT", path, UpdateGroupRequest{PublicSlug: &slug, IsPublicPage: &on}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+	if resp := doRequest(admin, "PUT", path, UpdateGroupRequest{IsPublicPage: &on}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a slug, got %d", resp.Code)
+	}
+
+	resp = doRequest(admin, "PUT", path, UpdateGroupRequest{PublicSlug: &slug, IsPublicPage: &on})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var updated GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &updated)
+	if updated.PublicSlug != "sre" || !updated.IsPublicPage {
+		t.Errorf("Expected the public page on at sre, got %+v", updated)
+	}
+
+	// Another group can't use the same slug
+	if resp := doRequest(admin, "PUT", "/groups/"+strconv.FormatUint(uint64(platform.ID), 10), UpdateGroupRequest{PublicSlug: &slug}); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", resp.Code)
+	}
+
+	// Turning the page off keeps the slug
+	resp = doRequest(admin, "PUT", path, UpdateGroupRequest{IsPublicPage: &off})
+	json.Unmarshal(resp.Body.Bytes(), &updated)
+	if updated.PublicSlug != "sre" || updated.IsPublicPage {
+		t.Errorf("Expected the public page off, got %+v", updated)
+	}
+}
+
+func TestGroupLinkPolicy(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	admin := createTestUser(t, db, "admin@example.com")
+	member := createTestUser(t, db, "member@example.com")
+
+	doRequest := func(user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := doRequest(admin, "POST", "/groups", CreateGroupRequest{Name: "SRE"})
+	var sre GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &sre)
+	db.Create(&models.GroupMembership{UserID: member.ID, GroupID: sre.ID, Role: models.GroupRoleMember})
+	path := "/groups/" + strconv.FormatUint(uint64(sre.ID), 10) + "/policy"
+
+	on, days, prefix := true, 30, "sre-"
+	domains := []string{"Example.com", "*.acme.io", "example.com"}
+	defaultTags := []string{"oncall", " infra/monitoring/ "}
+	update := UpdateLinkPolicyRequest{
+		DefaultIsPublic:   &on,
+		DefaultTags:       &defaultTags,
+		DefaultExpiryDays: &days,
+		AllowedDomains:    &domains,
+		RequireTitle:      &on,
+		SlugPrefix:        &prefix,
+	}
+
+	// Only group admins change the policy
+	if resp := doRequest(member, "PUT", path, update); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+
+	resp = doRequest(admin, "PUT", path, update)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	// Members can read it
+	resp = doRequest(member, "GET", path, nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.Code)
+	}
+	var policy LinkPolicyResponse
+	json.Unmarshal(resp.Body.Bytes(), &policy)
+	if !policy.DefaultIsPublic || policy.DefaultExpiryDays != 30 || !policy.RequireTitle || policy.SlugPrefix != "sre-" ||
+		strings.Join(policy.DefaultTags, ",") != "oncall,infra/monitoring" ||
+		strings.Join(policy.AllowedDomains, ",") != "example.com,acme.io" {
+		t.Errorf("Unexpected policy: %+v", policy)
+	}
+
+	// Fields left out are unchanged, and false is saved
+	off := false
+	resp = doRequest(admin, "PUT", path, UpdateLinkPolicyRequest{RequireTitle: &off})
+	json.Unmarshal(resp.Body.Bytes(), &policy)
+	var group models.Group
+	db.First(&group, sre.ID)
+	if group.RequireTitle || !group.DefaultIsPublic || group.SlugPrefix != "sre-" {
+		t.Errorf("Expected only require_title to change, got %+v", policy)
+	}
+
+	// Invalid settings are rejected
+	badDomains := []string{"https://example.com/path"}
+	badPrefix := "sre/"
+	badDays := -1
+	for _, bad := range []UpdateLinkPolicyRequest{{AllowedDomains: &badDomains}, {SlugPrefix: &badPrefix}, {DefaultExpiryDays: &badDays}} {
+		if resp := doRequest(admin, "PUT", path, bad); resp.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %+v, got %d", bad, resp.Code)
+		}
+	}
+
+	// Non-members can't see it
+	outsider := createTestUser(t, db, "outsider@example.com")
+	if resp := doRequest(outsider, "GET", path, nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+}
+
+func TestNestedGroups(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	admin := createTestUser(t, db, "admin@example.com")
+	member := createTestUser(t, db, "member@example.com")
+	outsider := createTestUser(t, db, "outsider@example.com")
+
+	doRequest := func(user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	groupPath := func(id uint) string { return "/groups/" + strconv.FormatUint(uint64(id), 10) }
+
+	// Engineering > SRE > Oncall
+	resp := doRequest(admin, "POST", "/groups", CreateGroupRequest{Name: "Engineering"})
+	var engineering GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &engineering)
+	resp = doRequest(admin, "POST", "/groups", CreateGroupRequest{Name: "SRE", ParentID: engineering.ID})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var sre GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &sre)
+	if sre.ParentID == nil || *sre.ParentID != engineering.ID {
+		t.Errorf("Expected SRE under Engineering, got %+v", sre.ParentID)
+	}
+	resp = doRequest(admin, "POST", "/groups", CreateGroupRequest{Name: "Oncall", ParentID: sre.ID})
+	var oncall GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &oncall)
+
+	// Only admins of the parent can nest under it
+	resp = doRequest(outsider, "POST", "/groups", CreateGroupRequest{Name: "Sneaky", ParentID: engineering.ID})
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 nesting under another team's group, got %d", resp.Code)
+	}
+
+	// Members of Engineering are members of Oncall with the same role
+	db.Create(&models.GroupMembership{UserID: member.ID, GroupID: engineering.ID, Role: models.GroupRoleMember})
+	resp = doRequest(member, "GET", groupPath(oncall.ID), nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for an inherited member, got %d", resp.Code)
+	}
+	var got GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &got)
+	if got.Role != string(models.GroupRoleMember) {
+		t.Errorf("Expected inherited role member, got %q", got.Role)
+	}
+	if resp := doRequest(member, "GET", groupPath(oncall.ID)+"/members", nil); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 listing members, got %d", resp.Code)
+	}
+	if resp := doRequest(outsider, "GET", groupPath(oncall.ID), nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an outsider, got %d", resp.Code)
+	}
+
+	resp = doRequest(member, "GET", "/groups", nil)
+	var listed []GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &listed)
+	if len(listed) != 3 {
+		t.Fatalf("Expected Engineering and its two subgroups, got %d groups", len(listed))
+	}
+	for _, g := range listed {
+		if g.Inherited != (g.ID != engineering.ID) {
+			t.Errorf("Expected only subgroups to be inherited, got %+v", g)
+		}
+	}
+
+	// Admins of Engineering administer Oncall, members don't
+	name := "Oncall Rotation"
+	if resp := doRequest(member, "PUT", groupPath(oncall.ID), UpdateGroupRequest{Name: name}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for an inherited member, got %d", resp.Code)
+	}
+	db.Model(&models.GroupMembership{}).Where("group_id = ? AND user_id = ?", sre.ID, admin.ID).Delete(&models.GroupMembership{})
+	if resp := doRequest(admin, "PUT", groupPath(sre.ID), UpdateGroupRequest{Name: name}); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for an inherited admin, got %d", resp.Code)
+	}
+
+	// A group can't be nested under itself or below itself
+	for _, parentID := range []uint{engineering.ID, oncall.ID} {
+		id := parentID
+		resp := doRequest(admin, "PUT", groupPath(engineering.ID), UpdateGroupRequest{ParentID: &id})
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for a cycle through %d, got %d", id, resp.Code)
+		}
+	}
+
+	// Groups with subgroups can't be deleted
+	if resp := doRequest(admin, "DELETE", groupPath(sre.ID), nil); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 deleting a group with subgroups, got %d", resp.Code)
+	}
+
+	// Detaching SRE ends the inherited membership
+	top := uint(0)
+	resp = doRequest(admin, "PUT", groupPath(sre.ID), UpdateGroupRequest{ParentID: &top})
+	var detached GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &detached)
+	if resp.Code != http.StatusOK || detached.ParentID != nil {
+		t.Errorf("Expected SRE detached, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp := doRequest(member, "GET", groupPath(oncall.ID), nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after detaching, got %d", resp.Code)
+	}
+}
+
+type recordingSender struct {
+	messages []mail.Message
+}
+
+func (s *recordingSender) Send(msg mail.Message) error {
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func TestGroupInvites(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	sender := &recordingSender{}
+	inviteHandler := NewInviteHandler(db, sender, "https://shorty.example.com/")
+	inviteHandler.RegisterRoutes(router.Group("/groups", auth.AuthMiddleware()))
+	inviteHandler.RegisterAcceptRoutes(router.Group("", auth.AuthMiddleware()))
+
+	admin := createTestUser(t, db, "admin@example.com")
+	invitee := createTestUser(t, db, "invitee@example.com")
+	first := createTestUser(t, db, "first@example.com")
+	second := createTestUser(t, db, "second@example.com")
+
+	doRequest := func(user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := doRequest(admin, "POST", "/groups", CreateGroupRequest{Name: "SRE"})
+	var group GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &group)
+	invitesPath := "/groups/" + strconv.FormatUint(uint64(group.ID), 10) + "/invites"
+
+	// Only admins invite
+	if resp := doRequest(invitee, "POST", invitesPath, CreateInviteRequest{Email: "x@example.com"}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+	if resp := doRequest(admin, "POST", invitesPath, CreateInviteRequest{Email: "admin@example.com"}); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 inviting a member, got %d", resp.Code)
+	}
+
+	// An email invite is mailed and only works for that address
+	resp = doRequest(admin, "POST", invitesPath, CreateInviteRequest{Email: "invitee@example.com", Role: "editor"})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var emailInvite CreateInviteResponse
+	json.Unmarshal(resp.Body.Bytes(), &emailInvite)
+	if len(sender.messages) != 1 || sender.messages[0].To[0] != "invitee@example.com" {
+		t.Fatalf("Expected an email to the invitee, got %+v", sender.messages)
+	}
+	if !strings.Contains(sender.messages[0].Body, "https://shorty.example.com/register?invite="+emailInvite.Token) {
+		t.Errorf("Expected the invite link in the email, got %q", sender.messages[0].Body)
+	}
+
+	acceptPath := "/invites/" + emailInvite.Token + "/accept"
+	if resp := doRequest(first, "POST", acceptPath, nil); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for someone else, got %d", resp.Code)
+	}
+	resp = doRequest(invitee, "POST", acceptPath, nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var joined GroupResponse
+	json.Unmarshal(resp.Body.Bytes(), &joined)
+	if joined.ID != group.ID || joined.Role != "editor" {
+		t.Errorf("Expected to join as editor, got %+v", joined)
+	}
+
+	// An invite link works for anyone until it runs out of uses
+	expiresAt := time.Now().Add(time.Hour)
+	resp = doRequest(admin, "POST", invitesPath, CreateInviteRequest{MaxUses: 1, ExpiresAt: &expiresAt})
+	var linkInvite CreateInviteResponse
+	json.Unmarshal(resp.Body.Bytes(), &linkInvite)
+	if len(sender.messages) != 1 {
+		t.Errorf("Expected no email for an invite link, got %d", len(sender.messages))
+	}
+	if resp := doRequest(first, "POST", "/invites/"+linkInvite.Token+"/accept", nil); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.Code)
+	}
+	if resp := doRequest(second, "POST", "/invites/"+linkInvite.Token+"/accept", nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 once used up, got %d", resp.Code)
+	}
+
+	// Revoked invites can't be accepted
+	resp = doRequest(admin, "POST", invitesPath, CreateInviteRequest{})
+	var revoked CreateInviteResponse
+	json.Unmarshal(resp.Body.Bytes(), &revoked)
+	if resp := doRequest(admin, "DELETE", invitesPath+"/"+strconv.FormatUint(uint64(revoked.ID), 10), nil); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 revoking, got %d", resp.Code)
+	}
+	if resp := doRequest(second, "POST", "/invites/"+revoked.Token+"/accept", nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a revoked invite, got %d", resp.Code)
+	}
+
+	resp = doRequest(admin, "GET", invitesPath, nil)
+	var listed []InviteResponse
+	json.Unmarshal(resp.Body.Bytes(), &listed)
+	statuses := make([]string, len(listed))
+	for i, invite := range listed {
+		statuses[i] = invite.Status
+	}
+	if strings.Join(statuses, ",") != "revoked,used_up,accepted" {
+		t.Errorf("Expected revoked, used up and accepted invites, got %v", statuses)
+	}
+}
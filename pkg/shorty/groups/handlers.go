@@ -3,10 +3,13 @@ package groups
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
 	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/slugs"
 	"gorm.io/gorm"
 )
 
@@ -25,26 +28,100 @@ type CreateGroupRequest struct {
 	Name           string `json:"name" binding:"required"`
 	Description    string `json:"description"`
 	OrganizationID uint   `json:"organization_id"` // Optional - defaults to org from context or global
+	Namespace      string `json:"namespace"`       // Optional slug prefix owned by the group, e.g. "sre"
+	ParentID       uint   `json:"parent_id"`       // Optional group to nest under; its members become members of this group
 }
 
 // UpdateGroupRequest represents the request to update a group
 type UpdateGroupRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Namespace    *string `json:"namespace"`      // An empty string releases the namespace
+	PublicSlug   *string `json:"public_slug"`    // Path of the public page, e.g. "sre" for /g/sre
+	IsPublicPage *bool   `json:"is_public_page"` // Turns the public page on or off; needs a public_slug
+	ParentID     *uint   `json:"parent_id"`      // Group to nest under; 0 makes it a top-level group
 }
 
 // GroupResponse represents a group in API responses
 type GroupResponse struct {
-	ID          uint   `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Role        string `json:"role,omitempty"` // User's role in this group
-	MemberCount int    `json:"member_count,omitempty"`
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Namespace    string `json:"namespace,omitempty"`
+	PublicSlug   string `json:"public_slug,omitempty"`
+	IsPublicPage bool   `json:"is_public_page"`
+	ParentID     *uint  `json:"parent_id,omitempty"`
+	Role         string `json:"role,omitempty"`      // User's effective role in this group
+	Inherited    bool   `json:"inherited,omitempty"` // Membership comes from a group above this one
+	MemberCount  int    `json:"member_count,omitempty"`
+}
+
+// validateNamespace checks a namespace is well-formed, not reserved, and not
+// already claimed by another group in the organization
+func (h *Handler) validateNamespace(orgID uint, namespace string, excludeGroupID uint) (int, string) {
+	if !slugs.IsValidNamespace(namespace) {
+		return http.StatusBadRequest, "Namespace must be up to 30 lowercase letters, numbers, hyphens, and underscores"
+	}
+
+	var org models.Organization
+	if err := h.db.First(&org, orgID).Error; err != nil {
+		return http.StatusNotFound, "Organization not found"
+	}
+	if slugs.IsReservedNamespace(namespace) || org.IsReservedNamespace(namespace) {
+		return http.StatusBadRequest, "This namespace is reserved"
+	}
+
+	var count int64
+	query := h.db.Model(&models.Group{}).Where("organization_id = ? AND namespace = ?", orgID, namespace)
+	if excludeGroupID > 0 {
+		query = query.Where("id != ?", excludeGroupID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return http.StatusInternalServerError, "Failed to check namespace"
+	}
+	if count > 0 {
+		return http.StatusConflict, "This namespace is already taken"
+	}
+	return 0, ""
+}
+
+// validatePublicSlug checks a public page slug is well-formed and not already
+// used by another group's page in the organization
+func (h *Handler) validatePublicSlug(orgID uint, slug string, excludeGroupID uint) (int, string) {
+	if !slugs.IsValidNamespace(slug) {
+		return http.StatusBadRequest, "Public slug must be up to 30 lowercase letters, numbers, hyphens, and underscores"
+	}
+
+	var count int64
+	if err := h.db.Model(&models.Group{}).Where("organization_id = ? AND public_slug = ? AND id != ?", orgID, slug, excludeGroupID).Count(&count).Error; err != nil {
+		return http.StatusInternalServerError, "Failed to check public slug"
+	}
+	if count > 0 {
+		return http.StatusConflict, "This public slug is already taken"
+	}
+	return 0, ""
+}
+
+// validateParent checks the user can nest a group under a parent: the parent
+// must be in the same organization, the user must be one of its admins, and
+// it mustn't be the group itself or one of its subgroups, which would make a cycle
+func (h *Handler) validateParent(userID, orgID, groupID, parentID uint) (int, string) {
+	var parent models.Group
+	if err := h.db.First(&parent, parentID).Error; err != nil || parent.OrganizationID != orgID {
+		return http.StatusBadRequest, "Parent group not found in this organization"
+	}
+	if !access.IsGroupAdmin(h.db, userID, parentID) {
+		return http.StatusForbidden, "Admin access to the parent group required"
+	}
+	if groupID > 0 && access.IsSubgroup(h.db, parentID, groupID) {
+		return http.StatusBadRequest, "A group can't be nested under itself or one of its subgroups"
+	}
+	return 0, ""
 }
 
 // List returns all groups the current user is a member of
 // @Summary List groups
-// @Description Get all groups the current user is a member of
+// @Description Get all groups the current user is a member of, including subgroups of their groups
 // @Tags groups
 // @Produce json
 // @Success 200 {array} GroupResponse
@@ -53,23 +130,35 @@ type GroupResponse struct {
 func (h *Handler) List(c *gin.Context) {
 	userID, _ := auth.GetUserID(c)
 
-	var memberships []models.GroupMembership
-	if err := h.db.Preload("Group").Where("user_id = ?", userID).Find(&memberships).Error; err != nil {
+	var memberGroups []models.Group
+	if err := h.db.Where("id IN (?)", access.MemberGroups(h.db, userID)).Order("id").Find(&memberGroups).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
 		return
 	}
 
-	groups := make([]GroupResponse, len(memberships))
-	for i, m := range memberships {
+	var directIDs []uint
+	h.db.Model(&models.GroupMembership{}).Where("user_id = ?", userID).Pluck("group_id", &directIDs)
+	direct := make(map[uint]bool, len(directIDs))
+	for _, id := range directIDs {
+		direct[id] = true
+	}
+
+	groups := make([]GroupResponse, len(memberGroups))
+	for i, g := range memberGroups {
 		var memberCount int64
-		h.db.Model(&models.GroupMembership{}).Where("group_id = ?", m.GroupID).Count(&memberCount)
+		h.db.Model(&models.GroupMembership{}).Where("group_id = ?", g.ID).Count(&memberCount)
 
 		groups[i] = GroupResponse{
-			ID:          m.Group.ID,
-			Name:        m.Group.Name,
-			Description: m.Group.Description,
-			Role:        string(m.Role),
-			MemberCount: int(memberCount),
+			ID:           g.ID,
+			Name:         g.Name,
+			Description:  g.Description,
+			Namespace:    g.Namespace,
+			PublicSlug:   g.PublicSlug,
+			IsPublicPage: g.IsPublicPage,
+			ParentID:     g.ParentID,
+			Role:         string(access.GroupRole(h.db, userID, g.ID)),
+			Inherited:    !direct[g.ID],
+			MemberCount:  int(memberCount),
 		}
 	}
 
@@ -122,13 +211,32 @@ func (h *Handler) Create(c *gin.Context) {
 		return
 	}
 
+	namespace := strings.ToLower(strings.TrimSpace(req.Namespace))
+	if namespace != "" {
+		if status, msg := h.validateNamespace(orgID, namespace, 0); status != 0 {
+			c.JSON(status, gin.H{"error": msg})
+			return
+		}
+	}
+
+	var parentID *uint
+	if req.ParentID != 0 {
+		if status, msg := h.validateParent(userID, orgID, 0, req.ParentID); status != 0 {
+			c.JSON(status, gin.H{"error": msg})
+			return
+		}
+		parentID = &req.ParentID
+	}
+
 	// Create group in a transaction
 	var group models.Group
 	err := h.db.Transaction(func(tx *gorm.DB) error {
 		group = models.Group{
 			OrganizationID: orgID,
+			ParentID:       parentID,
 			Name:           req.Name,
 			Description:    req.Description,
+			Namespace:      namespace,
 		}
 		if err := tx.Create(&group).Error; err != nil {
 			return err
@@ -152,6 +260,8 @@ func (h *Handler) Create(c *gin.Context) {
 		ID:          group.ID,
 		Name:        group.Name,
 		Description: group.Description,
+		Namespace:   group.Namespace,
+		ParentID:    group.ParentID,
 		Role:        string(models.GroupRoleAdmin),
 		MemberCount: 1,
 	})
@@ -175,9 +285,9 @@ func (h *Handler) Get(c *gin.Context) {
 		return
 	}
 
-	// Check membership
-	var membership models.GroupMembership
-	if err := h.db.Where("user_id = ? AND group_id = ?", userID, groupID).First(&membership).Error; err != nil {
+	// Check membership, which may come from a group above this one
+	role := access.GroupRole(h.db, userID, uint(groupID))
+	if role == "" {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
 		return
 	}
@@ -192,17 +302,21 @@ func (h *Handler) Get(c *gin.Context) {
 	h.db.Model(&models.GroupMembership{}).Where("group_id = ?", groupID).Count(&memberCount)
 
 	c.JSON(http.StatusOK, GroupResponse{
-		ID:          group.ID,
-		Name:        group.Name,
-		Description: group.Description,
-		Role:        string(membership.Role),
-		MemberCount: int(memberCount),
+		ID:           group.ID,
+		Name:         group.Name,
+		Description:  group.Description,
+		Namespace:    group.Namespace,
+		PublicSlug:   group.PublicSlug,
+		IsPublicPage: group.IsPublicPage,
+		ParentID:     group.ParentID,
+		Role:         string(role),
+		MemberCount:  int(memberCount),
 	})
 }
 
 // Update updates a group (admin only)
 // @Summary Update a group
-// @Description Update a group (requires admin role in group)
+// @Description Update a group (requires admin role in group). Setting public_slug and is_public_page publishes the group's public links at /g/{public_slug}.
 // @Tags groups
 // @Accept json
 // @Produce json
@@ -221,9 +335,8 @@ func (h *Handler) Update(c *gin.Context) {
 		return
 	}
 
-	// Check admin membership
-	var membership models.GroupMembership
-	if err := h.db.Where("user_id = ? AND group_id = ? AND role = ?", userID, groupID, models.GroupRoleAdmin).First(&membership).Error; err != nil {
+	// Check admin membership, which may come from a group above this one
+	if !access.IsGroupAdmin(h.db, userID, uint(groupID)) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 		return
 	}
@@ -247,6 +360,44 @@ func (h *Handler) Update(c *gin.Context) {
 	if req.Description != "" {
 		group.Description = req.Description
 	}
+	if req.Namespace != nil {
+		namespace := strings.ToLower(strings.TrimSpace(*req.Namespace))
+		if namespace != "" && namespace != group.Namespace {
+			if status, msg := h.validateNamespace(group.OrganizationID, namespace, group.ID); status != 0 {
+				c.JSON(status, gin.H{"error": msg})
+				return
+			}
+		}
+		group.Namespace = namespace
+	}
+	if req.PublicSlug != nil {
+		publicSlug := strings.ToLower(strings.TrimSpace(*req.PublicSlug))
+		if publicSlug != "" && publicSlug != group.PublicSlug {
+			if status, msg := h.validatePublicSlug(group.OrganizationID, publicSlug, group.ID); status != 0 {
+				c.JSON(status, gin.H{"error": msg})
+				return
+			}
+		}
+		group.PublicSlug = publicSlug
+	}
+	if req.IsPublicPage != nil {
+		group.IsPublicPage = *req.IsPublicPage
+	}
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			group.ParentID = nil
+		} else if group.ParentID == nil || *group.ParentID != *req.ParentID {
+			if status, msg := h.validateParent(userID, group.OrganizationID, group.ID, *req.ParentID); status != 0 {
+				c.JSON(status, gin.H{"error": msg})
+				return
+			}
+			group.ParentID = req.ParentID
+		}
+	}
+	if group.IsPublicPage && group.PublicSlug == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A public page needs a public_slug"})
+		return
+	}
 
 	if err := h.db.Save(&group).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update group"})
@@ -257,11 +408,15 @@ func (h *Handler) Update(c *gin.Context) {
 	h.db.Model(&models.GroupMembership{}).Where("group_id = ?", groupID).Count(&memberCount)
 
 	c.JSON(http.StatusOK, GroupResponse{
-		ID:          group.ID,
-		Name:        group.Name,
-		Description: group.Description,
-		Role:        string(membership.Role),
-		MemberCount: int(memberCount),
+		ID:           group.ID,
+		Name:         group.Name,
+		Description:  group.Description,
+		Namespace:    group.Namespace,
+		PublicSlug:   group.PublicSlug,
+		IsPublicPage: group.IsPublicPage,
+		ParentID:     group.ParentID,
+		Role:         string(models.GroupRoleAdmin),
+		MemberCount:  int(memberCount),
 	})
 }
 
@@ -283,12 +438,19 @@ func (h *Handler) Delete(c *gin.Context) {
 		return
 	}
 
-	// Check admin membership
-	if err := h.db.Where("user_id = ? AND group_id = ? AND role = ?", userID, groupID, models.GroupRoleAdmin).First(&models.GroupMembership{}).Error; err != nil {
+	// Check admin membership, which may come from a group above this one
+	if !access.IsGroupAdmin(h.db, userID, uint(groupID)) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 		return
 	}
 
+	var subgroups int64
+	h.db.Model(&models.Group{}).Where("parent_id = ?", groupID).Count(&subgroups)
+	if subgroups > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Move or delete the group's subgroups first"})
+		return
+	}
+
 	// Delete group (cascades to memberships via soft delete)
 	if err := h.db.Delete(&models.Group{}, groupID).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete group"})
@@ -305,4 +467,6 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("/:id", h.Get)
 	rg.PUT("/:id", h.Update)
 	rg.DELETE("/:id", h.Delete)
+	rg.GET("/:id/policy", h.GetPolicy)
+	rg.PUT("/:id/policy", h.UpdatePolicy)
 }
@@ -0,0 +1,348 @@
+package groups
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/invites"
+	"github.com/mikepea/shorty/pkg/shorty/mail"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// DefaultInviteExpiry is how long an invite lasts when no expiry is given
+const DefaultInviteExpiry = 7 * 24 * time.Hour
+
+// Invite statuses
+const (
+	InviteStatusPending  = "pending"
+	InviteStatusAccepted = "accepted"
+	InviteStatusExpired  = "expired"
+	InviteStatusUsedUp   = "used_up"
+	InviteStatusRevoked  = "revoked"
+)
+
+// InviteHandler handles group invitation requests
+type InviteHandler struct {
+	db      *gorm.DB
+	sender  mail.Sender
+	baseURL string
+}
+
+// NewInviteHandler creates a new invite handler. Email invites are sent with
+// sender, and baseURL is used to build the links in them.
+func NewInviteHandler(db *gorm.DB, sender mail.Sender, baseURL string) *InviteHandler {
+	return &InviteHandler{db: db, sender: sender, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// CreateInviteRequest represents a request to invite people to a group.
+// With an email it invites that address once; without, it creates an invite
+// link that anyone with the link can use.
+type CreateInviteRequest struct {
+	Email     string     `json:"email" binding:"omitempty,email"`
+	Role      string     `json:"role" binding:"omitempty,oneof=admin editor member contributor viewer"` // Default member
+	ExpiresAt *time.Time `json:"expires_at"`                                                            // Default 7 days from now
+	MaxUses   int        `json:"max_uses" binding:"min=0"`                                              // Invite links only; 0 means unlimited
+}
+
+// InviteResponse represents an invite in API responses
+type InviteResponse struct {
+	ID          uint   `json:"id"`
+	GroupID     uint   `json:"group_id"`
+	Email       string `json:"email,omitempty"`
+	Role        string `json:"role"`
+	TokenPrefix string `json:"token_prefix"`
+	ExpiresAt   string `json:"expires_at,omitempty"`
+	MaxUses     int    `json:"max_uses"`
+	Uses        int    `json:"uses"`
+	Status      string `json:"status"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// CreateInviteResponse includes the invite token, which is only shown once
+type CreateInviteResponse struct {
+	InviteResponse
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// inviteStatus describes where an invite is in its life
+func inviteStatus(invite *models.GroupInvite, now time.Time) string {
+	switch {
+	case invite.RevokedAt != nil:
+		return InviteStatusRevoked
+	case invite.AcceptedByID != nil:
+		return InviteStatusAccepted
+	case invite.MaxUses > 0 && invite.Uses >= invite.MaxUses:
+		return InviteStatusUsedUp
+	case invite.ExpiresAt != nil && !invite.ExpiresAt.After(now):
+		return InviteStatusExpired
+	}
+	return InviteStatusPending
+}
+
+func inviteToResponse(invite *models.GroupInvite) InviteResponse {
+	response := InviteResponse{
+		ID:          invite.ID,
+		GroupID:     invite.GroupID,
+		Email:       invite.Email,
+		Role:        string(invite.Role),
+		TokenPrefix: invite.TokenPrefix,
+		MaxUses:     invite.MaxUses,
+		Uses:        invite.Uses,
+		Status:      inviteStatus(invite, time.Now()),
+		CreatedAt:   invite.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if invite.ExpiresAt != nil {
+		response.ExpiresAt = invite.ExpiresAt.Format("2006-01-02T15:04:05Z")
+	}
+	return response
+}
+
+// ListInvites returns a group's invites, newest first (admin only)
+// @Summary List group invites
+// @Description Get a group's email invites and invite links with their status (requires admin role in group)
+// @Tags groups
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {array} InviteResponse
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Security BearerAuth
+// @Router /groups/{id}/invites [get]
+func (h *InviteHandler) ListInvites(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	if !access.IsGroupAdmin(h.db, userID, uint(groupID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var groupInvites []models.GroupInvite
+	if err := h.db.Where("group_id = ?", groupID).Order("created_at DESC, id DESC").Find(&groupInvites).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invites"})
+		return
+	}
+
+	response := make([]InviteResponse, len(groupInvites))
+	for i := range groupInvites {
+		response[i] = inviteToResponse(&groupInvites[i])
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateInvite invites an email address to a group, or creates an invite link (admin only)
+// @Summary Create a group invite
+// @Description Invite an email address to the group, or leave out the email to create an invite link that can be used max_uses times. Email invites are mailed to the invitee and accepted when they accept the invite, register with its token, or log in via OIDC with the verified address. The token is only returned once.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param request body CreateInviteRequest true "Invite details"
+// @Success 201 {object} CreateInviteResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 409 {object} map[string]string "Already a member"
+// @Security BearerAuth
+// @Router /groups/{id}/invites [post]
+func (h *InviteHandler) CreateInvite(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	if !access.IsGroupAdmin(h.db, userID, uint(groupID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var req CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var group models.Group
+	if err := h.db.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	invite := models.GroupInvite{
+		GroupID:     group.ID,
+		Email:       req.Email,
+		Role:        models.GroupRoleMember,
+		MaxUses:     req.MaxUses,
+		CreatedByID: userID,
+	}
+	if req.Role != "" {
+		invite.Role = models.GroupRole(req.Role)
+	}
+
+	expiresAt := time.Now().Add(DefaultInviteExpiry)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+			return
+		}
+		expiresAt = *req.ExpiresAt
+	}
+	invite.ExpiresAt = &expiresAt
+
+	if req.Email != "" {
+		// An email invite is used once
+		invite.MaxUses = 1
+
+		var count int64
+		h.db.Model(&models.GroupMembership{}).
+			Joins("JOIN users ON users.id = group_memberships.user_id").
+			Where("group_memberships.group_id = ? AND LOWER(users.email) = ?", group.ID, strings.ToLower(req.Email)).
+			Count(&count)
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "User is already a member"})
+			return
+		}
+	}
+
+	token, err := invites.Create(h.db, &invite)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invite"})
+		return
+	}
+	url := invites.AcceptURL(h.baseURL, token)
+
+	if invite.Email != "" {
+		var inviter models.User
+		h.db.First(&inviter, userID)
+		if err := h.sender.Send(invites.Message(&invite, group.Name, inviter.Name, url)); err != nil {
+			log.Printf("Warning: Failed to send invite to %s: %v", invite.Email, err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, CreateInviteResponse{
+		InviteResponse: inviteToResponse(&invite),
+		Token:          token,
+		URL:            url,
+	})
+}
+
+// RevokeInvite revokes an invite so it can no longer be accepted (admin only)
+// @Summary Revoke a group invite
+// @Description Revoke an email invite or invite link (requires admin role in group). Members who already joined stay in the group.
+// @Tags groups
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param inviteId path int true "Invite ID"
+// @Success 200 {object} InviteResponse
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 404 {object} map[string]string "Invite not found"
+// @Security BearerAuth
+// @Router /groups/{id}/invites/{inviteId} [delete]
+func (h *InviteHandler) RevokeInvite(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	if !access.IsGroupAdmin(h.db, userID, uint(groupID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var invite models.GroupInvite
+	if err := h.db.Where("id = ? AND group_id = ?", c.Param("inviteId"), groupID).First(&invite).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+		return
+	}
+
+	if invite.RevokedAt == nil {
+		now := time.Now()
+		invite.RevokedAt = &now
+		if err := h.db.Save(&invite).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke invite"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, inviteToResponse(&invite))
+}
+
+// AcceptInvite joins the current user to the group an invite is for
+// @Summary Accept a group invite
+// @Description Join a group with an invite token, with the invited role. Email invites can only be accepted by the invited address. Members already in the group keep their role.
+// @Tags groups
+// @Produce json
+// @Param token path string true "Invite token"
+// @Success 200 {object} GroupResponse
+// @Failure 403 {object} map[string]string "Invite is for a different email address"
+// @Failure 404 {object} map[string]string "Invite not found"
+// @Security BearerAuth
+// @Router /invites/{token}/accept [post]
+func (h *InviteHandler) AcceptInvite(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	var user models.User
+	if err := h.db.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	invite, err := invites.Find(h.db, c.Param("token"))
+	if err == nil {
+		err = invites.Accept(h.db, invite, &user)
+	}
+	switch err {
+	case nil:
+	case invites.ErrWrongEmail:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invite is for a different email address"})
+		return
+	case invites.ErrInvalid:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invite not found or no longer valid"})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept invite"})
+		return
+	}
+
+	var memberCount int64
+	h.db.Model(&models.GroupMembership{}).Where("group_id = ?", invite.GroupID).Count(&memberCount)
+
+	c.JSON(http.StatusOK, GroupResponse{
+		ID:           invite.Group.ID,
+		Name:         invite.Group.Name,
+		Description:  invite.Group.Description,
+		Namespace:    invite.Group.Namespace,
+		PublicSlug:   invite.Group.PublicSlug,
+		IsPublicPage: invite.Group.IsPublicPage,
+		ParentID:     invite.Group.ParentID,
+		Role:         string(access.GroupRole(h.db, userID, invite.GroupID)),
+		MemberCount:  int(memberCount),
+	})
+}
+
+// RegisterRoutes registers the invite management routes on the groups router
+func (h *InviteHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/:id/invites", h.ListInvites)
+	rg.POST("/:id/invites", h.CreateInvite)
+	rg.DELETE("/:id/invites/:inviteId", h.RevokeInvite)
+}
+
+// RegisterAcceptRoutes registers the route for accepting invites
+func (h *InviteHandler) RegisterAcceptRoutes(rg *gin.RouterGroup) {
+	rg.POST("/invites/:token/accept", h.AcceptInvite)
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
 	"github.com/mikepea/shorty/pkg/shorty/models"
 )
@@ -20,12 +21,12 @@ type MemberResponse struct {
 // AddMemberRequest represents a request to add a member
 type AddMemberRequest struct {
 	Email string `json:"email" binding:"required,email"`
-	Role  string `json:"role" binding:"required,oneof=admin member"`
+	Role  string `json:"role" binding:"required,oneof=admin editor member contributor viewer"`
 }
 
 // UpdateMemberRequest represents a request to update a member's role
 type UpdateMemberRequest struct {
-	Role string `json:"role" binding:"required,oneof=admin member"`
+	Role string `json:"role" binding:"required,oneof=admin editor member contributor viewer"`
 }
 
 // ListMembers returns all members of a group
@@ -37,8 +38,8 @@ func (h *Handler) ListMembers(c *gin.Context) {
 		return
 	}
 
-	// Check membership
-	if err := h.db.Where("user_id = ? AND group_id = ?", userID, groupID).First(&models.GroupMembership{}).Error; err != nil {
+	// Check membership, which may come from a group above this one
+	if err := access.Authorize(h.db, userID, uint(groupID), access.ActionView); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
 		return
 	}
@@ -71,8 +72,8 @@ func (h *Handler) AddMember(c *gin.Context) {
 		return
 	}
 
-	// Check admin membership
-	if err := h.db.Where("user_id = ? AND group_id = ? AND role = ?", userID, groupID, models.GroupRoleAdmin).First(&models.GroupMembership{}).Error; err != nil {
+	// Check admin membership, which may come from a group above this one
+	if !access.IsGroupAdmin(h.db, userID, uint(groupID)) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 		return
 	}
@@ -131,8 +132,8 @@ func (h *Handler) UpdateMember(c *gin.Context) {
 		return
 	}
 
-	// Check admin membership
-	if err := h.db.Where("user_id = ? AND group_id = ? AND role = ?", userID, groupID, models.GroupRoleAdmin).First(&models.GroupMembership{}).Error; err != nil {
+	// Check admin membership, which may come from a group above this one
+	if !access.IsGroupAdmin(h.db, userID, uint(groupID)) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 		return
 	}
@@ -179,8 +180,8 @@ func (h *Handler) RemoveMember(c *gin.Context) {
 		return
 	}
 
-	// Check admin membership
-	if err := h.db.Where("user_id = ? AND group_id = ? AND role = ?", userID, groupID, models.GroupRoleAdmin).First(&models.GroupMembership{}).Error; err != nil {
+	// Check admin membership, which may come from a group above this one
+	if !access.IsGroupAdmin(h.db, userID, uint(groupID)) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 		return
 	}
@@ -0,0 +1,215 @@
+package groups
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/tags"
+)
+
+// MaxSlugPrefix is the longest slug prefix a group can set
+const MaxSlugPrefix = 20
+
+var (
+	slugPrefixRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]*$`)
+	domainRegex     = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`)
+)
+
+// LinkPolicyResponse represents a group's link defaults and rules in API responses
+type LinkPolicyResponse struct {
+	DefaultIsPublic   bool     `json:"default_is_public"`
+	DefaultTags       []string `json:"default_tags"`
+	DefaultExpiryDays int      `json:"default_expiry_days"`
+	AllowedDomains    []string `json:"allowed_domains"`
+	RequireTitle      bool     `json:"require_title"`
+	SlugPrefix        string   `json:"slug_prefix"`
+}
+
+// UpdateLinkPolicyRequest represents a request to change a group's link
+// defaults and rules. Fields left out are unchanged.
+type UpdateLinkPolicyRequest struct {
+	DefaultIsPublic   *bool     `json:"default_is_public"`                                      // Visibility of new links that don't choose one
+	DefaultTags       *[]string `json:"default_tags"`                                           // Tags added to every new link
+	DefaultExpiryDays *int      `json:"default_expiry_days" binding:"omitempty,min=0,max=3650"` // 0 means new links don't expire
+	AllowedDomains    *[]string `json:"allowed_domains"`                                        // Destination domains, including subdomains; empty allows any
+	RequireTitle      *bool     `json:"require_title"`
+	SlugPrefix        *string   `json:"slug_prefix"` // New slugs must start with this, e.g. "sre-"
+}
+
+func policyToResponse(group *models.Group) LinkPolicyResponse {
+	response := LinkPolicyResponse{
+		DefaultIsPublic:   group.DefaultIsPublic,
+		DefaultTags:       group.DefaultTagList(),
+		DefaultExpiryDays: group.DefaultExpiryDays,
+		AllowedDomains:    group.AllowedDomainList(),
+		RequireTitle:      group.RequireTitle,
+		SlugPrefix:        group.SlugPrefix,
+	}
+	if response.DefaultTags == nil {
+		response.DefaultTags = []string{}
+	}
+	if response.AllowedDomains == nil {
+		response.AllowedDomains = []string{}
+	}
+	return response
+}
+
+// normalizeDomains lowercases and checks a list of allowed domains, dropping
+// duplicates. It returns an error message for the first invalid domain.
+func normalizeDomains(domains []string) ([]string, string) {
+	var normalized []string
+	seen := map[string]bool{}
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "*.")
+		if domain == "" || seen[domain] {
+			continue
+		}
+		if !domainRegex.MatchString(domain) {
+			return nil, "Invalid domain '" + domain + "': use a host name like example.com, without a scheme or path"
+		}
+		seen[domain] = true
+		normalized = append(normalized, domain)
+	}
+	return normalized, ""
+}
+
+// normalizeTags tidies a list of default tags, dropping duplicates. It returns
+// an error message for a tag that can't be stored.
+func normalizeTags(names []string) ([]string, string) {
+	var normalized []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = tags.Normalize(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if strings.Contains(name, ",") {
+			return nil, "Tag names can't contain commas"
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized, ""
+}
+
+// GetPolicy returns the defaults and rules for links in a group
+// @Summary Get a group's link policy
+// @Description Get the defaults applied to new links in the group and the rules they must follow
+// @Tags groups
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {object} LinkPolicyResponse
+// @Failure 404 {object} map[string]string "Group not found"
+// @Security BearerAuth
+// @Router /groups/{id}/policy [get]
+func (h *Handler) GetPolicy(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	// Members can see the rules their links have to follow
+	if err := access.Authorize(h.db, userID, uint(groupID), access.ActionView); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	var group models.Group
+	if err := h.db.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, policyToResponse(&group))
+}
+
+// UpdatePolicy changes the defaults and rules for links in a group (admin only)
+// @Summary Update a group's link policy
+// @Description Set the defaults applied to new and imported links in the group (visibility, tags and expiry) and the rules they must follow (allowed destination domains, a required title and a slug prefix). Existing links are unchanged. Requires admin role in group.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param request body UpdateLinkPolicyRequest true "Policy changes"
+// @Success 200 {object} LinkPolicyResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Security BearerAuth
+// @Router /groups/{id}/policy [put]
+func (h *Handler) UpdatePolicy(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	// Check admin membership, which may come from a group above this one
+	if !access.IsGroupAdmin(h.db, userID, uint(groupID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var req UpdateLinkPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var group models.Group
+	if err := h.db.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	if req.DefaultIsPublic != nil {
+		group.DefaultIsPublic = *req.DefaultIsPublic
+	}
+	if req.DefaultTags != nil {
+		names, msg := normalizeTags(*req.DefaultTags)
+		if msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+		group.DefaultTags = strings.Join(names, ",")
+	}
+	if req.DefaultExpiryDays != nil {
+		group.DefaultExpiryDays = *req.DefaultExpiryDays
+	}
+	if req.AllowedDomains != nil {
+		domains, msg := normalizeDomains(*req.AllowedDomains)
+		if msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+		group.AllowedDomains = strings.Join(domains, ",")
+	}
+	if req.RequireTitle != nil {
+		group.RequireTitle = *req.RequireTitle
+	}
+	if req.SlugPrefix != nil {
+		prefix := strings.TrimSpace(*req.SlugPrefix)
+		if len(prefix) > MaxSlugPrefix || !slugPrefixRegex.MatchString(prefix) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Slug prefix must be up to 20 letters, numbers, hyphens, and underscores"})
+			return
+		}
+		group.SlugPrefix = prefix
+	}
+
+	// Save with Select so that false and zero values are written too
+	if err := h.db.Model(&group).Select("DefaultIsPublic", "DefaultTags", "DefaultExpiryDays", "AllowedDomains", "RequireTitle", "SlugPrefix").
+		Updates(&group).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update link policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, policyToResponse(&group))
+}
@@ -0,0 +1,21 @@
+// Package history records changes made to links in bulk, such as tag renames
+// and merges by organization admins, so they show up in each link's history.
+package history
+
+import (
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// Record adds an entry to the history of each of the links
+func Record(db *gorm.DB, linkIDs []uint, actorID uint, action, detail string) error {
+	if len(linkIDs) == 0 {
+		return nil
+	}
+
+	events := make([]models.LinkEvent, len(linkIDs))
+	for i, linkID := range linkIDs {
+		events[i] = models.LinkEvent{LinkID: linkID, ActorID: actorID, Action: action, Detail: detail}
+	}
+	return db.CreateInBatches(events, 200).Error
+}
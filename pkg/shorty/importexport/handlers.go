@@ -8,19 +8,26 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/links"
 	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/slugs"
+	"github.com/mikepea/shorty/pkg/shorty/tagquery"
+	"github.com/mikepea/shorty/pkg/shorty/tags"
+	"github.com/mikepea/shorty/pkg/shorty/urlcanon"
 	"gorm.io/gorm"
 )
 
 // Handler handles import/export requests
 type Handler struct {
-	db *gorm.DB
+	db      *gorm.DB
+	slugGen *slugs.Generator
 }
 
 // NewHandler creates a new import/export handler
 func NewHandler(db *gorm.DB) *Handler {
-	return &Handler{db: db}
+	return &Handler{db: db, slugGen: slugs.NewGenerator(db)}
 }
 
 // PinboardBookmark represents a bookmark in Pinboard JSON format
@@ -44,9 +51,10 @@ type ImportRequest struct {
 
 // ImportResult represents the result of an import operation
 type ImportResult struct {
-	Imported int      `json:"imported"`
-	Skipped  int      `json:"skipped"`
-	Errors   []string `json:"errors,omitempty"`
+	Imported   int      `json:"imported"`
+	Skipped    int      `json:"skipped"`
+	Duplicates int      `json:"duplicates"` // Imported links whose destination already existed in the organization
+	Errors     []string `json:"errors,omitempty"`
 }
 
 // ExportBookmark represents a bookmark for export
@@ -60,52 +68,6 @@ type ExportBookmark struct {
 	ToRead      string `json:"toread"`
 }
 
-// checkGroupMembership verifies the user is a member of the group
-func (h *Handler) checkGroupMembership(userID, groupID uint) error {
-	var membership models.GroupMembership
-	if err := h.db.Where("user_id = ? AND group_id = ?", userID, groupID).First(&membership).Error; err != nil {
-		return err
-	}
-	return nil
-}
-
-// getUserGroupIDs returns all group IDs the user is a member of
-func (h *Handler) getUserGroupIDs(userID uint) ([]uint, error) {
-	var memberships []models.GroupMembership
-	if err := h.db.Where("user_id = ?", userID).Find(&memberships).Error; err != nil {
-		return nil, err
-	}
-
-	groupIDs := make([]uint, len(memberships))
-	for i, m := range memberships {
-		groupIDs[i] = m.GroupID
-	}
-	return groupIDs, nil
-}
-
-// generateSlug generates a unique slug for a link
-func (h *Handler) generateSlug() (string, error) {
-	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
-	const length = 8
-
-	for attempts := 0; attempts < 10; attempts++ {
-		slug := make([]byte, length)
-		for i := range slug {
-			slug[i] = charset[time.Now().UnixNano()%int64(len(charset))]
-			time.Sleep(time.Nanosecond)
-		}
-
-		// Check uniqueness
-		var count int64
-		h.db.Model(&models.Link{}).Where("slug = ?", string(slug)).Count(&count)
-		if count == 0 {
-			return string(slug), nil
-		}
-	}
-
-	return "", nil
-}
-
 // Import imports bookmarks from Pinboard JSON format
 func (h *Handler) Import(c *gin.Context) {
 	userID, _ := auth.GetUserID(c)
@@ -116,12 +78,27 @@ func (h *Handler) Import(c *gin.Context) {
 		return
 	}
 
-	// Check group membership
-	if err := h.checkGroupMembership(userID, req.GroupID); err != nil {
+	// Check the user's role lets them add links
+	switch access.Authorize(h.db, userID, req.GroupID, access.ActionCreate) {
+	case nil:
+	case access.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your role in this group doesn't allow adding links"})
+		return
+	default:
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
 		return
 	}
 
+	var group models.Group
+	if err := h.db.First(&group, req.GroupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	var org models.Organization
+	h.db.First(&org, group.OrganizationID)
+	trackingParams := org.TrackingParamList()
+
 	result := ImportResult{
 		Errors: []string{},
 	}
@@ -145,34 +122,59 @@ func (h *Handler) Import(c *gin.Context) {
 			createdAt = time.Now()
 		}
 
-		// Generate slug
-		slug, err := h.generateSlug()
-		if err != nil || slug == "" {
-			result.Errors = append(result.Errors, "bookmark "+strconv.Itoa(i)+": failed to generate slug")
+		// The group's rules apply to imported links too
+		if group.RequireTitle && strings.TrimSpace(bookmark.Description) == "" {
+			result.Errors = append(result.Errors, "bookmark "+strconv.Itoa(i)+": this group requires a title for new links")
+			result.Skipped++
+			continue
+		}
+		if !group.AllowsURL(bookmark.Href) {
+			result.Errors = append(result.Errors, "bookmark "+strconv.Itoa(i)+": links in this group must point to "+strings.Join(group.AllowedDomainList(), ", "))
 			result.Skipped++
 			continue
 		}
 
-		// Determine visibility
+		// Determine visibility, falling back to the group's default
 		isPublic := bookmark.Shared == "yes"
+		if bookmark.Shared == "" {
+			isPublic = group.DefaultIsPublic
+		}
 
 		// Determine unread status
 		isUnread := bookmark.ToRead == "yes"
 
+		// Check for existing links to the same destination
+		canonicalHash := urlcanon.Hash(bookmark.Href, trackingParams)
+		if canonicalHash != "" {
+			var count int64
+			h.db.Model(&models.Link{}).Where("organization_id = ? AND canonical_hash = ?", group.OrganizationID, canonicalHash).Count(&count)
+			if count > 0 {
+				result.Duplicates++
+			}
+		}
+
 		// Create link
 		link := models.Link{
-			GroupID:     req.GroupID,
-			CreatedByID: userID,
-			Slug:        slug,
-			URL:         bookmark.Href,
-			Title:       bookmark.Description,
-			Description: bookmark.Extended,
-			IsPublic:    isPublic,
-			IsUnread:    isUnread,
+			OrganizationID: group.OrganizationID,
+			GroupID:        req.GroupID,
+			CreatedByID:    userID,
+			URL:            bookmark.Href,
+			CanonicalHash:  canonicalHash,
+			Title:          bookmark.Description,
+			Description:    bookmark.Extended,
+			IsPublic:       isPublic,
+			IsUnread:       isUnread,
+			ExpiresAt:      group.DefaultExpiry(time.Now()),
 		}
 		link.CreatedAt = createdAt
 
-		if err := h.db.Create(&link).Error; err != nil {
+		// The slug is generated using the organization's strategy and the
+		// group's slug prefix, skipping slugs the user may not claim directly.
+		// Pinboard bookmarks have no slug of their own.
+		if err := h.slugGen.CreateLinkExcept(&link, group.SlugPrefix, func(slug string) bool {
+			rule, err := links.Protection(h.db, userID, group.OrganizationID, slug)
+			return rule != nil || err != nil
+		}); err != nil {
 			result.Errors = append(result.Errors, "bookmark "+strconv.Itoa(i)+": "+err.Error())
 			result.Skipped++
 			continue
@@ -185,30 +187,12 @@ func (h *Handler) Import(c *gin.Context) {
 			"is_unread": isUnread,
 		})
 
-		// Handle tags
-		if bookmark.Tags != "" {
-			tagNames := strings.Fields(bookmark.Tags)
-			var tags []models.Tag
-
-			for _, tagName := range tagNames {
-				tagName = strings.TrimSpace(tagName)
-				if tagName == "" {
-					continue
-				}
-
-				var tag models.Tag
-				if err := h.db.Where("name = ?", tagName).First(&tag).Error; err != nil {
-					// Create new tag
-					tag = models.Tag{Name: tagName}
-					if err := h.db.Create(&tag).Error; err != nil {
-						continue
-					}
-				}
-				tags = append(tags, tag)
-			}
-
-			if len(tags) > 0 {
-				h.db.Model(&link).Association("Tags").Append(tags)
+		// Handle tags, which belong to the group's organization, along with
+		// the group's default tags
+		if names := append(strings.Fields(bookmark.Tags), group.DefaultTagList()...); len(names) > 0 {
+			linkTags, err := tags.FindOrCreate(h.db, group.OrganizationID, names)
+			if err == nil && len(linkTags) > 0 {
+				h.db.Model(&link).Association("Tags").Append(linkTags)
 			}
 		}
 
@@ -224,7 +208,7 @@ func (h *Handler) Export(c *gin.Context) {
 
 	// Get optional group_id parameter
 	groupIDStr := c.Query("group_id")
-	var groupIDs []uint
+	query := h.db.Preload("Tags")
 
 	if groupIDStr != "" {
 		groupID, err := strconv.ParseUint(groupIDStr, 10, 32)
@@ -234,30 +218,31 @@ func (h *Handler) Export(c *gin.Context) {
 		}
 
 		// Check membership
-		if err := h.checkGroupMembership(userID, uint(groupID)); err != nil {
+		if err := access.Authorize(h.db, userID, uint(groupID), access.ActionView); err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
 			return
 		}
 
-		groupIDs = []uint{uint(groupID)}
+		query = query.Where("group_id = ?", groupID)
 	} else {
-		// Export from all user's groups
-		var err error
-		groupIDs, err = h.getUserGroupIDs(userID)
+		// Export from all user's groups, and links shared with the user
+		query = query.Scopes(access.Visible(h.db, userID))
+	}
+
+	// Optional tag expression, like tag=oncall AND NOT tag=deprecated
+	if tagQuery := c.Query("tag_query"); tagQuery != "" {
+		expr, err := tagquery.Parse(tagQuery)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-	}
-
-	if len(groupIDs) == 0 {
-		c.JSON(http.StatusOK, []ExportBookmark{})
-		return
+		sql, args := expr.SQL()
+		query = query.Where(sql, args...)
 	}
 
 	// Fetch links with tags
 	var links []models.Link
-	if err := h.db.Preload("Tags").Where("group_id IN ?", groupIDs).Order("created_at DESC").Find(&links).Error; err != nil {
+	if err := query.Order("created_at DESC").Find(&links).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch links"})
 		return
 	}
@@ -312,11 +297,9 @@ func (h *Handler) ExportSingle(c *gin.Context) {
 	}
 
 	// Check access
-	if !link.IsPublic {
-		if err := h.checkGroupMembership(userID, link.GroupID); err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
-			return
-		}
+	if !access.CanView(h.db, userID, &link) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
 	}
 
 	// Convert tags to space-separated string
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -185,6 +187,41 @@ func TestImportBookmarksNotMember(t *testing.T) {
 	}
 }
 
+func TestImportBookmarksViewer(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	owner := createTestUser(t, db, "owner@example.com")
+	viewer := createTestUser(t, db, "viewer@example.com")
+	group := createTestGroup(t, db, "Test Group", owner.ID)
+	db.Create(&models.GroupMembership{UserID: viewer.ID, GroupID: group.ID, Role: models.GroupRoleViewer})
+
+	req := ImportRequest{
+		GroupID:   group.ID,
+		Bookmarks: []PinboardBookmark{{Href: "https://example.com", Description: "Example Site"}},
+	}
+	jsonBody, _ := json.Marshal(req)
+
+	httpReq, _ := http.NewRequest("POST", "/api/import", bytes.NewBuffer(jsonBody))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", getAuthHeader(viewer))
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, httpReq)
+
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+
+	// Viewers can still export
+	httpReq, _ = http.NewRequest("GET", "/api/export", nil)
+	httpReq.Header.Set("Authorization", getAuthHeader(viewer))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httpReq)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 exporting, got %d", resp.Code)
+	}
+}
+
 func TestExportBookmarks(t *testing.T) {
 	db := setupTestDB(t)
 	router := setupTestRouter(db)
@@ -253,6 +290,50 @@ func TestExportBookmarksByGroup(t *testing.T) {
 	}
 }
 
+func TestExportBookmarksByTagQuery(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+
+	link1 := createTestLink(t, db, group.ID, user.ID, "link1", "https://example.com")
+	link2 := createTestLink(t, db, group.ID, user.ID, "link2", "https://golang.org")
+
+	golang := models.Tag{Name: "golang"}
+	deprecated := models.Tag{Name: "deprecated"}
+	db.Create(&golang)
+	db.Create(&deprecated)
+	db.Model(&link1).Association("Tags").Append(&golang)
+	db.Model(&link2).Association("Tags").Append(&golang, &deprecated)
+
+	httpReq, _ := http.NewRequest("GET", "/api/export?tag_query="+url.QueryEscape("tag=golang AND NOT tag=deprecated"), nil)
+	httpReq.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, httpReq)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var bookmarks []ExportBookmark
+	json.Unmarshal(resp.Body.Bytes(), &bookmarks)
+
+	if len(bookmarks) != 1 || bookmarks[0].Href != "https://example.com" {
+		t.Errorf("Expected only link1, got %+v", bookmarks)
+	}
+
+	httpReq, _ = http.NewRequest("GET", "/api/export?tag_query="+url.QueryEscape("tag=golang AND ("), nil)
+	httpReq.Header.Set("Authorization", getAuthHeader(user))
+	resp = httptest.NewRecorder()
+
+	router.ServeHTTP(resp, httpReq)
+
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.Code)
+	}
+}
+
 func TestExportSingleBookmark(t *testing.T) {
 	db := setupTestDB(t)
 	router := setupTestRouter(db)
@@ -428,3 +509,94 @@ func TestImportVisibilityAndUnread(t *testing.T) {
 		t.Error("Expected private link to be unread")
 	}
 }
+
+func TestImportAppliesGroupPolicy(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+	db.Model(&group).Updates(map[string]interface{}{
+		"default_is_public":   true,
+		"default_tags":        "imported",
+		"default_expiry_days": 7,
+		"allowed_domains":     "example.com",
+		"require_title":       true,
+		"slug_prefix":         "team-",
+	})
+
+	req := ImportRequest{
+		GroupID: group.ID,
+		Bookmarks: []PinboardBookmark{
+			{Href: "https://docs.example.com", Description: "Docs", Tags: "reference"},
+			{Href: "https://private.example.com", Description: "Private", Shared: "no"},
+			{Href: "https://example.com/untitled"},
+			{Href: "https://elsewhere.net", Description: "Elsewhere"},
+		},
+	}
+	jsonBody, _ := json.Marshal(req)
+
+	httpReq, _ := http.NewRequest("POST", "/api/import", bytes.NewBuffer(jsonBody))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, httpReq)
+
+	var result ImportResult
+	json.Unmarshal(resp.Body.Bytes(), &result)
+	if result.Imported != 2 || result.Skipped != 2 || len(result.Errors) != 2 {
+		t.Fatalf("Expected 2 imported and 2 skipped, got %+v", result)
+	}
+	if result.Errors[0] != "bookmark 2: this group requires a title for new links" ||
+		result.Errors[1] != "bookmark 3: links in this group must point to example.com" {
+		t.Errorf("Unexpected errors: %v", result.Errors)
+	}
+
+	var docs models.Link
+	db.Preload("Tags").Where("url = ?", "https://docs.example.com").First(&docs)
+	if !docs.IsPublic || docs.ExpiresAt == nil || !strings.HasPrefix(docs.Slug, "team-") || len(docs.Tags) != 2 {
+		t.Errorf("Expected the group's defaults on the imported link, got %+v", docs)
+	}
+
+	// An explicit shared value wins over the default visibility
+	var private models.Link
+	db.Where("url = ?", "https://private.example.com").First(&private)
+	if private.IsPublic {
+		t.Error("Expected the unshared bookmark to stay private")
+	}
+}
+
+func TestImportSkipsProtectedSlugs(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	approver := createTestUser(t, db, "approver@example.com")
+	db.Create(&models.Organization{ID: 1, Name: "Acme", Slug: "acme", SlugStrategy: "title"})
+	group := createTestGroup(t, db, "Test Group", user.ID)
+	db.Model(&group).Update("organization_id", 1)
+	db.Create(&models.ProtectedSlug{OrganizationID: 1, Pattern: "status", CreatedByID: approver.ID, Approvers: []models.User{approver}})
+
+	jsonBody, _ := json.Marshal(ImportRequest{
+		GroupID:   group.ID,
+		Bookmarks: []PinboardBookmark{{Href: "https://example.com/status", Description: "Status"}},
+	})
+	httpReq, _ := http.NewRequest("POST", "/api/import", bytes.NewBuffer(jsonBody))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, httpReq)
+
+	var result ImportResult
+	json.Unmarshal(resp.Body.Bytes(), &result)
+	if result.Imported != 1 {
+		t.Fatalf("Expected 1 imported, got %+v", result)
+	}
+
+	// The generated slug avoids the protected one
+	var link models.Link
+	db.Where("url = ?", "https://example.com/status").First(&link)
+	if link.Slug == "status" || link.Slug == "" {
+		t.Errorf("Expected a slug other than the protected one, got %q", link.Slug)
+	}
+}
@@ -0,0 +1,157 @@
+// Package invites creates and accepts group invitations. A group admin
+// invites an email address, or shares an invite link that works until it
+// expires or runs out of uses. Invitees join the group with the invited role
+// when they accept or register with the invite token, and email invites are
+// accepted automatically when the invitee logs in via OIDC with a verified
+// address.
+package invites
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mikepea/shorty/pkg/shorty/mail"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// TokenLength is the number of random bytes in an invite token
+const TokenLength = 32
+
+var (
+	// ErrInvalid is returned for unknown, revoked, expired and used up invites
+	ErrInvalid = errors.New("invite is invalid, expired or revoked")
+	// ErrWrongEmail is returned when an email invite is accepted by someone else
+	ErrWrongEmail = errors.New("invite is for a different email address")
+)
+
+// hashToken creates a SHA-256 hash of an invite token
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// Create saves an invite with a new token and returns the token, which is
+// only stored hashed
+func Create(db *gorm.DB, invite *models.GroupInvite) (string, error) {
+	tokenBytes := make([]byte, TokenLength)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	invite.Email = strings.ToLower(strings.TrimSpace(invite.Email))
+	invite.TokenHash = hashToken(token)
+	invite.TokenPrefix = token[:8]
+	if err := db.Create(invite).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// IsUsable reports whether an invite can still be accepted
+func IsUsable(invite *models.GroupInvite, now time.Time) bool {
+	return invite.RevokedAt == nil && invite.AcceptedByID == nil &&
+		(invite.ExpiresAt == nil || invite.ExpiresAt.After(now)) &&
+		(invite.MaxUses == 0 || invite.Uses < invite.MaxUses)
+}
+
+// Find looks up a usable invite by its token
+func Find(db *gorm.DB, token string) (*models.GroupInvite, error) {
+	var invite models.GroupInvite
+	if err := db.Preload("Group").Where("token_hash = ?", hashToken(token)).First(&invite).Error; err != nil {
+		return nil, ErrInvalid
+	}
+	if !IsUsable(&invite, time.Now()) {
+		return nil, ErrInvalid
+	}
+	return &invite, nil
+}
+
+// Accept adds the user to the invite's group with the invited role and uses
+// up the invite. Users already in the group keep their role and don't use
+// up the invite.
+func Accept(db *gorm.DB, invite *models.GroupInvite, user *models.User) error {
+	if invite.Email != "" && !strings.EqualFold(invite.Email, user.Email) {
+		return ErrWrongEmail
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		tx.Model(&models.GroupMembership{}).Where("user_id = ? AND group_id = ?", user.ID, invite.GroupID).Count(&count)
+		if count > 0 {
+			return nil
+		}
+
+		// Count the use in the same statement that checks one is left, so two
+		// people can't both take the last use of an invite link
+		updates := map[string]interface{}{"uses": gorm.Expr("uses + 1")}
+		if invite.Email != "" {
+			updates["accepted_by_id"] = user.ID
+		}
+		result := tx.Model(&models.GroupInvite{}).
+			Where("id = ? AND revoked_at IS NULL AND accepted_by_id IS NULL AND (max_uses = 0 OR uses < max_uses)", invite.ID).
+			Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalid
+		}
+
+		return tx.Create(&models.GroupMembership{
+			UserID:  user.ID,
+			GroupID: invite.GroupID,
+			Role:    invite.Role,
+		}).Error
+	})
+}
+
+// AcceptPending accepts every usable email invite for the user's address
+func AcceptPending(db *gorm.DB, user *models.User) error {
+	var pending []models.GroupInvite
+	if err := db.Where("email = ? AND revoked_at IS NULL AND accepted_by_id IS NULL", strings.ToLower(user.Email)).
+		Find(&pending).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range pending {
+		if !IsUsable(&pending[i], now) {
+			continue
+		}
+		if err := Accept(db, &pending[i], user); err != nil && err != ErrInvalid {
+			return err
+		}
+	}
+	return nil
+}
+
+// AcceptURL returns the page where an invite is accepted. Signing up there
+// with the token accepts the invite.
+func AcceptURL(baseURL, token string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/register?invite=" + token
+}
+
+// Message returns the email sent to the invitee of an email invite
+func Message(invite *models.GroupInvite, groupName, inviterName, acceptURL string) mail.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s has invited you to join %s on Shorty as %s.\n\n", inviterName, groupName, invite.Role)
+	fmt.Fprintf(&body, "Accept the invitation: %s\n", acceptURL)
+	if invite.ExpiresAt != nil {
+		fmt.Fprintf(&body, "\nThe invitation expires on %s.\n", invite.ExpiresAt.UTC().Format("2 January 2006 15:04 MST"))
+	}
+	fmt.Fprintf(&body, "\nIf you sign in with single sign-on as %s, the invitation is accepted the next time you log in.\n", invite.Email)
+
+	return mail.Message{
+		To:      []string{invite.Email},
+		Subject: fmt.Sprintf("You're invited to join %s", groupName),
+		Body:    body.String(),
+	}
+}
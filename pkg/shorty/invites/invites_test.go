@@ -0,0 +1,88 @@
+package invites
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	models.AutoMigrate(db)
+	return db
+}
+
+func TestIsUsable(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	acceptedBy := uint(1)
+
+	tests := []struct {
+		name   string
+		invite models.GroupInvite
+		want   bool
+	}{
+		{"pending", models.GroupInvite{ExpiresAt: &future}, true},
+		{"no expiry", models.GroupInvite{}, true},
+		{"expired", models.GroupInvite{ExpiresAt: &past}, false},
+		{"revoked", models.GroupInvite{RevokedAt: &past}, false},
+		{"accepted", models.GroupInvite{AcceptedByID: &acceptedBy}, false},
+		{"uses left", models.GroupInvite{MaxUses: 3, Uses: 2}, true},
+		{"used up", models.GroupInvite{MaxUses: 3, Uses: 3}, false},
+	}
+	for _, tt := range tests {
+		if got := IsUsable(&tt.invite, now); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestAcceptPending(t *testing.T) {
+	db := setupTestDB(t)
+	user := models.User{Email: "Invitee@Example.com", Name: "Invitee"}
+	db.Create(&user)
+
+	sre := models.Group{Name: "SRE"}
+	platform := models.Group{Name: "Platform"}
+	expired := models.Group{Name: "Expired"}
+	db.Create(&sre)
+	db.Create(&platform)
+	db.Create(&expired)
+
+	past := time.Now().Add(-time.Hour)
+	for _, invite := range []models.GroupInvite{
+		{GroupID: sre.ID, Email: "invitee@example.com", Role: models.GroupRoleContributor, MaxUses: 1},
+		{GroupID: platform.ID, Email: "someone@example.com", Role: models.GroupRoleMember, MaxUses: 1},
+		{GroupID: expired.ID, Email: "invitee@example.com", Role: models.GroupRoleMember, MaxUses: 1, ExpiresAt: &past},
+	} {
+		if _, err := Create(db, &invite); err != nil {
+			t.Fatalf("Failed to create invite: %v", err)
+		}
+	}
+
+	if err := AcceptPending(db, &user); err != nil {
+		t.Fatalf("AcceptPending failed: %v", err)
+	}
+
+	var memberships []models.GroupMembership
+	db.Where("user_id = ?", user.ID).Find(&memberships)
+	if len(memberships) != 1 || memberships[0].GroupID != sre.ID || memberships[0].Role != models.GroupRoleContributor {
+		t.Errorf("Expected to join SRE only as contributor, got %+v", memberships)
+	}
+
+	// Accepting again is a no-op
+	if err := AcceptPending(db, &user); err != nil {
+		t.Fatalf("AcceptPending failed: %v", err)
+	}
+	var count int64
+	db.Model(&models.GroupMembership{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected one membership, got %d", count)
+	}
+}
@@ -0,0 +1,116 @@
+package landing
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description,omitempty"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+}
+
+// atomFeed is an Atom 1.0 document
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+	Summary string   `xml:"summary,omitempty"`
+}
+
+func toRSS(page Page) rssFeed {
+	description := page.Description
+	if description == "" {
+		description = page.Title
+	}
+
+	feed := rssFeed{Version: "2.0", Channel: rssChannel{Title: page.Title, Link: page.URL, Description: description}}
+	for _, link := range page.Links {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       link.Title,
+			Link:        link.ShortURL,
+			Description: link.Description,
+			GUID:        link.ShortURL,
+			PubDate:     link.created.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return feed
+}
+
+func toAtom(page Page) atomFeed {
+	// The feed was last updated when its newest entry was
+	updated := time.Unix(0, 0)
+	for _, link := range page.Links {
+		if link.updated.After(updated) {
+			updated = link.updated
+		}
+	}
+
+	feed := atomFeed{
+		Title:    page.Title,
+		Subtitle: page.Description,
+		ID:       page.URL,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: page.URL, Rel: "alternate", Type: "text/html"},
+			{Href: page.URL + "?format=atom", Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+	for _, link := range page.Links {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   link.Title,
+			ID:      link.ShortURL,
+			Updated: link.updated.UTC().Format(time.RFC3339),
+			Link:    atomLink{Href: link.ShortURL},
+			Summary: link.Description,
+		})
+	}
+	return feed
+}
+
+// writeXML writes a feed with the XML declaration
+func writeXML(c *gin.Context, contentType string, feed interface{}) {
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	c.Writer.WriteString(xml.Header)
+	enc := xml.NewEncoder(c.Writer)
+	enc.Indent("", "  ")
+	enc.Encode(feed)
+}
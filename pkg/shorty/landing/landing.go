@@ -0,0 +1,213 @@
+// Package landing serves shareable, unauthenticated pages listing the public
+// links of a tag (/t/:tag) or a group (/g/:slug) as HTML, JSON, RSS or Atom.
+// Like redirects, the organization is resolved from the Host header.
+package landing
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/redirect"
+	"github.com/mikepea/shorty/pkg/shorty/tagquery"
+	"github.com/mikepea/shorty/pkg/shorty/tags"
+	"gorm.io/gorm"
+)
+
+// MaxLinks is the most links listed on a page or in a feed, newest first
+const MaxLinks = 100
+
+// Handler handles public landing page requests
+type Handler struct {
+	db *gorm.DB
+}
+
+// NewHandler creates a new landing page handler
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// PublicLink represents a link on a landing page
+type PublicLink struct {
+	Slug        string `json:"slug"`
+	ShortURL    string `json:"short_url"`
+	URL         string `json:"url,omitempty"` // Empty for pages
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+
+	created, updated time.Time
+}
+
+// Page represents a landing page in JSON responses
+type Page struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	URL         string       `json:"url"`
+	Links       []PublicLink `json:"links"`
+}
+
+// pageTemplate is the HTML rendering of a landing page
+var pageTemplate = template.Must(template.New("landing").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="alternate" type="application/rss+xml" title="{{.Title}}" href="{{.URL}}?format=rss">
+<link rel="alternate" type="application/atom+xml" title="{{.Title}}" href="{{.URL}}?format=atom">
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+ul { padding-left: 1.25rem; }
+li { margin: 0.5rem 0; }
+.description { color: #555; margin: 0.25rem 0; }
+.feeds { font-size: 0.875rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Description}}<p class="description">{{.Description}}</p>{{end}}
+<ul>
+{{range .Links}}<li><a href="{{.ShortURL}}">{{.Title}}</a>{{if .Description}}<p class="description">{{.Description}}</p>{{end}}</li>
+{{else}}<li>No public links yet.</li>
+{{end}}</ul>
+<p class="feeds"><a href="{{.URL}}?format=rss">RSS</a> · <a href="{{.URL}}?format=atom">Atom</a> · <a href="{{.URL}}?format=json">JSON</a></p>
+</body>
+</html>
+`))
+
+// baseURL returns the scheme and host the request was made to
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// publicLinks returns the newest public, unexpired links matching the query
+func publicLinks(query *gorm.DB, base string) ([]PublicLink, error) {
+	var links []models.Link
+	if err := query.
+		Where("links.is_public = ?", true).
+		Where("links.expires_at IS NULL OR links.expires_at > ?", time.Now()).
+		Order("links.created_at DESC").
+		Limit(MaxLinks).
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]PublicLink, len(links))
+	for i, link := range links {
+		title := link.Title
+		if title == "" {
+			title = link.Slug
+		}
+		result[i] = PublicLink{
+			Slug:        link.Slug,
+			ShortURL:    base + "/" + link.Slug,
+			Title:       title,
+			Description: link.Description,
+			CreatedAt:   link.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			UpdatedAt:   link.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+			created:     link.CreatedAt,
+			updated:     link.UpdatedAt,
+		}
+		if !link.IsPage() {
+			result[i].URL = link.URL
+		}
+	}
+	return result, nil
+}
+
+// render writes the page in the format asked for by the format query
+// parameter: html (the default), json, rss or atom
+func render(c *gin.Context, page Page) {
+	switch c.Query("format") {
+	case "json":
+		c.JSON(http.StatusOK, page)
+	case "rss":
+		writeXML(c, "application/rss+xml; charset=utf-8", toRSS(page))
+	case "atom":
+		writeXML(c, "application/atom+xml; charset=utf-8", toAtom(page))
+	default:
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		pageTemplate.Execute(c.Writer, page)
+	}
+}
+
+// TagPage lists the organization's public links with a tag, or any tag below
+// it, so /t/infra also lists links tagged infra/monitoring
+func (h *Handler) TagPage(c *gin.Context) {
+	orgID := redirect.ResolveOrganization(h.db, c.Request.Host)
+	name := tags.Normalize(strings.TrimPrefix(c.Param("tag"), "/"))
+	if name == "" {
+		c.String(http.StatusNotFound, "Tag not found")
+		return
+	}
+
+	base := baseURL(c)
+	sql, args := tagquery.Tag(name).SQL()
+	links, err := publicLinks(h.db.Model(&models.Link{}).Where("links.organization_id = ?", orgID).Where(sql, args...), base)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to load links")
+		return
+	}
+	// Tags only used on private or expired links look the same as unknown
+	// ones, so the page doesn't reveal which tag names exist
+	if len(links) == 0 {
+		c.String(http.StatusNotFound, "Tag not found")
+		return
+	}
+
+	// Tag names can have spaces, so each level is escaped
+	levels := strings.Split(name, tags.Separator)
+	for i, level := range levels {
+		levels[i] = url.PathEscape(level)
+	}
+
+	render(c, Page{
+		Title: "Links tagged " + name,
+		URL:   base + "/t/" + strings.Join(levels, "/"),
+		Links: links,
+	})
+}
+
+// GroupPage lists a group's public links, if a group admin has turned its
+// public page on
+func (h *Handler) GroupPage(c *gin.Context) {
+	orgID := redirect.ResolveOrganization(h.db, c.Request.Host)
+
+	var group models.Group
+	if err := h.db.Where("organization_id = ? AND public_slug = ? AND is_public_page = ?", orgID, c.Param("slug"), true).First(&group).Error; err != nil {
+		c.String(http.StatusNotFound, "Group not found")
+		return
+	}
+
+	base := baseURL(c)
+	links, err := publicLinks(h.db.Model(&models.Link{}).Where("links.group_id = ?", group.ID), base)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to load links")
+		return
+	}
+
+	render(c, Page{
+		Title:       group.Name,
+		Description: group.Description,
+		URL:         base + "/g/" + group.PublicSlug,
+		Links:       links,
+	})
+}
+
+// RegisterPublicRoutes registers the landing pages on the root router.
+// This must be called before the redirect routes.
+func (h *Handler) RegisterPublicRoutes(r *gin.Engine) {
+	r.GET("/t/*tag", h.TagPage)
+	r.GET("/g/:slug", h.GroupPage)
+}
@@ -0,0 +1,177 @@
+package landing
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/redirect"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	models.AutoMigrate(db)
+	return db
+}
+
+// setupTestData creates an organization on links.example.com with a group
+// and a mix of public, private and expired links
+func setupTestData(t *testing.T, db *gorm.DB) models.Group {
+	org := models.Organization{Name: "Example", Slug: "example"}
+	if err := db.Create(&org).Error; err != nil {
+		t.Fatalf("Failed to create organization: %v", err)
+	}
+	db.Create(&models.OrganizationDomain{OrganizationID: org.ID, Domain: "links.example.com"})
+	other := models.Organization{Name: "Other", Slug: "other", IsGlobal: true}
+	db.Create(&other)
+
+	group := models.Group{OrganizationID: org.ID, Name: "SRE <team>", Description: "Runbooks and dashboards", PublicSlug: "sre"}
+	db.Create(&group)
+
+	monitoring := models.Tag{OrganizationID: org.ID, Name: "infra/monitoring"}
+	db.Create(&monitoring)
+	otherTag := models.Tag{OrganizationID: other.ID, Name: "infra"}
+	db.Create(&otherTag)
+
+	past := time.Now().Add(-time.Hour)
+	for _, link := range []models.Link{
+		{Slug: "grafana", URL: "https://grafana.example.com", Title: "Grafana", IsPublic: true},
+		{Slug: "secret", URL: "https://secret.example.com", Title: "Secret"},
+		{Slug: "old", URL: "https://old.example.com", Title: "Old", IsPublic: true, ExpiresAt: &past},
+		{Slug: "handbook", URL: "https://handbook.example.com", Title: "Handbook", IsPublic: true},
+	} {
+		link.OrganizationID = org.ID
+		link.GroupID = group.ID
+		link.CreatedByID = 1
+		db.Create(&link)
+		if link.Slug != "handbook" {
+			db.Model(&link).Association("Tags").Append(&monitoring)
+		}
+	}
+
+	return group
+}
+
+func setupTestRouter(db *gorm.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(db).RegisterPublicRoutes(r)
+	redirect.NewHandler(db).RegisterRoutes(r)
+	return r
+}
+
+func get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	req.Host = "links.example.com"
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestTagPage(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	group := setupTestData(t, db)
+
+	// Parent tags list links with tags below them
+	for _, path := range []string{"/t/infra?format=json", "/t/infra/monitoring?format=json"} {
+		resp := get(router, path)
+		if resp.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 from %s, got %d: %s", path, resp.Code, resp.Body.String())
+		}
+		var page Page
+		json.Unmarshal(resp.Body.Bytes(), &page)
+		if len(page.Links) != 1 || page.Links[0].Slug != "grafana" || page.Links[0].ShortURL != "http://links.example.com/grafana" {
+			t.Errorf("Expected only the public, unexpired grafana link from %s, got %+v", path, page.Links)
+		}
+	}
+
+	resp := get(router, "/t/infra")
+	if !strings.HasPrefix(resp.Header().Get("Content-Type"), "text/html") || !strings.Contains(resp.Body.String(), `<a href="http://links.example.com/grafana">Grafana</a>`) {
+		t.Errorf("Expected an HTML page, got %s", resp.Body.String())
+	}
+	if strings.Contains(resp.Body.String(), "Secret") {
+		t.Error("Expected private links to be left out")
+	}
+
+	if resp := get(router, "/t/missing"); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown tag, got %d", resp.Code)
+	}
+
+	// A tag used only on private links looks like an unknown one
+	private := models.Tag{OrganizationID: group.OrganizationID, Name: "internal"}
+	db.Create(&private)
+	var secret models.Link
+	db.Where("slug = ?", "secret").First(&secret)
+	db.Model(&secret).Association("Tags").Append(&private)
+	if resp := get(router, "/t/internal"); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a tag with no public links, got %d", resp.Code)
+	}
+
+	// Redirects still work alongside the landing pages
+	if resp := get(router, "/grafana"); resp.Code != http.StatusFound {
+		t.Errorf("Expected the redirect to still work, got %d", resp.Code)
+	}
+}
+
+func TestGroupPage(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	group := setupTestData(t, db)
+
+	// Off until a group admin turns it on
+	if resp := get(router, "/g/sre"); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 while the page is off, got %d", resp.Code)
+	}
+	db.Model(&group).Update("is_public_page", true)
+
+	resp := get(router, "/g/sre")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.Code)
+	}
+	body := resp.Body.String()
+	if !strings.Contains(body, "<h1>SRE &lt;team&gt;</h1>") || !strings.Contains(body, "Handbook") || strings.Contains(body, "Secret") || strings.Contains(body, ">Old<") {
+		t.Errorf("Unexpected page:\n%s", body)
+	}
+
+	resp = get(router, "/g/sre?format=rss")
+	if resp.Header().Get("Content-Type") != "application/rss+xml; charset=utf-8" {
+		t.Errorf("Unexpected content type %q", resp.Header().Get("Content-Type"))
+	}
+	var rss rssFeed
+	if err := xml.Unmarshal(resp.Body.Bytes(), &rss); err != nil {
+		t.Fatalf("Invalid RSS: %v", err)
+	}
+	if rss.Channel.Title != "SRE <team>" || len(rss.Channel.Items) != 2 || rss.Channel.Items[0].Link != "http://links.example.com/handbook" {
+		t.Errorf("Unexpected RSS feed %+v", rss)
+	}
+
+	resp = get(router, "/g/sre?format=atom")
+	var atom atomFeed
+	if err := xml.Unmarshal(resp.Body.Bytes(), &atom); err != nil {
+		t.Fatalf("Invalid Atom: %v", err)
+	}
+	if atom.ID != "http://links.example.com/g/sre" || len(atom.Entries) != 2 || atom.Entries[1].Link.Href != "http://links.example.com/grafana" {
+		t.Errorf("Unexpected Atom feed %+v", atom)
+	}
+
+	// Pages are scoped to the organization of the host
+	req, _ := http.NewRequest("GET", "/g/sre", nil)
+	req.Host = "elsewhere.example.com"
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 on another organization's host, got %d", resp.Code)
+	}
+}
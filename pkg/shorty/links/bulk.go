@@ -0,0 +1,390 @@
+package links
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/tags"
+	"gorm.io/gorm"
+)
+
+// Bulk actions supported by the bulk endpoint
+const (
+	BulkActionDelete     = "delete"
+	BulkActionMove       = "move"
+	BulkActionAddTags    = "add_tags"
+	BulkActionRemoveTags = "remove_tags"
+	BulkActionSetPublic  = "set_public"
+	BulkActionSetUnread  = "set_unread"
+	BulkActionSetExpiry  = "set_expiry"
+)
+
+// maxBulkItems caps how many links a single bulk request may touch
+const maxBulkItems = 500
+
+// Bulk item statuses
+const (
+	BulkStatusOK      = "ok"
+	BulkStatusDryRun  = "dry_run"
+	BulkStatusError   = "error"
+	BulkStatusSkipped = "skipped"
+)
+
+// BulkRequest represents a bulk operation over a set of links.
+// Links are selected by slug, by ID, or by a search query (one or more may be combined).
+type BulkRequest struct {
+	Slugs      []string      `json:"slugs"`
+	IDs        []uint        `json:"ids"`
+	Query      *SearchParams `json:"query"`
+	Action     string        `json:"action" binding:"required,oneof=delete move add_tags remove_tags set_public set_unread set_expiry"`
+	GroupID    uint          `json:"group_id"`                                               // Target group for move
+	OnConflict string        `json:"on_conflict" binding:"omitempty,oneof=skip rename fail"` // Slug conflict strategy for move (default fail)
+	Tags       []string      `json:"tags"`                                                   // Tags for add_tags/remove_tags
+	Value      *bool         `json:"value"`                                                  // Value for set_public/set_unread
+	ExpiresAt  *time.Time    `json:"expires_at"`                                             // Expiry for set_expiry (null clears it)
+	DryRun     bool          `json:"dry_run"`
+	Atomic     bool          `json:"atomic"` // Roll back every item if any item fails
+}
+
+// BulkItemResult reports the outcome of a bulk operation for a single link
+type BulkItemResult struct {
+	ID     uint   `json:"id,omitempty"`
+	Slug   string `json:"slug"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkResponse represents the result of a bulk operation
+type BulkResponse struct {
+	Action    string           `json:"action"`
+	DryRun    bool             `json:"dry_run"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// errBulkAborted is returned from the outer transaction to roll back an atomic bulk operation
+var errBulkAborted = errors.New("bulk operation aborted")
+
+// validateBulkRequest checks that the action has the parameters it needs
+func validateBulkRequest(req *BulkRequest) error {
+	if len(req.Slugs) == 0 && len(req.IDs) == 0 && req.Query == nil {
+		return &ValidationError{"One of slugs, ids or query is required"}
+	}
+	if req.Query != nil {
+		if err := req.Query.Validate(); err != nil {
+			return err
+		}
+	}
+
+	switch req.Action {
+	case BulkActionMove:
+		if req.GroupID == 0 {
+			return &ValidationError{"group_id is required for move"}
+		}
+	case BulkActionAddTags, BulkActionRemoveTags:
+		if len(req.Tags) == 0 {
+			return &ValidationError{"tags are required for " + req.Action}
+		}
+	case BulkActionSetPublic, BulkActionSetUnread:
+		if req.Value == nil {
+			return &ValidationError{"value is required for " + req.Action}
+		}
+	}
+
+	return nil
+}
+
+// resolveBulkLinks finds the links selected by a bulk request.
+// Slugs and IDs that don't resolve to a link in one of the user's groups are
+// reported as errors, so the caller gets a result for every requested item.
+func (h *Handler) resolveBulkLinks(groupIDs []uint, req *BulkRequest) ([]models.Link, []BulkItemResult, error) {
+	var links []models.Link
+	var missing []BulkItemResult
+	seen := make(map[uint]bool)
+
+	add := func(found []models.Link) {
+		for _, link := range found {
+			if !seen[link.ID] {
+				seen[link.ID] = true
+				links = append(links, link)
+			}
+		}
+	}
+
+	if len(req.Slugs) > 0 {
+		var found []models.Link
+		if err := h.db.Where("slug IN ? AND group_id IN ?", req.Slugs, groupIDs).Find(&found).Error; err != nil {
+			return nil, nil, err
+		}
+		bySlug := make(map[string]bool, len(found))
+		for _, link := range found {
+			bySlug[link.Slug] = true
+		}
+		for _, slug := range req.Slugs {
+			if !bySlug[slug] {
+				missing = append(missing, BulkItemResult{Slug: slug, Status: BulkStatusError, Error: "Link not found"})
+			}
+		}
+		add(found)
+	}
+
+	if len(req.IDs) > 0 {
+		var found []models.Link
+		if err := h.db.Where("id IN ? AND group_id IN ?", req.IDs, groupIDs).Find(&found).Error; err != nil {
+			return nil, nil, err
+		}
+		byID := make(map[uint]bool, len(found))
+		for _, link := range found {
+			byID[link.ID] = true
+		}
+		for _, id := range req.IDs {
+			if !byID[id] {
+				missing = append(missing, BulkItemResult{ID: id, Status: BulkStatusError, Error: "Link not found"})
+			}
+		}
+		add(found)
+	}
+
+	if req.Query != nil {
+		var found []models.Link
+		if err := h.searchQuery(groupIDs, *req.Query).Order("links.id").Limit(maxBulkItems + 1).Find(&found).Error; err != nil {
+			return nil, nil, err
+		}
+		add(found)
+	}
+
+	return links, missing, nil
+}
+
+// applyBulkAction performs the requested action on a single link within a transaction
+func (h *Handler) applyBulkAction(tx *gorm.DB, link *models.Link, req *BulkRequest, targetGroup *models.Group, userID uint) error {
+	switch req.Action {
+	case BulkActionDelete:
+		return tx.Delete(link).Error
+
+	case BulkActionMove:
+		onConflict := req.OnConflict
+		if onConflict == "" {
+			onConflict = ConflictFail
+		}
+		result, err := h.withDB(tx).transferLink(link, targetGroup, TransferModeMove, onConflict, userID)
+		if err != nil {
+			return err
+		}
+		if result.Status == TransferStatusSkipped {
+			return errTransferSkipped
+		}
+		return nil
+
+	case BulkActionAddTags:
+		found, err := tags.FindOrCreate(tx, link.OrganizationID, req.Tags)
+		if err != nil {
+			return err
+		}
+		return tx.Model(link).Association("Tags").Append(found)
+
+	case BulkActionRemoveTags:
+		found, err := tags.Find(tx, link.OrganizationID, req.Tags)
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			return nil
+		}
+		return tx.Model(link).Association("Tags").Delete(found)
+
+	case BulkActionSetPublic:
+		return tx.Model(link).Update("is_public", *req.Value).Error
+
+	case BulkActionSetUnread:
+		return tx.Model(link).Update("is_unread", *req.Value).Error
+
+	case BulkActionSetExpiry:
+		return tx.Model(link).Update("expires_at", req.ExpiresAt).Error
+	}
+
+	return &ValidationError{"Unknown action"}
+}
+
+// Bulk applies an action to many links at once
+// @Summary Bulk link operations
+// @Description Apply an action (delete, move, add_tags, remove_tags, set_public, set_unread, set_expiry) to a set of links selected by slug, ID or search query. Each link is updated in its own transaction unless atomic is set. Moves stay within the links' organization. Use dry_run to preview the affected links.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param request body BulkRequest true "Bulk operation"
+// @Success 200 {object} BulkResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 404 {object} map[string]string "Group not found"
+// @Failure 409 {object} BulkResponse "Atomic operation rolled back"
+// @Security BearerAuth
+// @Router /links/bulk [post]
+func (h *Handler) Bulk(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	var req BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateBulkRequest(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Moving requires a role in the target group that can add links. Moves to
+	// another organization go through /links/transfer, which needs admin
+	// access to the target group.
+	var targetGroup models.Group
+	if req.Action == BulkActionMove {
+		switch access.Authorize(h.db, userID, req.GroupID, access.ActionCreate) {
+		case nil:
+		case access.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your role in the target group doesn't allow adding links"})
+			return
+		default:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+			return
+		}
+		if err := h.db.First(&targetGroup, req.GroupID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+			return
+		}
+	}
+
+	groupIDs, err := h.getUserGroupIDs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
+		return
+	}
+
+	response := BulkResponse{
+		Action:  req.Action,
+		DryRun:  req.DryRun,
+		Results: []BulkItemResult{},
+	}
+
+	if len(groupIDs) == 0 {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	// Only links in the user's groups can be selected
+	links, missing, err := h.resolveBulkLinks(groupIDs, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch links"})
+		return
+	}
+	if len(links) > maxBulkItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many links selected (max 500)"})
+		return
+	}
+
+	response.Results = append(response.Results, missing...)
+	response.Failed = len(missing)
+
+	if req.Action == BulkActionMove {
+		for i := range links {
+			if links[i].OrganizationID != targetGroup.OrganizationID {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Bulk moves stay within an organization; use /links/transfer to move links to another organization"})
+				return
+			}
+		}
+	}
+
+	// Protected links only change with approval, which bulk changes can't
+	// wait for, so they're left for their approvers
+	protected := make(map[uint]bool)
+	for i := range links {
+		var rule *models.ProtectedSlug
+		if req.Action == BulkActionMove {
+			rule, err = h.transferProtection(userID, &links[i], &targetGroup, TransferModeMove)
+		} else {
+			rule, err = h.protectionFor(userID, links[i].OrganizationID, links[i].Slug)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check protected slugs"})
+			return
+		}
+		protected[links[i].ID] = rule != nil
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		for i := range links {
+			link := &links[i]
+			result := BulkItemResult{ID: link.ID, Slug: link.Slug, Status: BulkStatusOK}
+
+			itemErr := tx.Transaction(func(itemTx *gorm.DB) error {
+				// Viewers and contributors may be able to select links they can't change
+				if err := access.Authorize(itemTx, userID, link.GroupID, access.EditAction(userID, link)); err != nil {
+					return &ValidationError{"Your role in this group doesn't allow changing this link"}
+				}
+				if protected[link.ID] {
+					return &ValidationError{"This link is protected, so only its approvers can change it in bulk"}
+				}
+				if err := h.applyBulkAction(itemTx, link, &req, &targetGroup, userID); err != nil {
+					return err
+				}
+				if req.DryRun {
+					return errBulkAborted
+				}
+				return nil
+			})
+			switch {
+			case itemErr == errBulkAborted:
+				result.Status = BulkStatusDryRun
+			case itemErr == errTransferSkipped:
+				result.Status = BulkStatusSkipped
+				result.Error = "Slug already exists in the target organization"
+			case itemErr != nil:
+				result.Status = BulkStatusError
+				var validationErr *ValidationError
+				var conflictErr *SlugConflictError
+				if errors.As(itemErr, &validationErr) {
+					result.Error = validationErr.Message
+				} else if errors.As(itemErr, &conflictErr) {
+					result.Error = conflictErr.Error()
+				} else {
+					result.Error = "Failed to apply " + req.Action
+				}
+			}
+
+			if result.Status == BulkStatusError {
+				response.Failed++
+			} else {
+				response.Succeeded++
+			}
+			response.Results = append(response.Results, result)
+		}
+
+		if req.Atomic && response.Failed > 0 {
+			return errBulkAborted
+		}
+		return nil
+	})
+
+	if err == errBulkAborted {
+		// Nothing was applied; report the other items as skipped
+		for i := range response.Results {
+			if response.Results[i].Status != BulkStatusError {
+				response.Results[i].Status = BulkStatusSkipped
+			}
+		}
+		response.Succeeded = 0
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply bulk operation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,491 @@
+package links
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/slugs"
+	"gorm.io/gorm"
+)
+
+// errAlreadyReviewed is returned when another approver reviewed a change
+// request first
+var errAlreadyReviewed = errors.New("change request has already been reviewed")
+
+// ReviewChangeRequest represents an approver's decision on a change request
+type ReviewChangeRequest struct {
+	Comment string `json:"comment" binding:"max=10000"`
+}
+
+// ChangeRequestResponse represents a change request in API responses
+type ChangeRequestResponse struct {
+	ID              uint            `json:"id"`
+	OrganizationID  uint            `json:"organization_id"`
+	GroupID         uint            `json:"group_id"`
+	LinkID          *uint           `json:"link_id,omitempty"` // The link being updated, or the created link once approved
+	ProtectedSlugID uint            `json:"protected_slug_id"`
+	Action          string          `json:"action"`
+	Slug            string          `json:"slug"`
+	Payload         json.RawMessage `json:"payload" swaggertype:"object"` // The requested create or update
+	Status          string          `json:"status"`
+	RequestedByID   uint            `json:"requested_by_id"`
+	ReviewedByID    *uint           `json:"reviewed_by_id,omitempty"`
+	ReviewedAt      string          `json:"reviewed_at,omitempty"`
+	ReviewComment   string          `json:"review_comment,omitempty"`
+	CreatedAt       string          `json:"created_at"`
+
+	// Link is the created or updated link. Only set when approving.
+	Link *LinkResponse `json:"link,omitempty"`
+}
+
+func changeRequestToResponse(cr models.LinkChangeRequest) ChangeRequestResponse {
+	response := ChangeRequestResponse{
+		ID:              cr.ID,
+		OrganizationID:  cr.OrganizationID,
+		GroupID:         cr.GroupID,
+		LinkID:          cr.LinkID,
+		ProtectedSlugID: cr.ProtectedSlugID,
+		Action:          cr.Action,
+		Slug:            cr.Slug,
+		Payload:         json.RawMessage(cr.Payload),
+		Status:          string(cr.Status),
+		RequestedByID:   cr.RequestedByID,
+		ReviewedByID:    cr.ReviewedByID,
+		ReviewComment:   cr.ReviewComment,
+		CreatedAt:       cr.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if cr.ReviewedAt != nil {
+		response.ReviewedAt = cr.ReviewedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return response
+}
+
+// isApprover reports whether the user may approve changes covered by a rule:
+// one of its designated approvers, or an organization admin if it has none
+func isApprover(db *gorm.DB, userID uint, rule *models.ProtectedSlug) bool {
+	var approvers int64
+	db.Table("protected_slug_approvers").Where("protected_slug_id = ?", rule.ID).Count(&approvers)
+	if approvers > 0 {
+		var mine int64
+		db.Table("protected_slug_approvers").Where("protected_slug_id = ? AND user_id = ?", rule.ID, userID).Count(&mine)
+		return mine > 0
+	}
+
+	var membership models.OrganizationMembership
+	return db.Where("user_id = ? AND organization_id = ? AND role = ?", userID, rule.OrganizationID, models.OrgRoleAdmin).First(&membership).Error == nil
+}
+
+// approvableRuleIDs returns the IDs of the protected slug rules the user may approve changes for
+func (h *Handler) approvableRuleIDs(userID uint) ([]uint, error) {
+	var ruleIDs []uint
+	if err := h.db.Table("protected_slug_approvers").Where("user_id = ?", userID).Pluck("protected_slug_id", &ruleIDs).Error; err != nil {
+		return nil, err
+	}
+
+	// Rules without designated approvers are approved by org admins
+	var adminOrgIDs []uint
+	if err := h.db.Model(&models.OrganizationMembership{}).Where("user_id = ? AND role = ?", userID, models.OrgRoleAdmin).Pluck("organization_id", &adminOrgIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(adminOrgIDs) > 0 {
+		var unassigned []uint
+		if err := h.db.Unscoped().Model(&models.ProtectedSlug{}).
+			Where("organization_id IN ?", adminOrgIDs).
+			Where("NOT EXISTS (SELECT 1 FROM protected_slug_approvers WHERE protected_slug_approvers.protected_slug_id = protected_slugs.id)").
+			Pluck("id", &unassigned).Error; err != nil {
+			return nil, err
+		}
+		ruleIDs = append(ruleIDs, unassigned...)
+	}
+
+	return ruleIDs, nil
+}
+
+// protectionFor returns the protected slug rule that stops the user changing
+// any of the given slugs directly, or nil if the change can be applied.
+// Approvers' own changes are applied directly.
+func (h *Handler) protectionFor(userID, orgID uint, candidates ...string) (*models.ProtectedSlug, error) {
+	return Protection(h.db, userID, orgID, candidates...)
+}
+
+// Protection returns the protected slug rule in an organization that stops
+// the user changing any of the given slugs directly, or nil if none does.
+// Other packages that create links use it to keep clear of protected slugs.
+func Protection(db *gorm.DB, userID, orgID uint, candidates ...string) (*models.ProtectedSlug, error) {
+	var rules []models.ProtectedSlug
+	if err := db.Where("organization_id = ?", orgID).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	for _, slug := range candidates {
+		if slug == "" {
+			continue
+		}
+		for i := range rules {
+			if rules[i].Matches(slug) && !isApprover(db, userID, &rules[i]) {
+				return &rules[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// transferProtection returns the protected slug rule that stops the user
+// moving or copying a link directly: a rule covering its slug in its own
+// organization when it moves, or in the organization it arrives in.
+// Transfers aren't queued for approval, so protected links are only
+// transferred by their approvers.
+func (h *Handler) transferProtection(userID uint, link *models.Link, target *models.Group, mode string) (*models.ProtectedSlug, error) {
+	if mode == TransferModeMove {
+		if rule, err := h.protectionFor(userID, link.OrganizationID, link.Slug); rule != nil || err != nil {
+			return rule, err
+		}
+	}
+	_, name := slugs.SplitNamespace(link.Slug)
+	return h.protectionFor(userID, target.OrganizationID, link.Slug, name)
+}
+
+// requestChange records a pending change to a protected link and writes the response
+func (h *Handler) requestChange(c *gin.Context, userID uint, rule *models.ProtectedSlug, action string, groupID uint, linkID *uint, slug string, payload interface{}) {
+	encoded, _ := json.Marshal(payload)
+	cr := models.LinkChangeRequest{
+		OrganizationID:  rule.OrganizationID,
+		GroupID:         groupID,
+		LinkID:          linkID,
+		ProtectedSlugID: rule.ID,
+		Action:          action,
+		Slug:            slug,
+		Payload:         string(encoded),
+		Status:          models.ChangeRequestPending,
+		RequestedByID:   userID,
+	}
+
+	if err := h.db.Create(&cr).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create change request"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, changeRequestToResponse(cr))
+}
+
+// findChangeRequest looks up a change request the user requested or can review
+func (h *Handler) findChangeRequest(c *gin.Context, userID uint) (*models.LinkChangeRequest, *models.ProtectedSlug, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid change request ID"})
+		return nil, nil, false
+	}
+
+	var cr models.LinkChangeRequest
+	if err := h.db.First(&cr, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Change request not found"})
+		return nil, nil, false
+	}
+
+	// The rule may have been removed since the request was made
+	var rule models.ProtectedSlug
+	if err := h.db.Unscoped().First(&rule, cr.ProtectedSlugID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Change request not found"})
+		return nil, nil, false
+	}
+
+	if cr.RequestedByID != userID && !isApprover(h.db, userID, &rule) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Change request not found"})
+		return nil, nil, false
+	}
+
+	return &cr, &rule, true
+}
+
+// findReviewableChangeRequest looks up a pending change request the user can approve or reject
+func (h *Handler) findReviewableChangeRequest(c *gin.Context, userID uint) (*models.LinkChangeRequest, *ReviewChangeRequest, bool) {
+	cr, rule, ok := h.findChangeRequest(c, userID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if !isApprover(h.db, userID, rule) || cr.RequestedByID == userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not an approver for this change request"})
+		return nil, nil, false
+	}
+
+	if cr.Status != models.ChangeRequestPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Change request has already been reviewed"})
+		return nil, nil, false
+	}
+
+	var req ReviewChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+
+	return cr, &req, true
+}
+
+// recordReview marks a pending change request as approved or rejected. It
+// returns errAlreadyReviewed if the request is no longer pending, so only one
+// review of a request takes effect.
+func recordReview(db *gorm.DB, cr *models.LinkChangeRequest, userID uint, status models.ChangeRequestStatus, comment string) error {
+	now := time.Now()
+	result := db.Model(&models.LinkChangeRequest{}).
+		Where("id = ? AND status = ?", cr.ID, models.ChangeRequestPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"reviewed_by_id": userID,
+			"reviewed_at":    now,
+			"review_comment": comment,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errAlreadyReviewed
+	}
+
+	cr.Status = status
+	cr.ReviewedByID = &userID
+	cr.ReviewedAt = &now
+	cr.ReviewComment = comment
+	return nil
+}
+
+// applyChangeRequest makes an approved change as the requester. Their role in
+// the group, the slug and the group's rules are checked again because they
+// may have changed since the request was made; these checks fail with a
+// ValidationError.
+func (h *Handler) applyChangeRequest(cr *models.LinkChangeRequest) (models.Link, error) {
+	var link models.Link
+	switch cr.Action {
+	case models.ChangeActionCreate:
+		var req CreateLinkRequest
+		if err := json.Unmarshal([]byte(cr.Payload), &req); err != nil {
+			return link, fmt.Errorf("reading change request %d: %w", cr.ID, err)
+		}
+
+		var group models.Group
+		if err := h.db.First(&group, cr.GroupID).Error; err != nil {
+			return link, &ValidationError{"The link's group no longer exists"}
+		}
+		if err := access.Authorize(h.db, cr.RequestedByID, group.ID, access.ActionCreate); err != nil {
+			return link, &ValidationError{"The requester can no longer add links to this group"}
+		}
+		if err := applyGroupPolicy(&group, &req); err != nil {
+			return link, err
+		}
+		if err := h.validateSlugForOrg(req.Slug, 0, group.OrganizationID, group.ID, cr.RequestedByID); err != nil {
+			return link, err
+		}
+
+		var err error
+		if link, err = h.createLink(cr.RequestedByID, &group, req); err != nil {
+			return link, err
+		}
+		cr.LinkID = &link.ID
+		return link, h.db.Model(cr).Update("link_id", link.ID).Error
+
+	case models.ChangeActionUpdate:
+		var req UpdateLinkRequest
+		if err := json.Unmarshal([]byte(cr.Payload), &req); err != nil {
+			return link, fmt.Errorf("reading change request %d: %w", cr.ID, err)
+		}
+
+		if cr.LinkID == nil || h.db.First(&link, *cr.LinkID).Error != nil {
+			return link, &ValidationError{"The link no longer exists"}
+		}
+		if err := access.Authorize(h.db, cr.RequestedByID, link.GroupID, access.EditAction(cr.RequestedByID, &link)); err != nil {
+			return link, &ValidationError{"The requester can no longer change this link"}
+		}
+		if req.Slug != "" && req.Slug != link.Slug {
+			if err := h.validateSlugForOrg(req.Slug, link.ID, link.OrganizationID, link.GroupID, cr.RequestedByID); err != nil {
+				return link, err
+			}
+		}
+		if err := h.checkUpdatePolicy(&link, req); err != nil {
+			return link, err
+		}
+		return link, h.updateLink(&link, req, cr.RequestedByID)
+
+	case models.ChangeActionDelete:
+		if cr.LinkID == nil || h.db.First(&link, *cr.LinkID).Error != nil {
+			return link, &ValidationError{"The link no longer exists"}
+		}
+		if err := access.Authorize(h.db, cr.RequestedByID, link.GroupID, access.EditAction(cr.RequestedByID, &link)); err != nil {
+			return link, &ValidationError{"The requester can no longer delete this link"}
+		}
+		return link, h.db.Delete(&link).Error
+	}
+
+	return link, fmt.Errorf("unknown change request action %q", cr.Action)
+}
+
+// ListChangeRequests returns change requests the user made or can review
+// @Summary List change requests
+// @Description Get change requests to protected links that the current user requested or can approve
+// @Tags change-requests
+// @Produce json
+// @Param status query string false "Filter by status: pending (default), approved, rejected or all"
+// @Success 200 {array} ChangeRequestResponse
+// @Security BearerAuth
+// @Router /change-requests [get]
+func (h *Handler) ListChangeRequests(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	ruleIDs, err := h.approvableRuleIDs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch change requests"})
+		return
+	}
+
+	query := h.db.Order("created_at DESC, id DESC")
+	if len(ruleIDs) > 0 {
+		query = query.Where("requested_by_id = ? OR protected_slug_id IN ?", userID, ruleIDs)
+	} else {
+		query = query.Where("requested_by_id = ?", userID)
+	}
+	switch status := c.DefaultQuery("status", string(models.ChangeRequestPending)); status {
+	case "all":
+	case string(models.ChangeRequestPending), string(models.ChangeRequestApproved), string(models.ChangeRequestRejected):
+		query = query.Where("status = ?", status)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	var requests []models.LinkChangeRequest
+	if err := query.Find(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch change requests"})
+		return
+	}
+
+	responses := make([]ChangeRequestResponse, len(requests))
+	for i, cr := range requests {
+		responses[i] = changeRequestToResponse(cr)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetChangeRequest returns a change request
+// @Summary Get a change request
+// @Description Get a change request the current user requested or can approve
+// @Tags change-requests
+// @Produce json
+// @Param id path int true "Change request ID"
+// @Success 200 {object} ChangeRequestResponse
+// @Failure 404 {object} map[string]string "Change request not found"
+// @Security BearerAuth
+// @Router /change-requests/{id} [get]
+func (h *Handler) GetChangeRequest(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	cr, _, ok := h.findChangeRequest(c, userID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, changeRequestToResponse(*cr))
+}
+
+// ApproveChangeRequest approves a change request and applies the change
+// @Summary Approve a change request
+// @Description Approve a pending change to a protected link and apply it on behalf of the requester, whose role in the group must still allow the change. Requesters can't approve their own changes.
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Change request ID"
+// @Param request body ReviewChangeRequest false "Review comment"
+// @Success 200 {object} ChangeRequestResponse
+// @Failure 400 {object} map[string]string "The change is no longer valid"
+// @Failure 403 {object} map[string]string "Not an approver"
+// @Failure 404 {object} map[string]string "Change request not found"
+// @Failure 409 {object} map[string]string "Already reviewed"
+// @Security BearerAuth
+// @Router /change-requests/{id}/approve [post]
+func (h *Handler) ApproveChangeRequest(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	cr, review, ok := h.findReviewableChangeRequest(c, userID)
+	if !ok {
+		return
+	}
+
+	// The approval is recorded in the same transaction as the change, and
+	// only while the request is pending, so racing approvers can't apply a
+	// change twice
+	var link models.Link
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := recordReview(tx, cr, userID, models.ChangeRequestApproved, review.Comment); err != nil {
+			return err
+		}
+		var err error
+		link, err = h.withDB(tx).applyChangeRequest(cr)
+		return err
+	})
+
+	var validationErr *ValidationError
+	switch {
+	case err == nil:
+	case errors.Is(err, errAlreadyReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": "Change request has already been reviewed"})
+		return
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply the change"})
+		return
+	}
+
+	if cr.Action == models.ChangeActionCreate {
+		h.fetchNewLinkMetadata(&link)
+	}
+
+	response := changeRequestToResponse(*cr)
+	linkResponse := linkToResponse(link)
+	response.Link = &linkResponse
+	c.JSON(http.StatusOK, response)
+}
+
+// RejectChangeRequest rejects a change request
+// @Summary Reject a change request
+// @Description Reject a pending change to a protected link. The change is not applied.
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Change request ID"
+// @Param request body ReviewChangeRequest false "Review comment"
+// @Success 200 {object} ChangeRequestResponse
+// @Failure 403 {object} map[string]string "Not an approver"
+// @Failure 404 {object} map[string]string "Change request not found"
+// @Failure 409 {object} map[string]string "Already reviewed"
+// @Security BearerAuth
+// @Router /change-requests/{id}/reject [post]
+func (h *Handler) RejectChangeRequest(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	cr, review, ok := h.findReviewableChangeRequest(c, userID)
+	if !ok {
+		return
+	}
+
+	err := recordReview(h.db, cr, userID, models.ChangeRequestRejected, review.Comment)
+	if errors.Is(err, errAlreadyReviewed) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Change request has already been reviewed"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record rejection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, changeRequestToResponse(*cr))
+}
@@ -0,0 +1,282 @@
+package links
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/urlcanon"
+	"gorm.io/gorm"
+)
+
+// DuplicateLink identifies an existing link with the same canonical destination
+type DuplicateLink struct {
+	ID      uint   `json:"id"`
+	GroupID uint   `json:"group_id"`
+	Slug    string `json:"slug"`
+	URL     string `json:"url"`
+}
+
+// DuplicateCluster is a set of links in an organization sharing a canonical destination
+type DuplicateCluster struct {
+	OrganizationID uint           `json:"organization_id"`
+	CanonicalURL   string         `json:"canonical_url"`
+	Links          []LinkResponse `json:"links"`
+}
+
+// MergeDuplicatesRequest represents a request to turn links into aliases of a target link
+type MergeDuplicatesRequest struct {
+	TargetID uint   `json:"target_id" binding:"required"`
+	LinkIDs  []uint `json:"link_ids" binding:"required,min=1"`
+}
+
+// MergeDuplicatesResponse represents the result of merging duplicate links
+type MergeDuplicatesResponse struct {
+	Target LinkResponse   `json:"target"`
+	Merged []LinkResponse `json:"merged"`
+}
+
+// canonicalHash hashes a URL using the organization's tracking parameter settings
+func (h *Handler) canonicalHash(orgID uint, rawURL string) string {
+	return urlcanon.HashForOrg(h.db, orgID, rawURL)
+}
+
+// findDuplicates returns links in an organization with the given canonical hash.
+// Aliases are left out since they already point at another link.
+func (h *Handler) findDuplicates(orgID uint, hash string, excludeID uint) ([]DuplicateLink, error) {
+	if hash == "" {
+		return nil, nil
+	}
+
+	var links []models.Link
+	if err := h.db.Where("organization_id = ? AND canonical_hash = ? AND id != ? AND alias_of_id IS NULL", orgID, hash, excludeID).
+		Order("id").Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	duplicates := make([]DuplicateLink, len(links))
+	for i, link := range links {
+		duplicates[i] = DuplicateLink{
+			ID:      link.ID,
+			GroupID: link.GroupID,
+			Slug:    link.Slug,
+			URL:     link.URL,
+		}
+	}
+	return duplicates, nil
+}
+
+// ListDuplicates reports clusters of links that share a canonical destination
+// @Summary List duplicate links
+// @Description List clusters of links with the same canonical URL (ignoring host case, default ports, query parameter order and tracking parameters). Only links in the caller's groups are considered.
+// @Tags links
+// @Produce json
+// @Param organization_id query int false "Only report duplicates in this organization"
+// @Success 200 {array} DuplicateCluster
+// @Security BearerAuth
+// @Router /duplicates [get]
+func (h *Handler) ListDuplicates(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	groupIDs, err := h.getUserGroupIDs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
+		return
+	}
+
+	if len(groupIDs) == 0 {
+		c.JSON(http.StatusOK, []DuplicateCluster{})
+		return
+	}
+
+	// Links in the caller's groups that aren't aliases
+	scope := func() *gorm.DB {
+		query := h.db.Model(&models.Link{}).
+			Where("group_id IN ? AND alias_of_id IS NULL AND canonical_hash != ''", groupIDs)
+		if orgID, err := strconv.ParseUint(c.Query("organization_id"), 10, 32); err == nil {
+			query = query.Where("organization_id = ?", orgID)
+		}
+		return query
+	}
+
+	var hashes []string
+	if err := scope().Group("organization_id, canonical_hash").Having("COUNT(*) > 1").
+		Pluck("canonical_hash", &hashes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch duplicates"})
+		return
+	}
+
+	clusters := []DuplicateCluster{}
+	if len(hashes) == 0 {
+		c.JSON(http.StatusOK, clusters)
+		return
+	}
+
+	var links []models.Link
+	if err := scope().Where("canonical_hash IN ?", hashes).
+		Order("organization_id, canonical_hash, id").Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch duplicates"})
+		return
+	}
+
+	// Links are ordered by cluster, so each cluster is a contiguous run
+	trackingParams := make(map[uint][]string)
+	for i := 0; i < len(links); {
+		j := i + 1
+		for j < len(links) && links[j].OrganizationID == links[i].OrganizationID && links[j].CanonicalHash == links[i].CanonicalHash {
+			j++
+		}
+
+		orgID := links[i].OrganizationID
+		if _, ok := trackingParams[orgID]; !ok {
+			var org models.Organization
+			h.db.First(&org, orgID)
+			trackingParams[orgID] = org.TrackingParamList()
+		}
+		canonical, _ := urlcanon.Canonicalize(links[i].URL, trackingParams[orgID])
+
+		cluster := DuplicateCluster{OrganizationID: orgID, CanonicalURL: canonical}
+		for _, link := range links[i:j] {
+			cluster.Links = append(cluster.Links, linkToResponse(link))
+		}
+		if len(cluster.Links) > 1 {
+			clusters = append(clusters, cluster)
+		}
+		i = j
+	}
+
+	c.JSON(http.StatusOK, clusters)
+}
+
+// MergeDuplicates turns links into aliases of a target link
+// @Summary Merge duplicate links
+// @Description Turn links into aliases of a target link in the same organization with the same destination. Pages can't be merged. Aliases keep their slugs but redirect to the target's URL, and clicks are credited to the target. Links already aliased to a merged link are re-pointed at the target.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param request body MergeDuplicatesRequest true "Links to merge"
+// @Success 200 {object} MergeDuplicatesResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Role doesn't allow editing a merged link, or it is protected"
+// @Failure 404 {object} map[string]string "Link not found"
+// @Security BearerAuth
+// @Router /duplicates/merge [post]
+func (h *Handler) MergeDuplicates(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	var req MergeDuplicatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	groupIDs, err := h.getUserGroupIDs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
+		return
+	}
+
+	var target models.Link
+	if err := h.db.Where("id = ? AND group_id IN ?", req.TargetID, groupIDs).First(&target).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+	if target.AliasOfID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Target link is itself an alias"})
+		return
+	}
+	if target.IsPage() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pages cannot be merged"})
+		return
+	}
+
+	var links []models.Link
+	if err := h.db.Where("id IN ? AND group_id IN ?", req.LinkIDs, groupIDs).Order("id").Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch links"})
+		return
+	}
+
+	found := make(map[uint]bool, len(links))
+	for _, link := range links {
+		found[link.ID] = true
+	}
+	for _, id := range req.LinkIDs {
+		if !found[id] {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Link not found", "id": id})
+			return
+		}
+	}
+
+	mergedIDs := make([]uint, len(links))
+	for i, link := range links {
+		if link.ID == target.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A link cannot be merged into itself"})
+			return
+		}
+		if link.OrganizationID != target.OrganizationID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Links must be in the same organization as the target"})
+			return
+		}
+		if link.IsPage() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Pages cannot be merged", "id": link.ID})
+			return
+		}
+		// Only duplicates are merged, so a link's destination never changes
+		if link.CanonicalHash == "" || link.CanonicalHash != target.CanonicalHash {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Link '%s' points somewhere other than the target", link.Slug), "id": link.ID})
+			return
+		}
+		// Merging rewrites the link, so the user's role must allow editing it
+		if err := access.Authorize(h.db, userID, link.GroupID, access.EditAction(userID, &link)); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You have read-only access to this link", "id": link.ID})
+			return
+		}
+		// Merges aren't queued for approval, so protected links are only
+		// merged by their approvers
+		rule, err := h.protectionFor(userID, link.OrganizationID, link.Slug)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check protected slugs"})
+			return
+		}
+		if rule != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Link '%s' is protected, so only its approvers can merge it", link.Slug), "id": link.ID})
+			return
+		}
+		mergedIDs[i] = link.ID
+	}
+
+	aliasUpdates := map[string]interface{}{
+		"alias_of_id": target.ID,
+		"url":         target.URL,
+	}
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		// Aliases of the merged links would otherwise point at another alias
+		if err := tx.Model(&models.Link{}).Where("alias_of_id IN ?", mergedIDs).Updates(aliasUpdates).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Link{}).Where("id IN ?", mergedIDs).Updates(aliasUpdates).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge links"})
+		return
+	}
+
+	if err := h.db.Where("id IN ?", mergedIDs).Order("id").Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch links"})
+		return
+	}
+
+	response := MergeDuplicatesResponse{
+		Target: linkToResponse(target),
+		Merged: make([]LinkResponse, len(links)),
+	}
+	for i, link := range links {
+		response.Merged[i] = linkToResponse(link)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
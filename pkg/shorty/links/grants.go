@@ -0,0 +1,238 @@
+package links
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+)
+
+// CreateGrantRequest represents the request to share a link. Set one of
+// user_id, email or group_id.
+type CreateGrantRequest struct {
+	UserID  uint   `json:"user_id"`
+	Email   string `json:"email" binding:"omitempty,email"`
+	GroupID uint   `json:"group_id"`
+	Role    string `json:"role" binding:"required,oneof=viewer editor"`
+}
+
+// GrantResponse represents a link grant in API responses
+type GrantResponse struct {
+	ID          uint   `json:"id"`
+	LinkID      uint   `json:"link_id"`
+	UserID      *uint  `json:"user_id,omitempty"`
+	UserEmail   string `json:"user_email,omitempty"`
+	UserName    string `json:"user_name,omitempty"`
+	GroupID     *uint  `json:"group_id,omitempty"`
+	GroupName   string `json:"group_name,omitempty"`
+	Role        string `json:"role"`
+	GrantedByID uint   `json:"granted_by_id"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func grantToResponse(grant models.LinkGrant) GrantResponse {
+	response := GrantResponse{
+		ID:          grant.ID,
+		LinkID:      grant.LinkID,
+		UserID:      grant.UserID,
+		GroupID:     grant.GroupID,
+		Role:        string(grant.Role),
+		GrantedByID: grant.GrantedByID,
+		CreatedAt:   grant.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if grant.User != nil {
+		response.UserEmail = grant.User.Email
+		response.UserName = grant.User.Name
+	}
+	if grant.Group != nil {
+		response.GroupName = grant.Group.Name
+	}
+	return response
+}
+
+// findOwnedLink looks up a link by slug and checks the user's role in the
+// group that owns it lets them change it. Only the owning group manages who a
+// link is shared with.
+func (h *Handler) findOwnedLink(c *gin.Context, userID uint) (*models.Link, bool) {
+	var link models.Link
+	if err := h.db.Where("slug = ?", c.Param("slug")).First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return nil, false
+	}
+
+	// Check the user's role lets them change the link
+	switch access.Authorize(h.db, userID, link.GroupID, access.EditAction(userID, &link)) {
+	case nil:
+	case access.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your role in this group doesn't allow sharing this link"})
+		return nil, false
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return nil, false
+	}
+
+	return &link, true
+}
+
+// ListGrants returns who a link is shared with
+// @Summary List link grants
+// @Description Get the users and groups outside the owning group that a link is shared with
+// @Tags links
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Success 200 {array} GrantResponse
+// @Failure 404 {object} map[string]string "Link not found"
+// @Security BearerAuth
+// @Router /links/{slug}/grants [get]
+func (h *Handler) ListGrants(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findOwnedLink(c, userID)
+	if !ok {
+		return
+	}
+
+	var grants []models.LinkGrant
+	if err := h.db.Preload("User").Preload("Group").Where("link_id = ?", link.ID).Order("id").Find(&grants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch grants"})
+		return
+	}
+
+	responses := make([]GrantResponse, len(grants))
+	for i, grant := range grants {
+		responses[i] = grantToResponse(grant)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// CreateGrant shares a link with a user or group
+// @Summary Share a link
+// @Description Share a single link with a user or group in the organization as a viewer or editor, without adding them to the owning group. Sharing again with the same user or group changes their role.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Param request body CreateGrantRequest true "Grantee and role"
+// @Success 201 {object} GrantResponse
+// @Success 200 {object} GrantResponse "Role changed"
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 404 {object} map[string]string "Link not found"
+// @Security BearerAuth
+// @Router /links/{slug}/grants [post]
+func (h *Handler) CreateGrant(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findOwnedLink(c, userID)
+	if !ok {
+		return
+	}
+
+	var req CreateGrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	grant := models.LinkGrant{LinkID: link.ID, Role: models.GrantRole(req.Role), GrantedByID: userID}
+	switch {
+	case req.GroupID != 0 && (req.UserID != 0 || req.Email != ""):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Share with either a user or a group"})
+		return
+
+	case req.GroupID != 0:
+		var group models.Group
+		if err := h.db.Where("id = ? AND organization_id = ?", req.GroupID, link.OrganizationID).First(&group).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Group not found in the link's organization"})
+			return
+		}
+		if group.ID == link.GroupID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The link already belongs to this group"})
+			return
+		}
+		grant.GroupID = &group.ID
+		grant.Group = &group
+
+	case req.UserID != 0 || req.Email != "":
+		var user models.User
+		query := h.db.Where("id = ?", req.UserID)
+		if req.Email != "" {
+			query = h.db.Where("email = ?", strings.ToLower(req.Email))
+		}
+		if err := query.First(&user).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User not found"})
+			return
+		}
+		if err := h.db.Where("user_id = ? AND organization_id = ?", user.ID, link.OrganizationID).First(&models.OrganizationMembership{}).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User is not a member of the link's organization"})
+			return
+		}
+		grant.UserID = &user.ID
+		grant.User = &user
+
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set user_id, email or group_id"})
+		return
+	}
+
+	// Sharing again with the same grantee changes their role
+	var existing models.LinkGrant
+	query := h.db.Where("link_id = ?", link.ID)
+	if grant.UserID != nil {
+		query = query.Where("user_id = ?", *grant.UserID)
+	} else {
+		query = query.Where("group_id = ?", *grant.GroupID)
+	}
+	if err := query.First(&existing).Error; err == nil {
+		existing.Role = grant.Role
+		if err := h.db.Save(&existing).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update grant"})
+			return
+		}
+		existing.User, existing.Group = grant.User, grant.Group
+		c.JSON(http.StatusOK, grantToResponse(existing))
+		return
+	}
+
+	if err := h.db.Omit("User", "Group").Create(&grant).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to share link"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, grantToResponse(grant))
+}
+
+// DeleteGrant revokes a link grant
+// @Summary Revoke a link grant
+// @Description Stop sharing a link with a user or group
+// @Tags links
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Param grantId path int true "Grant ID"
+// @Success 200 {object} map[string]string "Grant revoked"
+// @Failure 404 {object} map[string]string "Grant not found"
+// @Security BearerAuth
+// @Router /links/{slug}/grants/{grantId} [delete]
+func (h *Handler) DeleteGrant(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findOwnedLink(c, userID)
+	if !ok {
+		return
+	}
+
+	result := h.db.Where("id = ? AND link_id = ?", c.Param("grantId"), link.ID).Delete(&models.LinkGrant{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke grant"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grant not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Grant revoked"})
+}
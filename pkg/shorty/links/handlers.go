@@ -1,7 +1,8 @@
 package links
 
 import (
-	"math/rand"
+	"errors"
+	"fmt"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -9,8 +10,13 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/metadata"
 	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/slugs"
+	"github.com/mikepea/shorty/pkg/shorty/tagquery"
+	"github.com/mikepea/shorty/pkg/shorty/tags"
 	"gorm.io/gorm"
 )
 
@@ -18,62 +24,108 @@ var slugRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
 // Handler handles link-related requests
 type Handler struct {
-	db *gorm.DB
+	db         *gorm.DB
+	slugGen    *slugs.Generator
+	fetcher    *metadata.Fetcher
+	fetchAsync func(func()) // Runs background metadata fetches; replaced in tests
 }
 
 // NewHandler creates a new links handler
 func NewHandler(db *gorm.DB) *Handler {
-	return &Handler{db: db}
+	return &Handler{
+		db:         db,
+		slugGen:    slugs.NewGenerator(db),
+		fetcher:    metadata.NewFetcher(metadata.Options{}),
+		fetchAsync: func(fn func()) { go fn() },
+	}
+}
+
+// withDB returns a copy of the handler that uses db, so that helpers can run
+// inside a transaction. Background fetches must be started from the original
+// handler once the transaction has committed.
+func (h *Handler) withDB(db *gorm.DB) *Handler {
+	copied := *h
+	copied.db = db
+	copied.slugGen = slugs.NewGenerator(db)
+	return &copied
 }
 
 // CreateLinkRequest represents the request to create a link
 type CreateLinkRequest struct {
-	URL         string `json:"url" binding:"required,url"`
-	Slug        string `json:"slug" binding:"omitempty,min=1,max=50"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	IsPublic    bool   `json:"is_public"`
-	IsUnread    bool   `json:"is_unread"`
+	Kind        string     `json:"kind" binding:"omitempty,oneof=redirect page"` // Defaults to redirect
+	URL         string     `json:"url" binding:"omitempty,url"`                  // Required for redirects
+	Content     string     `json:"content"`                                      // Markdown, required for pages
+	Slug        string     `json:"slug" binding:"omitempty,min=1,max=50"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	IsPublic    *bool      `json:"is_public"` // Defaults to the group's default visibility
+	IsUnread    bool       `json:"is_unread"`
+	ExpiresAt   *time.Time `json:"expires_at"` // Defaults to the group's default expiry
 }
 
 // UpdateLinkRequest represents the request to update a link
 type UpdateLinkRequest struct {
-	URL         string `json:"url" binding:"omitempty,url"`
-	Slug        string `json:"slug" binding:"omitempty,min=1,max=50"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	IsPublic    *bool  `json:"is_public"`
-	IsUnread    *bool  `json:"is_unread"`
+	URL         string     `json:"url" binding:"omitempty,url"`
+	Content     *string    `json:"content"` // Pages only
+	Slug        string     `json:"slug" binding:"omitempty,min=1,max=50"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	IsPublic    *bool      `json:"is_public"`
+	IsUnread    *bool      `json:"is_unread"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	ClearExpiry bool       `json:"clear_expiry"` // Removes the expiry; can't be combined with expires_at
 }
 
 // LinkResponse represents a link in API responses
 type LinkResponse struct {
-	ID          uint   `json:"id"`
-	GroupID     uint   `json:"group_id"`
-	Slug        string `json:"slug"`
-	URL         string `json:"url"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	IsPublic    bool   `json:"is_public"`
-	IsUnread    bool   `json:"is_unread"`
-	ClickCount  uint   `json:"click_count"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID           uint   `json:"id"`
+	GroupID      uint   `json:"group_id"`
+	Slug         string `json:"slug"`
+	Kind         string `json:"kind"`
+	URL          string `json:"url"`
+	Content      string `json:"content,omitempty"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	IsPublic     bool   `json:"is_public"`
+	IsUnread     bool   `json:"is_unread"`
+	ImageURL     string `json:"image_url,omitempty"`
+	FaviconURL   string `json:"favicon_url,omitempty"`
+	ClickCount   uint   `json:"click_count"`
+	CommentCount uint   `json:"comment_count"`
+	ExpiresAt    string `json:"expires_at,omitempty"`
+	AliasOfID    *uint  `json:"alias_of_id,omitempty"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+
+	// Duplicates lists existing links in the organization with the same
+	// canonical URL. Only set when creating a link.
+	Duplicates []DuplicateLink `json:"duplicates,omitempty"`
 }
 
 func linkToResponse(link models.Link) LinkResponse {
+	var expiresAt string
+	if link.ExpiresAt != nil {
+		expiresAt = link.ExpiresAt.Format("2006-01-02T15:04:05Z")
+	}
 	return LinkResponse{
-		ID:          link.ID,
-		GroupID:     link.GroupID,
-		Slug:        link.Slug,
-		URL:         link.URL,
-		Title:       link.Title,
-		Description: link.Description,
-		IsPublic:    link.IsPublic,
-		IsUnread:    link.IsUnread,
-		ClickCount:  link.ClickCount,
-		CreatedAt:   link.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:   link.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ID:           link.ID,
+		GroupID:      link.GroupID,
+		Slug:         link.Slug,
+		Kind:         string(link.Kind),
+		URL:          link.URL,
+		Content:      link.Content,
+		Title:        link.Title,
+		Description:  link.Description,
+		IsPublic:     link.IsPublic,
+		IsUnread:     link.IsUnread,
+		ImageURL:     link.ImageURL,
+		FaviconURL:   link.FaviconURL,
+		ClickCount:   link.ClickCount,
+		CommentCount: link.CommentCount,
+		ExpiresAt:    expiresAt,
+		AliasOfID:    link.AliasOfID,
+		CreatedAt:    link.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:    link.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
@@ -86,27 +138,131 @@ func (e *ValidationError) Error() string {
 	return e.Message
 }
 
-// validateSlug checks if a slug is valid and available (deprecated - use validateSlugForOrg)
-func (h *Handler) validateSlug(slug string, excludeID uint) error {
-	return h.validateSlugForOrg(slug, excludeID, 0)
+// MaxPageContent is the largest page body accepted, in bytes
+const MaxPageContent = 64 << 10
+
+// validateNewLink checks a redirect has a URL and a page has content
+func validateNewLink(req CreateLinkRequest) error {
+	if models.LinkKind(req.Kind) != models.LinkKindPage {
+		if req.URL == "" {
+			return &ValidationError{"URL is required"}
+		}
+		return nil
+	}
+	if req.URL != "" {
+		return &ValidationError{"Pages have content instead of a URL"}
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		return &ValidationError{"Content is required for pages"}
+	}
+	if len(req.Content) > MaxPageContent {
+		return &ValidationError{"Content is too long"}
+	}
+	return nil
+}
+
+// applyGroupPolicy fills in the group's defaults for a new link and checks the
+// link against the group's rules
+func applyGroupPolicy(group *models.Group, req *CreateLinkRequest) error {
+	if req.IsPublic == nil {
+		isPublic := group.DefaultIsPublic
+		req.IsPublic = &isPublic
+	}
+	if req.ExpiresAt == nil {
+		req.ExpiresAt = group.DefaultExpiry(time.Now())
+	}
+
+	// A generated slug gets the group's prefix, so only a chosen one is checked
+	return checkGroupPolicy(group, &models.Link{Kind: models.LinkKind(req.Kind), URL: req.URL, Title: req.Title, Slug: req.Slug})
+}
+
+// checkGroupPolicy checks a link's title, destination and slug against the
+// rules of the group it's in or going into. An empty slug isn't checked.
+func checkGroupPolicy(group *models.Group, link *models.Link) error {
+	if group.RequireTitle && strings.TrimSpace(link.Title) == "" {
+		return &ValidationError{"This group requires a title for new links"}
+	}
+	if !link.IsPage() && !group.AllowsURL(link.URL) {
+		return allowedDomainsError(group)
+	}
+	if link.Slug != "" && !strings.HasPrefix(link.Slug, group.SlugPrefix) {
+		return slugPrefixError(group)
+	}
+	return nil
+}
+
+// checkUpdatePolicy checks the destination and slug an update sets against the
+// rules of the link's group. Links made before a rule changed are only held
+// to it when the part it covers changes.
+func (h *Handler) checkUpdatePolicy(link *models.Link, req UpdateLinkRequest) error {
+	var group models.Group
+	if err := h.db.First(&group, link.GroupID).Error; err != nil {
+		return err
+	}
+	if req.URL != "" && !group.AllowsURL(req.URL) {
+		return allowedDomainsError(&group)
+	}
+	if req.Slug != "" && req.Slug != link.Slug && !strings.HasPrefix(req.Slug, group.SlugPrefix) {
+		return slugPrefixError(&group)
+	}
+	return nil
+}
+
+func allowedDomainsError(group *models.Group) error {
+	return &ValidationError{fmt.Sprintf("Links in this group must point to %s", strings.Join(group.AllowedDomainList(), ", "))}
+}
+
+func slugPrefixError(group *models.Group) error {
+	return &ValidationError{fmt.Sprintf("Slugs in this group must start with '%s'", group.SlugPrefix)}
+}
+
+// validateLinkUpdate checks an update is consistent and fits the kind of link
+// being changed
+func validateLinkUpdate(link *models.Link, req UpdateLinkRequest) error {
+	if req.ClearExpiry && req.ExpiresAt != nil {
+		return &ValidationError{"Set either expires_at or clear_expiry, not both"}
+	}
+	if !link.IsPage() {
+		if req.Content != nil {
+			return &ValidationError{"Only pages have content"}
+		}
+		return nil
+	}
+	if req.URL != "" {
+		return &ValidationError{"Pages have content instead of a URL"}
+	}
+	if req.Content != nil && strings.TrimSpace(*req.Content) == "" {
+		return &ValidationError{"Content is required for pages"}
+	}
+	if req.Content != nil && len(*req.Content) > MaxPageContent {
+		return &ValidationError{"Content is too long"}
+	}
+	return nil
 }
 
-// validateSlugForOrg checks if a slug is valid and available within an organization
-func (h *Handler) validateSlugForOrg(slug string, excludeID uint, orgID uint) error {
+// validateSlugForOrg checks if a slug is valid and available within an organization.
+// Slugs of the form "namespace/name" can only be used for links in the group that
+// owns the namespace, and only by that group's admins.
+func (h *Handler) validateSlugForOrg(slug string, excludeID, orgID, groupID, userID uint) error {
 	if slug == "" {
 		return nil
 	}
 
 	// Check format
-	if !slugRegex.MatchString(slug) {
-		return &ValidationError{"Slug must contain only letters, numbers, hyphens, and underscores"}
+	namespace, name := slugs.SplitNamespace(slug)
+	if !slugRegex.MatchString(name) || (namespace != "" && !slugRegex.MatchString(namespace)) {
+		return &ValidationError{"Slug must contain only letters, numbers, hyphens, and underscores, optionally prefixed by a namespace (e.g. team/name)"}
 	}
 
 	// Check reserved slugs
-	reserved := []string{"api", "health", "admin", "login", "logout", "register", "auth"}
-	for _, r := range reserved {
-		if strings.EqualFold(slug, r) {
-			return &ValidationError{"This slug is reserved"}
+	if namespace == "" && slugs.IsReserved(slug) {
+		return &ValidationError{"This slug is reserved"}
+	}
+
+	// Check namespace ownership
+	if namespace != "" {
+		if err := h.checkNamespace(namespace, orgID, groupID, userID); err != nil {
+			return err
 		}
 	}
 
@@ -123,43 +279,19 @@ func (h *Handler) validateSlugForOrg(slug string, excludeID uint, orgID uint) er
 	return nil
 }
 
-// generateRandomString creates a random string of given length
-func generateRandomString(length int, charset string) string {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = charset[r.Intn(len(charset))]
+// checkNamespace verifies a namespace is owned by the link's group and that the
+// user is an admin of that group
+func (h *Handler) checkNamespace(namespace string, orgID, groupID, userID uint) error {
+	var owner models.Group
+	if err := h.db.Where("organization_id = ? AND namespace = ?", orgID, namespace).First(&owner).Error; err != nil {
+		return &ValidationError{fmt.Sprintf("Namespace '%s' does not exist", namespace)}
 	}
-	return string(b)
-}
-
-// generateSlug creates a unique slug (deprecated - use generateSlugForOrg)
-func (h *Handler) generateSlug() string {
-	return h.generateSlugForOrg(0)
-}
-
-// generateSlugForOrg creates a unique slug within an organization
-func (h *Handler) generateSlugForOrg(orgID uint) string {
-	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
-	const length = 8
-
-	for attempts := 0; attempts < 10; attempts++ {
-		slug := generateRandomString(length, charset)
-		var existing models.Link
-		if err := h.db.Where("organization_id = ? AND slug = ?", orgID, slug).First(&existing).Error; err != nil {
-			return slug
-		}
+	if owner.ID != groupID {
+		return &ValidationError{fmt.Sprintf("Namespace '%s' belongs to another group", namespace)}
 	}
 
-	// Fallback to longer slug if short ones are exhausted
-	return generateRandomString(12, charset)
-}
-
-// checkGroupMembership verifies the user is a member of the group
-func (h *Handler) checkGroupMembership(userID, groupID uint) error {
-	var membership models.GroupMembership
-	if err := h.db.Where("user_id = ? AND group_id = ?", userID, groupID).First(&membership).Error; err != nil {
-		return err
+	if !access.IsGroupAdmin(h.db, userID, groupID) {
+		return &ValidationError{"Only group admins can create links in the group's namespace"}
 	}
 	return nil
 }
@@ -172,8 +304,10 @@ func (h *Handler) checkGroupMembership(userID, groupID uint) error {
 // @Param id path int true "Group ID"
 // @Param is_unread query bool false "Filter by unread status"
 // @Param is_public query bool false "Filter by public status"
+// @Param tag query string false "Filter by tag name, including tags below it"
+// @Param tag_query query string false "Filter by a tag expression, like tag=oncall AND NOT tag=deprecated"
 // @Success 200 {array} LinkResponse
-// @Failure 400 {object} map[string]string "Invalid group ID"
+// @Failure 400 {object} map[string]string "Invalid group ID or tag query"
 // @Failure 404 {object} map[string]string "Group not found"
 // @Security BearerAuth
 // @Router /groups/{id}/links [get]
@@ -186,23 +320,28 @@ func (h *Handler) ListByGroup(c *gin.Context) {
 	}
 
 	// Check membership
-	if err := h.checkGroupMembership(userID, uint(groupID)); err != nil {
+	if err := access.Authorize(h.db, userID, uint(groupID), access.ActionView); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
 		return
 	}
 
-	var links []models.Link
-	query := h.db.Where("group_id = ?", groupID).Order("created_at DESC")
-
 	// Optional filters
+	params := SearchParams{TagQuery: c.Query("tag_query"), Tag: c.Query("tag"), GroupID: uint(groupID)}
 	if isUnread := c.Query("is_unread"); isUnread != "" {
-		query = query.Where("is_unread = ?", isUnread == "true")
+		v := isUnread == "true"
+		params.IsUnread = &v
 	}
 	if isPublic := c.Query("is_public"); isPublic != "" {
-		query = query.Where("is_public = ?", isPublic == "true")
+		v := isPublic == "true"
+		params.IsPublic = &v
+	}
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	if err := query.Find(&links).Error; err != nil {
+	var links []models.Link
+	if err := filterLinks(h.db.Model(&models.Link{}), params).Order("links.created_at DESC").Find(&links).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch links"})
 		return
 	}
@@ -217,14 +356,16 @@ func (h *Handler) ListByGroup(c *gin.Context) {
 
 // Create creates a new link in a group
 // @Summary Create a link
-// @Description Create a new shortened link in a group
+// @Description Create a new shortened link in a group. Set kind to "page" to create a page whose Markdown content is shown at the short URL instead of redirecting. Links with a protected slug are not created immediately; a change request is returned for approval instead.
 // @Tags links
 // @Accept json
 // @Produce json
 // @Param id path int true "Group ID"
 // @Param request body CreateLinkRequest true "Link details"
 // @Success 201 {object} LinkResponse
-// @Failure 400 {object} map[string]string "Validation error"
+// @Success 202 {object} ChangeRequestResponse "Awaiting approval"
+// @Failure 400 {object} map[string]string "Validation error, including links that break the group's rules"
+// @Failure 403 {object} map[string]string "Role doesn't allow adding links"
 // @Failure 404 {object} map[string]string "Group not found"
 // @Security BearerAuth
 // @Router /groups/{id}/links [post]
@@ -236,8 +377,13 @@ func (h *Handler) Create(c *gin.Context) {
 		return
 	}
 
-	// Check membership
-	if err := h.checkGroupMembership(userID, uint(groupID)); err != nil {
+	// Check the user's role lets them add links
+	switch access.Authorize(h.db, userID, uint(groupID), access.ActionCreate) {
+	case nil:
+	case access.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your role in this group doesn't allow adding links"})
+		return
+	default:
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
 		return
 	}
@@ -254,36 +400,122 @@ func (h *Handler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := validateNewLink(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := applyGroupPolicy(&group, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Handle slug - now scoped to organization
-	slug := req.Slug
-	if slug == "" {
-		slug = h.generateSlugForOrg(group.OrganizationID)
-	} else {
-		if err := h.validateSlugForOrg(slug, 0, group.OrganizationID); err != nil {
+	if req.Slug != "" {
+		if err := h.validateSlugForOrg(req.Slug, 0, group.OrganizationID, group.ID, userID); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 	}
 
+	// Protected slugs only change with approval
+	rule, err := h.protectionFor(userID, group.OrganizationID, req.Slug)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check protected slugs"})
+		return
+	}
+	if rule != nil {
+		h.requestChange(c, userID, rule, models.ChangeActionCreate, group.ID, nil, req.Slug, req)
+		return
+	}
+
+	link, err := h.createLink(userID, &group, req)
+	if errors.Is(err, slugs.ErrExhausted) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Couldn't generate a slug you can use; choose one instead"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create link"})
+		return
+	}
+	h.fetchNewLinkMetadata(&link)
+
+	// Warn about other links to the same destination; the link is still created
+	duplicates, err := h.findDuplicates(link.OrganizationID, link.CanonicalHash, link.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for duplicates"})
+		return
+	}
+
+	response := linkToResponse(link)
+	response.Duplicates = duplicates
+	c.JSON(http.StatusCreated, response)
+}
+
+// createLink inserts a new link in the group, generating a slug if none was
+// requested, and adds the group's default tags. The slug must already have
+// been validated. Generated slugs are never protected ones the user would
+// need approval for. Callers fetch the link's metadata once it is saved.
+func (h *Handler) createLink(userID uint, group *models.Group, req CreateLinkRequest) (models.Link, error) {
 	link := models.Link{
 		OrganizationID: group.OrganizationID,
-		GroupID:        uint(groupID),
+		GroupID:        group.ID,
 		CreatedByID:    userID,
-		Slug:           slug,
+		Slug:           req.Slug,
+		Kind:           models.LinkKindRedirect,
 		URL:            req.URL,
 		Title:          req.Title,
 		Description:    req.Description,
-		IsPublic:       req.IsPublic,
+		IsPublic:       req.IsPublic != nil && *req.IsPublic,
 		IsUnread:       req.IsUnread,
+		ExpiresAt:      req.ExpiresAt,
+	}
+	if models.LinkKind(req.Kind) == models.LinkKindPage {
+		link.Kind = models.LinkKindPage
+		link.Content = req.Content
+	} else {
+		link.CanonicalHash = h.canonicalHash(group.OrganizationID, req.URL)
 	}
 
-	if err := h.db.Create(&link).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create link"})
-		return
+	// Without a slug, one is generated using the organization's strategy
+	var err error
+	if link.Slug == "" {
+		err = h.slugGen.CreateLinkExcept(&link, group.SlugPrefix, func(slug string) bool {
+			rule, err := h.protectionFor(userID, group.OrganizationID, slug)
+			return rule != nil || err != nil
+		})
+	} else {
+		err = h.db.Create(&link).Error
+	}
+	if err != nil {
+		return link, err
 	}
 
-	c.JSON(http.StatusCreated, linkToResponse(link))
+	if names := group.DefaultTagList(); len(names) > 0 {
+		linkTags, err := tags.FindOrCreate(h.db, group.OrganizationID, names)
+		if err != nil {
+			return link, err
+		}
+		if err := h.db.Model(&link).Association("Tags").Append(linkTags); err != nil {
+			return link, err
+		}
+	}
+
+	// Pages start their history
+	if link.IsPage() {
+		if err := h.recordRevision(&link, userID); err != nil {
+			return link, err
+		}
+	}
+
+	return link, nil
+}
+
+// fetchNewLinkMetadata fills in the title and other page details of a new
+// redirect in the background
+func (h *Handler) fetchNewLinkMetadata(link *models.Link) {
+	if !link.IsPage() && link.Title == "" {
+		h.fetchLinkMetadataAsync(link.ID)
+	}
 }
 
 // GetBySlug returns a link by its slug
@@ -306,12 +538,10 @@ func (h *Handler) GetBySlug(c *gin.Context) {
 		return
 	}
 
-	// Check if user has access (public or member of group)
-	if !link.IsPublic {
-		if err := h.checkGroupMembership(userID, link.GroupID); err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
-			return
-		}
+	// Check if user has access (public, member of group or shared with them)
+	if !access.CanView(h.db, userID, &link) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
 	}
 
 	c.JSON(http.StatusOK, linkToResponse(link))
@@ -319,14 +549,16 @@ func (h *Handler) GetBySlug(c *gin.Context) {
 
 // Update updates a link
 // @Summary Update a link
-// @Description Update an existing link by slug
+// @Description Update an existing link by slug. Changes to links with a protected slug, or renames to one, are not applied immediately; a change request is returned for approval instead.
 // @Tags links
 // @Accept json
 // @Produce json
 // @Param slug path string true "Link slug"
 // @Param request body UpdateLinkRequest true "Updated link details"
 // @Success 200 {object} LinkResponse
+// @Success 202 {object} ChangeRequestResponse "Awaiting approval"
 // @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Read-only access to the link"
 // @Failure 404 {object} map[string]string "Link not found"
 // @Security BearerAuth
 // @Router /links/{slug} [put]
@@ -340,8 +572,13 @@ func (h *Handler) Update(c *gin.Context) {
 		return
 	}
 
-	// Check membership
-	if err := h.checkGroupMembership(userID, link.GroupID); err != nil {
+	// Check access: group members whose role allows it and editors the link is shared with can change it
+	switch access.Role(h.db, userID, &link) {
+	case models.GrantRoleEditor:
+	case models.GrantRoleViewer:
+		c.JSON(http.StatusForbidden, gin.H{"error": "You have read-only access to this link"})
+		return
+	default:
 		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
 		return
 	}
@@ -351,19 +588,62 @@ func (h *Handler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := validateLinkUpdate(&link, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Validate new slug if provided
 	if req.Slug != "" && req.Slug != link.Slug {
-		if err := h.validateSlug(req.Slug, link.ID); err != nil {
+		if err := h.validateSlugForOrg(req.Slug, link.ID, link.OrganizationID, link.GroupID, userID); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+	}
+	if err := h.checkUpdatePolicy(&link, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Protected slugs only change with approval, whether the link has one or is being renamed to one
+	rule, err := h.protectionFor(userID, link.OrganizationID, link.Slug, req.Slug)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check protected slugs"})
+		return
+	}
+	if rule != nil {
+		h.requestChange(c, userID, rule, models.ChangeActionUpdate, link.GroupID, &link.ID, link.Slug, req)
+		return
+	}
+
+	if err := h.updateLink(&link, req, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, linkToResponse(link))
+}
+
+// updateLink applies an update request to a link on behalf of editorID. A
+// new slug must already have been validated.
+func (h *Handler) updateLink(link *models.Link, req UpdateLinkRequest, editorID uint) error {
+	if req.Slug != "" {
 		link.Slug = req.Slug
 	}
 
+	// Edits to a page's title or body are kept as a new revision
+	revised := link.IsPage() && ((req.Content != nil && *req.Content != link.Content) || (req.Title != "" && req.Title != link.Title))
+	if req.Content != nil {
+		link.Content = *req.Content
+	}
+
 	// Update fields
-	if req.URL != "" {
+	urlChanged := req.URL != "" && req.URL != link.URL
+	if urlChanged {
 		link.URL = req.URL
+		link.CanonicalHash = h.canonicalHash(link.OrganizationID, req.URL)
+		// Giving an alias its own destination detaches it from its target
+		link.AliasOfID = nil
 	}
 	if req.Title != "" {
 		link.Title = req.Title
@@ -377,13 +657,31 @@ func (h *Handler) Update(c *gin.Context) {
 	if req.IsUnread != nil {
 		link.IsUnread = *req.IsUnread
 	}
+	if req.ExpiresAt != nil {
+		link.ExpiresAt = req.ExpiresAt
+	}
+	if req.ClearExpiry {
+		link.ExpiresAt = nil
+	}
 
-	if err := h.db.Save(&link).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update link"})
-		return
+	if err := h.db.Save(link).Error; err != nil {
+		return err
+	}
+	if revised {
+		if err := h.recordRevision(link, editorID); err != nil {
+			return err
+		}
 	}
 
-	c.JSON(http.StatusOK, linkToResponse(link))
+	// Keep aliases of this link pointing at the same destination
+	if urlChanged {
+		h.db.Model(&models.Link{}).Where("alias_of_id = ?", link.ID).Updates(map[string]interface{}{
+			"url":            link.URL,
+			"canonical_hash": link.CanonicalHash,
+		})
+	}
+
+	return nil
 }
 
 // Delete deletes a link
@@ -393,6 +691,8 @@ func (h *Handler) Update(c *gin.Context) {
 // @Produce json
 // @Param slug path string true "Link slug"
 // @Success 200 {object} map[string]string "Link deleted"
+// @Success 202 {object} ChangeRequestResponse "Awaiting approval"
+// @Failure 403 {object} map[string]string "Role doesn't allow deleting the link"
 // @Failure 404 {object} map[string]string "Link not found"
 // @Security BearerAuth
 // @Router /links/{slug} [delete]
@@ -406,12 +706,28 @@ func (h *Handler) Delete(c *gin.Context) {
 		return
 	}
 
-	// Check membership
-	if err := h.checkGroupMembership(userID, link.GroupID); err != nil {
+	// Check the user's role lets them delete the link
+	switch access.Authorize(h.db, userID, link.GroupID, access.EditAction(userID, &link)) {
+	case nil:
+	case access.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your role in this group doesn't allow deleting this link"})
+		return
+	default:
 		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
 		return
 	}
 
+	// Protected slugs are only deleted with approval
+	rule, err := h.protectionFor(userID, link.OrganizationID, link.Slug)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check protected slugs"})
+		return
+	}
+	if rule != nil {
+		h.requestChange(c, userID, rule, models.ChangeActionDelete, link.GroupID, &link.ID, link.Slug, struct{}{})
+		return
+	}
+
 	if err := h.db.Delete(&link).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete link"})
 		return
@@ -420,73 +736,108 @@ func (h *Handler) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Link deleted"})
 }
 
-// Search searches links across all user's groups
-// @Summary Search links
-// @Description Search links across all groups the user has access to
-// @Tags links
-// @Produce json
-// @Param q query string false "Search query (searches title, description, URL)"
-// @Param is_unread query bool false "Filter by unread status"
-// @Param is_public query bool false "Filter by public status"
-// @Param group_id query int false "Filter by group ID"
-// @Param tag query string false "Filter by tag name"
-// @Param limit query int false "Max results (default 50, max 100)"
-// @Param offset query int false "Offset for pagination"
-// @Success 200 {array} LinkResponse
-// @Security BearerAuth
-// @Router /links [get]
-func (h *Handler) Search(c *gin.Context) {
-	userID, _ := auth.GetUserID(c)
+// SearchParams holds the filters accepted by Search
+type SearchParams struct {
+	Q        string `json:"q,omitempty"`
+	IsUnread *bool  `json:"is_unread,omitempty"`
+	IsPublic *bool  `json:"is_public,omitempty"`
+	GroupID  uint   `json:"group_id,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+	TagQuery string `json:"tag_query,omitempty"` // Boolean tag expression, see tagquery.Parse
+}
 
-	// Get user's group IDs
-	var memberships []models.GroupMembership
-	if err := h.db.Where("user_id = ?", userID).Find(&memberships).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
-		return
+// Validate checks the filters can be applied
+func (p SearchParams) Validate() error {
+	if p.TagQuery != "" {
+		if _, err := tagquery.Parse(p.TagQuery); err != nil {
+			return &ValidationError{err.Error()}
+		}
 	}
+	return nil
+}
 
-	groupIDs := make([]uint, len(memberships))
-	for i, m := range memberships {
-		groupIDs[i] = m.GroupID
+// searchParamsFromQuery reads search filters from the request query string
+func searchParamsFromQuery(c *gin.Context) SearchParams {
+	var params SearchParams
+	params.Q = c.Query("q")
+	if isUnread := c.Query("is_unread"); isUnread != "" {
+		v := isUnread == "true"
+		params.IsUnread = &v
 	}
-
-	if len(groupIDs) == 0 {
-		c.JSON(http.StatusOK, []LinkResponse{})
-		return
+	if isPublic := c.Query("is_public"); isPublic != "" {
+		v := isPublic == "true"
+		params.IsPublic = &v
+	}
+	if groupID, err := strconv.ParseUint(c.Query("group_id"), 10, 32); err == nil {
+		params.GroupID = uint(groupID)
 	}
+	params.Tag = c.Query("tag")
+	params.TagQuery = c.Query("tag_query")
+	return params
+}
+
+// searchQuery builds a query for links in the given groups matching the search filters
+func (h *Handler) searchQuery(groupIDs []uint, params SearchParams) *gorm.DB {
+	return filterLinks(h.db.Model(&models.Link{}).Where("links.group_id IN ?", groupIDs), params)
+}
 
-	query := h.db.Where("group_id IN ?", groupIDs).Order("created_at DESC")
+// visibleSearchQuery builds a query for links the user can see matching the
+// search filters: links in their groups and links shared with them
+func (h *Handler) visibleSearchQuery(userID uint, params SearchParams) *gorm.DB {
+	return filterLinks(h.db.Model(&models.Link{}).Scopes(access.Visible(h.db, userID)), params)
+}
 
+// filterLinks applies the search filters to a links query
+func filterLinks(query *gorm.DB, params SearchParams) *gorm.DB {
 	// Search term
-	if q := c.Query("q"); q != "" {
-		searchTerm := "%" + q + "%"
-		query = query.Where("title LIKE ? OR description LIKE ? OR url LIKE ?", searchTerm, searchTerm, searchTerm)
+	if params.Q != "" {
+		searchTerm := "%" + params.Q + "%"
+		query = query.Where("links.title LIKE ? OR links.description LIKE ? OR links.url LIKE ? OR links.content LIKE ?", searchTerm, searchTerm, searchTerm, searchTerm)
 	}
 
 	// Filters
-	if isUnread := c.Query("is_unread"); isUnread != "" {
-		query = query.Where("is_unread = ?", isUnread == "true")
-	}
-	if isPublic := c.Query("is_public"); isPublic != "" {
-		query = query.Where("is_public = ?", isPublic == "true")
-	}
-	if groupID := c.Query("group_id"); groupID != "" {
-		query = query.Where("group_id = ?", groupID)
-	}
-	if tag := c.Query("tag"); tag != "" {
-		query = query.Joins("JOIN link_tags ON link_tags.link_id = links.id").
-			Joins("JOIN tags ON tags.id = link_tags.tag_id").
-			Where("tags.name = ?", tag)
+	if params.IsUnread != nil {
+		query = query.Where("links.is_unread = ?", *params.IsUnread)
+	}
+	if params.IsPublic != nil {
+		query = query.Where("links.is_public = ?", *params.IsPublic)
+	}
+	if params.GroupID != 0 {
+		query = query.Where("links.group_id = ?", params.GroupID)
+	}
+	if params.Tag != "" {
+		// Tags are hierarchical: infra also matches infra/monitoring
+		sql, args := tagquery.Tag(params.Tag).SQL()
+		query = query.Where(sql, args...)
+	}
+	if params.TagQuery != "" {
+		expr, err := tagquery.Parse(params.TagQuery)
+		if err != nil {
+			// Callers validate first, so this only fails the query
+			query.AddError(err)
+			return query
+		}
+		sql, args := expr.SQL()
+		query = query.Where(sql, args...)
 	}
 
-	// Pagination
+	return query
+}
+
+// getUserGroupIDs returns all group IDs the user is a member of, including
+// groups below the ones they belong to
+func (h *Handler) getUserGroupIDs(userID uint) ([]uint, error) {
+	return access.GroupIDs(h.db, userID)
+}
+
+// paginate applies the limit (default 50, max 100) and offset query parameters
+func paginate(c *gin.Context, query *gorm.DB) *gorm.DB {
 	limit := 50
 	if l := c.Query("limit"); l != "" {
 		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
 			limit = parsed
 		}
 	}
-	query = query.Limit(limit)
 
 	offset := 0
 	if o := c.Query("offset"); o != "" {
@@ -494,7 +845,37 @@ func (h *Handler) Search(c *gin.Context) {
 			offset = parsed
 		}
 	}
-	query = query.Offset(offset)
+
+	return query.Limit(limit).Offset(offset)
+}
+
+// Search searches links across all user's groups
+// @Summary Search links
+// @Description Search links across all groups the user has access to, and links shared with them
+// @Tags links
+// @Produce json
+// @Param q query string false "Search query (searches title, description, URL and page content)"
+// @Param is_unread query bool false "Filter by unread status"
+// @Param is_public query bool false "Filter by public status"
+// @Param group_id query int false "Filter by group ID"
+// @Param tag query string false "Filter by tag name, including tags below it (infra matches infra/monitoring)"
+// @Param tag_query query string false "Filter by a tag expression, like tag=oncall AND (tag=sre OR tag=infra) AND NOT tag=deprecated"
+// @Param limit query int false "Max results (default 50, max 100)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {array} LinkResponse
+// @Failure 400 {object} map[string]string "Invalid tag query"
+// @Security BearerAuth
+// @Router /links [get]
+func (h *Handler) Search(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	params := searchParamsFromQuery(c)
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := paginate(c, h.visibleSearchQuery(userID, params).Order("links.created_at DESC"))
 
 	var links []models.Link
 	if err := query.Find(&links).Error; err != nil {
@@ -521,6 +902,55 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.PUT("/links/:slug", h.Update)
 	rg.DELETE("/links/:slug", h.Delete)
 
+	// Page metadata
+	rg.POST("/links/:slug/metadata", h.RefreshMetadata)
+
+	// Bulk operations
+	rg.POST("/links/bulk", h.Bulk)
+
+	// Move or copy links between groups
+	rg.POST("/links/:slug/transfer", h.Transfer)
+	rg.POST("/groups/:id/links/transfer", h.TransferGroup)
+
+	// Duplicate destinations
+	rg.GET("/duplicates", h.ListDuplicates)
+	rg.POST("/duplicates/merge", h.MergeDuplicates)
+
+	// Per-user link state
+	rg.GET("/links/:slug/state", h.GetState)
+	rg.PUT("/links/:slug/state", h.UpdateState)
+	rg.GET("/me/links/pinned", h.ListPinned)
+	rg.GET("/me/links/favorites", h.ListFavorites)
+	rg.GET("/me/links/unread", h.ListUnread)
+
+	// Saved searches and those pinned to group dashboards
+	rg.GET("/saved-searches", h.ListSavedSearches)
+	rg.POST("/saved-searches", h.CreateSavedSearch)
+	rg.GET("/saved-searches/:id", h.GetSavedSearch)
+	rg.PUT("/saved-searches/:id", h.UpdateSavedSearch)
+	rg.DELETE("/saved-searches/:id", h.DeleteSavedSearch)
+	rg.GET("/saved-searches/:id/links", h.RunSavedSearch)
+	rg.GET("/groups/:id/pinned-searches", h.ListPinnedSearches)
+
+	// History of changes made by organization admins
+	rg.GET("/links/:slug/history", h.ListHistory)
+
+	// Page history
+	rg.GET("/links/:slug/revisions", h.ListRevisions)
+	rg.GET("/links/:slug/revisions/:revision", h.GetRevision)
+	rg.POST("/links/:slug/revisions/:revision/restore", h.RestoreRevision)
+
+	// Sharing single links outside the owning group
+	rg.GET("/links/:slug/grants", h.ListGrants)
+	rg.POST("/links/:slug/grants", h.CreateGrant)
+	rg.DELETE("/links/:slug/grants/:grantId", h.DeleteGrant)
+
+	// Change requests for protected slugs
+	rg.GET("/change-requests", h.ListChangeRequests)
+	rg.GET("/change-requests/:id", h.GetChangeRequest)
+	rg.POST("/change-requests/:id/approve", h.ApproveChangeRequest)
+	rg.POST("/change-requests/:id/reject", h.RejectChangeRequest)
+
 	// Search across all groups
 	rg.GET("/links", h.Search)
 }
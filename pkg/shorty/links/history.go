@@ -0,0 +1,60 @@
+package links
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+)
+
+// LinkEventResponse represents an entry in a link's history
+type LinkEventResponse struct {
+	ID        uint   `json:"id"`
+	Action    string `json:"action"`
+	Detail    string `json:"detail"`
+	ActorID   uint   `json:"actor_id"`
+	ActorName string `json:"actor_name"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ListHistory returns a link's history
+// @Summary Get link history
+// @Description Get changes made to a link by organization admins, such as its tags being renamed, merged or deleted, newest first
+// @Tags links
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Success 200 {array} LinkEventResponse
+// @Failure 404 {object} map[string]string "Link not found"
+// @Security BearerAuth
+// @Router /links/{slug}/history [get]
+func (h *Handler) ListHistory(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	var link models.Link
+	if err := h.db.Where("slug = ?", c.Param("slug")).First(&link).Error; err != nil || !access.CanView(h.db, userID, &link) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+
+	var events []models.LinkEvent
+	if err := h.db.Preload("Actor").Where("link_id = ?", link.ID).Order("id DESC").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch history"})
+		return
+	}
+
+	responses := make([]LinkEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = LinkEventResponse{
+			ID:        event.ID,
+			Action:    event.Action,
+			Detail:    event.Detail,
+			ActorID:   event.ActorID,
+			ActorName: event.Actor.Name,
+			CreatedAt: event.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
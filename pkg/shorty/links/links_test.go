@@ -5,11 +5,19 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/history"
+	"github.com/mikepea/shorty/pkg/shorty/metadata"
 	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/slugs"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -56,7 +64,26 @@ func createTestGroup(t *testing.T, db *gorm.DB, name string, userID uint) models
 func setupTestRouter(db *gorm.DB) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	r.UseRawPath = true
 	handler := NewHandler(db)
+	// Never fetch page metadata over the network in tests
+	handler.fetchAsync = func(fn func()) {}
+
+	api := r.Group("/api")
+	api.Use(auth.AuthMiddleware())
+	handler.RegisterRoutes(api)
+
+	return r
+}
+
+// setupMetadataTestRouter returns a router whose handler fetches metadata
+// synchronously and may reach local test servers
+func setupMetadataTestRouter(db *gorm.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handler := NewHandler(db)
+	handler.fetcher = metadata.NewFetcher(metadata.Options{AllowPrivateNetworks: true})
+	handler.fetchAsync = func(fn func()) { fn() }
 
 	api := r.Group("/api")
 	api.Use(auth.AuthMiddleware())
@@ -198,6 +225,94 @@ func TestCreateLinkReservedSlug(t *testing.T) {
 	}
 }
 
+func TestCreateLinkGroupPolicy(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestOrgGroup(t, db, "SRE", 1, user.ID)
+	db.Model(&group).Updates(map[string]interface{}{
+		"default_is_public":   true,
+		"default_tags":        "oncall,infra/monitoring",
+		"default_expiry_days": 30,
+		"allowed_domains":     "example.com",
+		"require_title":       true,
+		"slug_prefix":         "sre-",
+	})
+
+	// Defaults fill in what the request leaves out
+	link := createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://wiki.example.com/runbook", Title: "Runbook"})
+	if !strings.HasPrefix(link.Slug, "sre-") {
+		t.Errorf("Expected a generated slug starting with 'sre-', got %s", link.Slug)
+	}
+	if !link.IsPublic {
+		t.Error("Expected the link to be public by default")
+	}
+	expiresAt, err := time.Parse(time.RFC3339, link.ExpiresAt)
+	if err != nil || expiresAt.Before(time.Now().AddDate(0, 0, 29)) || expiresAt.After(time.Now().AddDate(0, 0, 31)) {
+		t.Errorf("Expected the link to expire in 30 days, got %q", link.ExpiresAt)
+	}
+	var loaded models.Link
+	db.Preload("Tags").First(&loaded, link.ID)
+	if len(loaded.Tags) != 2 {
+		t.Errorf("Expected the group's 2 default tags, got %+v", loaded.Tags)
+	}
+
+	// Explicit values win over the defaults
+	private := false
+	link = createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://example.com", Title: "Home", Slug: "sre-home", IsPublic: &private})
+	if link.IsPublic || link.Slug != "sre-home" {
+		t.Errorf("Expected a private link at sre-home, got %+v", link)
+	}
+
+	// Requests that break the rules are rejected with a reason
+	tests := []struct {
+		body CreateLinkRequest
+		want string
+	}{
+		{CreateLinkRequest{URL: "https://example.com"}, "This group requires a title for new links"},
+		{CreateLinkRequest{URL: "https://other.net", Title: "Other"}, "Links in this group must point to example.com"},
+		{CreateLinkRequest{URL: "https://example.com", Title: "Home", Slug: "home"}, "Slugs in this group must start with 'sre-'"},
+	}
+	for _, tt := range tests {
+		jsonBody, _ := json.Marshal(tt.body)
+		req, _ := http.NewRequest("POST", "/api/groups/"+strconv.FormatUint(uint64(group.ID), 10)+"/links", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		var response map[string]string
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		if resp.Code != http.StatusBadRequest || response["error"] != tt.want {
+			t.Errorf("Expected 400 %q for %+v, got %d: %s", tt.want, tt.body, resp.Code, resp.Body.String())
+		}
+	}
+	// Updates can't move a link off the allowed domains or the slug prefix
+	for _, update := range []UpdateLinkRequest{{URL: "https://other.net"}, {Slug: "home"}} {
+		jsonBody, _ := json.Marshal(update)
+		req, _ := http.NewRequest("PUT", "/api/links/sre-home", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %+v, got %d", update, resp.Code)
+		}
+	}
+
+	// Links moved into the group must follow its rules too
+	other := createTestOrgGroup(t, db, "Other", 1, user.ID)
+	db.Create(&models.Link{OrganizationID: 1, GroupID: other.ID, CreatedByID: user.ID, Slug: "outside", URL: "https://other.net", Title: "Outside"})
+	resp, _ := doTransfer(t, router, user, "/api/links/outside/transfer", TransferRequest{TargetGroupID: group.ID})
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 moving a link in, got %d: %s", resp.Code, resp.Body.String())
+	}
+	_, bulk := doBulk(t, router, user, BulkRequest{Slugs: []string{"outside"}, Action: BulkActionMove, GroupID: group.ID})
+	if bulk.Failed != 1 {
+		t.Errorf("Expected the bulk move to fail, got %+v", bulk)
+	}
+}
+
 func TestListLinks(t *testing.T) {
 	db := setupTestDB(t)
 	router := setupTestRouter(db)
@@ -209,7 +324,7 @@ func TestListLinks(t *testing.T) {
 		db.Create(&models.Link{
 			GroupID:     group.ID,
 			CreatedByID: user.ID,
-			Slug:        generateRandomString(8, "abcdef"),
+			Slug:        slugs.Random(8),
 			URL:         "https://example.com",
 		})
 	}
@@ -292,6 +407,114 @@ func TestGetPrivateLinkNotMember(t *testing.T) {
 	}
 }
 
+func TestGetLinkInSubgroup(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	owner := createTestUser(t, db, "owner@example.com")
+	member := createTestUser(t, db, "member@example.com")
+	engineering := createTestGroup(t, db, "Engineering", member.ID)
+	sre := createTestGroup(t, db, "SRE", owner.ID)
+	oncall := createTestGroup(t, db, "Oncall", owner.ID)
+	db.Model(&sre).Update("parent_id", engineering.ID)
+	db.Model(&oncall).Update("parent_id", sre.ID)
+
+	db.Create(&models.Link{GroupID: oncall.ID, CreatedByID: owner.ID, Slug: "pager", URL: "https://example.com"})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", getAuthHeader(member))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Members of Engineering see links two levels down
+	if resp := get("/api/links/pager"); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	resp := get("/api/links")
+	var links []LinkResponse
+	json.Unmarshal(resp.Body.Bytes(), &links)
+	if len(links) != 1 {
+		t.Errorf("Expected the subgroup's link in the list, got %d links", len(links))
+	}
+	if resp := get("/api/groups/" + strconv.FormatUint(uint64(oncall.ID), 10) + "/links"); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 listing the subgroup's links, got %d", resp.Code)
+	}
+
+	// Membership doesn't flow upwards
+	db.Create(&models.Link{GroupID: engineering.ID, CreatedByID: member.ID, Slug: "handbook", URL: "https://example.com"})
+	req, _ := http.NewRequest("GET", "/api/links/handbook", nil)
+	req.Header.Set("Authorization", getAuthHeader(owner))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a member of a subgroup, got %d", resp.Code)
+	}
+}
+
+func TestGroupRoles(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	owner := createTestUser(t, db, "owner@example.com")
+	group := createTestGroup(t, db, "Test Group", owner.ID)
+	groupPath := "/api/groups/" + strconv.FormatUint(uint64(group.ID), 10) + "/links"
+
+	users := make(map[models.GroupRole]models.User)
+	for _, role := range []models.GroupRole{models.GroupRoleViewer, models.GroupRoleContributor, models.GroupRoleEditor} {
+		user := createTestUser(t, db, string(role)+"@example.com")
+		db.Create(&models.GroupMembership{UserID: user.ID, GroupID: group.ID, Role: role})
+		users[role] = user
+	}
+	db.Create(&models.Link{GroupID: group.ID, CreatedByID: owner.ID, Slug: "owners", URL: "https://example.com"})
+
+	do := func(user models.User, method, path string, body interface{}) int {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	tests := []struct {
+		role               models.GroupRole
+		create, editOthers int
+	}{
+		{models.GroupRoleViewer, http.StatusForbidden, http.StatusForbidden},
+		{models.GroupRoleContributor, http.StatusCreated, http.StatusForbidden},
+		{models.GroupRoleEditor, http.StatusCreated, http.StatusOK},
+	}
+	for _, tt := range tests {
+		user := users[tt.role]
+		if code := do(user, "GET", groupPath, nil); code != http.StatusOK {
+			t.Errorf("%s: expected to list links, got %d", tt.role, code)
+		}
+		slug := string(tt.role) + "-link"
+		if code := do(user, "POST", groupPath, CreateLinkRequest{URL: "https://example.org", Slug: slug}); code != tt.create {
+			t.Errorf("%s: expected %d creating a link, got %d", tt.role, tt.create, code)
+		}
+		if code := do(user, "PUT", "/api/links/owners", UpdateLinkRequest{Title: "Changed"}); code != tt.editOthers {
+			t.Errorf("%s: expected %d editing another's link, got %d", tt.role, tt.editOthers, code)
+		}
+		if tt.create != http.StatusCreated {
+			continue
+		}
+		// Anyone who can add links can change and delete their own
+		if code := do(user, "PUT", "/api/links/"+slug, UpdateLinkRequest{Title: "Changed"}); code != http.StatusOK {
+			t.Errorf("%s: expected to edit their own link, got %d", tt.role, code)
+		}
+		if code := do(user, "DELETE", "/api/links/"+slug, nil); code != http.StatusOK {
+			t.Errorf("%s: expected to delete their own link, got %d", tt.role, code)
+		}
+	}
+
+	if code := do(users[models.GroupRoleContributor], "DELETE", "/api/links/owners", nil); code != http.StatusForbidden {
+		t.Errorf("Expected a contributor not to delete another's link, got %d", code)
+	}
+}
+
 func TestGetPublicLinkNotMember(t *testing.T) {
 	db := setupTestDB(t)
 	router := setupTestRouter(db)
@@ -358,6 +581,51 @@ func TestUpdateLink(t *testing.T) {
 	}
 }
 
+func TestUpdateLinkExpiry(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+	db.Create(&models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: "launch", URL: "https://example.com"})
+
+	update := func(body UpdateLinkRequest) (*httptest.ResponseRecorder, LinkResponse) {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest("PUT", "/api/links/launch", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		var response LinkResponse
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		return resp, response
+	}
+
+	expiry := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	resp, response := update(UpdateLinkRequest{ExpiresAt: &expiry})
+	if resp.Code != http.StatusOK || response.ExpiresAt == "" {
+		t.Fatalf("Expected the expiry to be set, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	if resp, _ := update(UpdateLinkRequest{ExpiresAt: &expiry, ClearExpiry: true}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 setting and clearing the expiry, got %d", resp.Code)
+	}
+
+	// Other changes leave the expiry alone
+	if _, response := update(UpdateLinkRequest{Title: "Launch"}); response.ExpiresAt == "" {
+		t.Error("Expected the expiry to be kept")
+	}
+
+	resp, response = update(UpdateLinkRequest{ClearExpiry: true})
+	if resp.Code != http.StatusOK || response.ExpiresAt != "" {
+		t.Errorf("Expected the expiry to be cleared, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var link models.Link
+	db.Where("slug = ?", "launch").First(&link)
+	if link.ExpiresAt != nil {
+		t.Errorf("Expected no expiry in the database, got %v", link.ExpiresAt)
+	}
+}
+
 func TestDeleteLink(t *testing.T) {
 	db := setupTestDB(t)
 	router := setupTestRouter(db)
@@ -425,3 +693,1420 @@ func TestSearchLinks(t *testing.T) {
 		t.Errorf("Expected 'Golang Tutorial', got %s", links[0].Title)
 	}
 }
+
+func TestSearchLinksByParentTag(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+
+	tagged := map[string]string{
+		"infra-home": "infra",
+		"grafana":    "infra/monitoring/grafana",
+		"terraform":  "infrastructure",
+		"legacy":     "infra_old/x",
+	}
+	for slug, tagName := range tagged {
+		link := models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: slug, URL: "https://example.com"}
+		db.Create(&link)
+		tag := models.Tag{Name: tagName}
+		db.Create(&tag)
+		db.Model(&link).Association("Tags").Append(&tag)
+	}
+
+	req, _ := http.NewRequest("GET", "/api/links?tag=infra", nil)
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var links []LinkResponse
+	json.Unmarshal(resp.Body.Bytes(), &links)
+
+	slugs := make([]string, len(links))
+	for i, link := range links {
+		slugs[i] = link.Slug
+	}
+	sort.Strings(slugs)
+	if strings.Join(slugs, ",") != "grafana,infra-home" {
+		t.Errorf("Expected infra and the tags below it only, got %v", slugs)
+	}
+}
+
+func TestSearchLinksByTagQuery(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+
+	tagged := map[string][]string{
+		"pager":     {"oncall", "sre"},
+		"dashboard": {"oncall", "infra/monitoring"},
+		"old-pager": {"oncall", "sre", "deprecated"},
+		"handbook":  {"sre"},
+	}
+	tagsByName := make(map[string]*models.Tag)
+	for slug, names := range tagged {
+		link := models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: slug, URL: "https://example.com"}
+		db.Create(&link)
+		for _, name := range names {
+			if tagsByName[name] == nil {
+				tagsByName[name] = &models.Tag{Name: name}
+				db.Create(tagsByName[name])
+			}
+			db.Model(&link).Association("Tags").Append(tagsByName[name])
+		}
+	}
+
+	search := func(path string) (int, []string) {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		var links []LinkResponse
+		json.Unmarshal(resp.Body.Bytes(), &links)
+		slugs := make([]string, len(links))
+		for i, link := range links {
+			slugs[i] = link.Slug
+		}
+		sort.Strings(slugs)
+		return resp.Code, slugs
+	}
+
+	expr := url.QueryEscape("tag=oncall AND (tag=sre OR tag=infra) AND NOT tag=deprecated")
+	for _, path := range []string{"/api/links?tag_query=" + expr, "/api/groups/" + strconv.FormatUint(uint64(group.ID), 10) + "/links?tag_query=" + expr} {
+		code, slugs := search(path)
+		if code != http.StatusOK {
+			t.Fatalf("Expected status 200 from %s, got %d", path, code)
+		}
+		if strings.Join(slugs, ",") != "dashboard,pager" {
+			t.Errorf("Expected dashboard and pager from %s, got %v", path, slugs)
+		}
+	}
+
+	if code, _ := search("/api/links?tag_query=" + url.QueryEscape("tag=oncall AND")); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid tag query, got %d", code)
+	}
+}
+
+func doBulk(t *testing.T, router *gin.Engine, user models.User, body BulkRequest) (*httptest.ResponseRecorder, BulkResponse) {
+	jsonBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", "/api/links/bulk", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	var response BulkResponse
+	json.Unmarshal(resp.Body.Bytes(), &response)
+	return resp, response
+}
+
+func TestBulkDelete(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+
+	for _, slug := range []string{"one", "two", "three"} {
+		db.Create(&models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: slug, URL: "https://example.com"})
+	}
+
+	resp, response := doBulk(t, router, user, BulkRequest{
+		Slugs:  []string{"one", "two", "missing"},
+		Action: BulkActionDelete,
+	})
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if response.Succeeded != 2 || response.Failed != 1 {
+		t.Errorf("Expected 2 succeeded and 1 failed, got %d and %d", response.Succeeded, response.Failed)
+	}
+
+	var count int64
+	db.Model(&models.Link{}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected 1 remaining link, got %d", count)
+	}
+}
+
+func TestBulkDryRun(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+
+	db.Create(&models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: "one", URL: "https://example.com", Title: "Golang"})
+	db.Create(&models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: "two", URL: "https://example.com", Title: "Python"})
+
+	resp, response := doBulk(t, router, user, BulkRequest{
+		Query:  &SearchParams{Q: "Golang"},
+		Action: BulkActionDelete,
+		DryRun: true,
+	})
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if len(response.Results) != 1 || response.Results[0].Status != BulkStatusDryRun {
+		t.Errorf("Expected a single dry_run result, got %+v", response.Results)
+	}
+
+	var count int64
+	db.Model(&models.Link{}).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected dry run to leave 2 links, got %d", count)
+	}
+}
+
+func TestBulkAddTagsAndSetPublic(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+
+	link := models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: "one", URL: "https://example.com"}
+	db.Create(&link)
+
+	resp, _ := doBulk(t, router, user, BulkRequest{
+		IDs:    []uint{link.ID},
+		Action: BulkActionAddTags,
+		Tags:   []string{"oncall", "sre"},
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	value := true
+	resp, _ = doBulk(t, router, user, BulkRequest{
+		Query:  &SearchParams{Tag: "oncall"},
+		Action: BulkActionSetPublic,
+		Value:  &value,
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var loaded models.Link
+	db.Preload("Tags").First(&loaded, link.ID)
+	if len(loaded.Tags) != 2 {
+		t.Errorf("Expected 2 tags, got %d", len(loaded.Tags))
+	}
+	if !loaded.IsPublic {
+		t.Error("Expected link to be public")
+	}
+}
+
+func TestBulkAtomicRollsBack(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	other := createTestUser(t, db, "other@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+	otherGroup := createTestGroup(t, db, "Other Group", other.ID)
+
+	db.Create(&models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: "mine", URL: "https://example.com"})
+	db.Create(&models.Link{GroupID: otherGroup.ID, CreatedByID: other.ID, Slug: "theirs", URL: "https://example.com"})
+
+	resp, response := doBulk(t, router, user, BulkRequest{
+		Slugs:  []string{"mine", "theirs"},
+		Action: BulkActionDelete,
+		Atomic: true,
+	})
+
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("Expected status 409, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if response.Succeeded != 0 {
+		t.Errorf("Expected nothing to succeed, got %d", response.Succeeded)
+	}
+
+	var count int64
+	db.Model(&models.Link{}).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected both links to remain, got %d", count)
+	}
+}
+
+func TestBulkMove(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	owner := createTestUser(t, db, "owner@example.com")
+	group := createTestOrgGroup(t, db, "Team", 1, user.ID)
+	target := createTestOrgGroup(t, db, "Archive", 1, owner.ID)
+	elsewhere := createTestOrgGroup(t, db, "Other Org", 2, owner.ID)
+	db.Create(&models.GroupMembership{UserID: user.ID, GroupID: target.ID, Role: models.GroupRoleMember})
+	db.Create(&models.GroupMembership{UserID: user.ID, GroupID: elsewhere.ID, Role: models.GroupRoleMember})
+	db.Create(&models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: owner.ID, Slug: "shared", URL: "https://example.com"})
+
+	// Members of the target group can move links within the organization
+	resp, response := doBulk(t, router, user, BulkRequest{Slugs: []string{"shared"}, Action: BulkActionMove, GroupID: target.ID})
+	if resp.Code != http.StatusOK || response.Succeeded != 1 {
+		t.Fatalf("Expected the move to succeed, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	// but not into another organization
+	resp, _ = doBulk(t, router, user, BulkRequest{Slugs: []string{"shared"}, Action: BulkActionMove, GroupID: elsewhere.ID})
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 moving to another organization, got %d", resp.Code)
+	}
+	var link models.Link
+	db.Where("slug = ?", "shared").First(&link)
+	if link.GroupID != target.ID || link.OrganizationID != 1 {
+		t.Errorf("Expected the link to stay in the archive, got %+v", link)
+	}
+}
+
+func doTransfer(t *testing.T, router *gin.Engine, user models.User, path string, body TransferRequest) (*httptest.ResponseRecorder, TransferResponse) {
+	jsonBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	var response TransferResponse
+	json.Unmarshal(resp.Body.Bytes(), &response)
+	return resp, response
+}
+
+func createTestOrgGroup(t *testing.T, db *gorm.DB, name string, orgID, userID uint) models.Group {
+	group := models.Group{Name: name, OrganizationID: orgID}
+	if err := db.Create(&group).Error; err != nil {
+		t.Fatalf("Failed to create test group: %v", err)
+	}
+	db.Create(&models.GroupMembership{UserID: userID, GroupID: group.ID, Role: models.GroupRoleAdmin})
+	return group
+}
+
+func TestTransferMoveAcrossOrganizations(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	source := createTestOrgGroup(t, db, "Source", 1, user.ID)
+	target := createTestOrgGroup(t, db, "Target", 2, user.ID)
+
+	tag := models.Tag{OrganizationID: 1, Name: "ops"}
+	link := models.Link{OrganizationID: 1, GroupID: source.ID, CreatedByID: user.ID, Slug: "runbook", URL: "https://example.com", Tags: []models.Tag{tag}}
+	db.Create(&link)
+	db.Create(&models.LinkGrant{LinkID: link.ID, UserID: &user.ID, Role: models.GrantRoleViewer, GrantedByID: user.ID})
+
+	resp, response := doTransfer(t, router, user, "/api/links/runbook/transfer", TransferRequest{TargetGroupID: target.ID})
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if response.Transferred != 1 {
+		t.Errorf("Expected 1 transferred link, got %d", response.Transferred)
+	}
+
+	var moved models.Link
+	db.Where("slug = ?", "runbook").First(&moved)
+	if moved.GroupID != target.ID || moved.OrganizationID != 2 {
+		t.Errorf("Expected link in group %d org 2, got group %d org %d", target.ID, moved.GroupID, moved.OrganizationID)
+	}
+
+	// Tags belong to an organization, so the link takes the target's tag
+	var tags []models.Tag
+	db.Model(&moved).Association("Tags").Find(&tags)
+	if len(tags) != 1 || tags[0].Name != "ops" || tags[0].OrganizationID != 2 {
+		t.Errorf("Expected the target organization's ops tag, got %+v", tags)
+	}
+
+	// Grants to the old organization's users and groups are dropped
+	var grants int64
+	db.Model(&models.LinkGrant{}).Where("link_id = ?", moved.ID).Count(&grants)
+	if grants != 0 {
+		t.Errorf("Expected grants to be removed, got %d", grants)
+	}
+}
+
+func TestTransferConflictStrategies(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	source := createTestOrgGroup(t, db, "Source", 1, user.ID)
+	target := createTestOrgGroup(t, db, "Target", 2, user.ID)
+
+	db.Create(&models.Link{OrganizationID: 1, GroupID: source.ID, CreatedByID: user.ID, Slug: "runbook", URL: "https://example.com/1"})
+	db.Create(&models.Link{OrganizationID: 2, GroupID: target.ID, CreatedByID: user.ID, Slug: "runbook", URL: "https://example.com/2"})
+
+	resp, _ := doTransfer(t, router, user, "/api/groups/1/links/transfer", TransferRequest{TargetGroupID: target.ID})
+	if resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for fail strategy, got %d", resp.Code)
+	}
+
+	resp, response := doTransfer(t, router, user, "/api/groups/1/links/transfer", TransferRequest{TargetGroupID: target.ID, OnConflict: ConflictSkip})
+	if resp.Code != http.StatusOK || response.Skipped != 1 {
+		t.Errorf("Expected 1 skipped link, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	resp, response = doTransfer(t, router, user, "/api/groups/1/links/transfer", TransferRequest{
+		TargetGroupID: target.ID,
+		Mode:          TransferModeCopy,
+		OnConflict:    ConflictRename,
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if len(response.Results) != 1 || response.Results[0].NewSlug != "runbook-2" {
+		t.Errorf("Expected copy renamed to runbook-2, got %+v", response.Results)
+	}
+
+	var count int64
+	db.Model(&models.Link{}).Where("organization_id = ?", 1).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected original link to remain after copy, got %d links", count)
+	}
+}
+
+func TestTransferStrippedSlugIsValidated(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	source := createTestOrgGroup(t, db, "SRE", 1, user.ID)
+	db.Model(&source).Update("namespace", "sre")
+	target := createTestOrgGroup(t, db, "Platform", 1, user.ID)
+	db.Create(&models.Link{OrganizationID: 1, GroupID: source.ID, CreatedByID: user.ID, Slug: "sre/api", URL: "https://example.com/api"})
+
+	// Leaving its namespace would turn the slug into a reserved one
+	resp, _ := doTransfer(t, router, user, "/api/links/sre%2Fapi/transfer", TransferRequest{TargetGroupID: target.ID})
+	if resp.Code != http.StatusConflict || !strings.Contains(resp.Body.String(), "reserved") {
+		t.Errorf("Expected status 409 for a reserved slug, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	resp, response := doTransfer(t, router, user, "/api/links/sre%2Fapi/transfer", TransferRequest{TargetGroupID: target.ID, OnConflict: ConflictRename})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if len(response.Results) != 1 || response.Results[0].NewSlug != "api-2" {
+		t.Errorf("Expected the link renamed to api-2, got %+v", response.Results)
+	}
+}
+
+func TestTransferCopyPage(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	source := createTestOrgGroup(t, db, "Source", 1, user.ID)
+	target := createTestOrgGroup(t, db, "Target", 2, user.ID)
+	db.Create(&models.Link{OrganizationID: 1, GroupID: source.ID, CreatedByID: user.ID, Slug: "oncall", Kind: models.LinkKindPage, Title: "On call", Content: "# Rota", ImageURL: "https://example.com/rota.png"})
+
+	resp, response := doTransfer(t, router, user, "/api/links/oncall/transfer", TransferRequest{TargetGroupID: target.ID, Mode: TransferModeCopy})
+	if resp.Code != http.StatusOK || len(response.Results) != 1 {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var copied models.Link
+	db.First(&copied, response.Results[0].NewID)
+	if !copied.IsPage() || copied.Content != "# Rota" || copied.ImageURL != "https://example.com/rota.png" || copied.CanonicalHash != "" {
+		t.Errorf("Expected a copy of the page, got %+v", copied)
+	}
+
+	// The copy starts its own history
+	var revisions []models.LinkRevision
+	db.Where("link_id = ?", copied.ID).Find(&revisions)
+	if len(revisions) != 1 || revisions[0].Revision != 1 || revisions[0].Content != "# Rota" || revisions[0].EditedByID != user.ID {
+		t.Errorf("Expected a first revision for the copy, got %+v", revisions)
+	}
+}
+
+func TestTransferRequiresTargetAdmin(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	other := createTestUser(t, db, "other@example.com")
+	source := createTestOrgGroup(t, db, "Source", 1, user.ID)
+	target := createTestOrgGroup(t, db, "Target", 1, other.ID)
+	db.Create(&models.GroupMembership{UserID: user.ID, GroupID: target.ID, Role: models.GroupRoleMember})
+
+	db.Create(&models.Link{OrganizationID: 1, GroupID: source.ID, CreatedByID: user.ID, Slug: "runbook", URL: "https://example.com"})
+
+	resp, _ := doTransfer(t, router, user, "/api/links/runbook/transfer", TransferRequest{TargetGroupID: target.ID})
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+}
+
+func newMetadataTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+<title>Fetched Title</title>
+<meta name="description" content="Fetched description">
+<meta property="og:image" content="/preview.png">
+</head></html>`))
+	}))
+}
+
+func TestCreateLinkFetchesMetadata(t *testing.T) {
+	server := newMetadataTestServer()
+	defer server.Close()
+
+	db := setupTestDB(t)
+	router := setupMetadataTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	createTestGroup(t, db, "Test Group", user.ID)
+
+	jsonBody, _ := json.Marshal(CreateLinkRequest{URL: server.URL, Slug: "fetched"})
+	req, _ := http.NewRequest("POST", "/api/groups/1/links", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var link models.Link
+	db.Where("slug = ?", "fetched").First(&link)
+	if link.Title != "Fetched Title" {
+		t.Errorf("Expected fetched title, got %q", link.Title)
+	}
+	if link.Description != "Fetched description" {
+		t.Errorf("Expected fetched description, got %q", link.Description)
+	}
+	if link.ImageURL != server.URL+"/preview.png" {
+		t.Errorf("Expected image URL, got %q", link.ImageURL)
+	}
+	if link.FetchedAt == nil || link.FetchStatus != http.StatusOK {
+		t.Errorf("Expected fetch to be recorded, got fetched_at=%v status=%d", link.FetchedAt, link.FetchStatus)
+	}
+}
+
+func TestRefreshMetadata(t *testing.T) {
+	server := newMetadataTestServer()
+	defer server.Close()
+
+	db := setupTestDB(t)
+	router := setupMetadataTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+
+	db.Create(&models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: user.ID, Slug: "docs", URL: server.URL, Title: "My Title"})
+
+	refresh := func(body string) (*httptest.ResponseRecorder, LinkResponse) {
+		req, _ := http.NewRequest("POST", "/api/links/docs/metadata", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		var response LinkResponse
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		return resp, response
+	}
+
+	// Existing title is kept, empty description is filled in
+	resp, response := refresh("")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if response.Title != "My Title" {
+		t.Errorf("Expected title to be kept, got %q", response.Title)
+	}
+	if response.Description != "Fetched description" {
+		t.Errorf("Expected description to be filled in, got %q", response.Description)
+	}
+
+	resp, response = refresh(`{"overwrite": true}`)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if response.Title != "Fetched Title" {
+		t.Errorf("Expected title to be overwritten, got %q", response.Title)
+	}
+}
+
+func createLinkViaAPI(t *testing.T, router *gin.Engine, user models.User, groupID uint, body CreateLinkRequest) LinkResponse {
+	jsonBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", "/api/groups/"+strconv.FormatUint(uint64(groupID), 10)+"/links", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var response LinkResponse
+	json.Unmarshal(resp.Body.Bytes(), &response)
+	return response
+}
+
+func TestCreateLinkWarnsAboutDuplicates(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestOrgGroup(t, db, "Test Group", 1, user.ID)
+	other := createTestOrgGroup(t, db, "Other Org Group", 2, user.ID)
+
+	first := createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://Grafana.example.com:443/d/abc?from=now-1h&orgId=1", Slug: "dash", Title: "Dashboard"})
+	if len(first.Duplicates) != 0 {
+		t.Errorf("Expected no duplicates for the first link, got %+v", first.Duplicates)
+	}
+
+	// Same organization, same destination after canonicalization
+	second := createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://grafana.example.com/d/abc?orgId=1&from=now-1h&utm_source=slack", Slug: "dash2", Title: "Dashboard"})
+	if len(second.Duplicates) != 1 || second.Duplicates[0].Slug != "dash" {
+		t.Errorf("Expected duplicate warning for 'dash', got %+v", second.Duplicates)
+	}
+
+	// Links in other organizations aren't duplicates
+	third := createLinkViaAPI(t, router, user, other.ID, CreateLinkRequest{URL: "https://grafana.example.com/d/abc?orgId=1&from=now-1h", Slug: "dash", Title: "Dashboard"})
+	if len(third.Duplicates) != 0 {
+		t.Errorf("Expected no duplicates across organizations, got %+v", third.Duplicates)
+	}
+}
+
+func TestOrganizationTrackingParams(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	db.Create(&models.Organization{Name: "Acme", Slug: "acme", TrackingParams: "ref, src_*"})
+	group := createTestOrgGroup(t, db, "Test Group", 1, user.ID)
+
+	createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://example.com/page?ref=home", Slug: "page", Title: "Page"})
+	second := createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://example.com/page?src_team=ops", Slug: "page2", Title: "Page"})
+
+	if len(second.Duplicates) != 1 {
+		t.Errorf("Expected organization tracking params to be ignored, got %+v", second.Duplicates)
+	}
+}
+
+func TestListAndMergeDuplicates(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestOrgGroup(t, db, "Test Group", 1, user.ID)
+
+	target := createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://example.com/dash?a=1&b=2", Slug: "dash", Title: "Dashboard"})
+	dup := createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://EXAMPLE.com/dash?b=2&a=1", Slug: "dash-old", Title: "Dashboard"})
+	createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://example.com/other", Slug: "other", Title: "Other"})
+
+	req, _ := http.NewRequest("GET", "/api/duplicates", nil)
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var clusters []DuplicateCluster
+	json.Unmarshal(resp.Body.Bytes(), &clusters)
+	if len(clusters) != 1 || len(clusters[0].Links) != 2 {
+		t.Fatalf("Expected one cluster of 2 links, got %+v", clusters)
+	}
+	if clusters[0].CanonicalURL != "https://example.com/dash?a=1&b=2" {
+		t.Errorf("Expected canonical URL, got %s", clusters[0].CanonicalURL)
+	}
+
+	// Viewers and contributors can't merge links they can't edit
+	viewer := createTestUser(t, db, "viewer@example.com")
+	contributor := createTestUser(t, db, "contributor@example.com")
+	db.Create(&models.GroupMembership{UserID: viewer.ID, GroupID: group.ID, Role: models.GroupRoleViewer})
+	db.Create(&models.GroupMembership{UserID: contributor.ID, GroupID: group.ID, Role: models.GroupRoleContributor})
+	jsonBody, _ := json.Marshal(MergeDuplicatesRequest{TargetID: target.ID, LinkIDs: []uint{dup.ID}})
+	for _, other := range []models.User{viewer, contributor} {
+		req, _ = http.NewRequest("POST", "/api/duplicates/merge", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(other))
+		resp = httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for %s, got %d", other.Email, resp.Code)
+		}
+	}
+
+	req, _ = http.NewRequest("POST", "/api/duplicates/merge", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var merged MergeDuplicatesResponse
+	json.Unmarshal(resp.Body.Bytes(), &merged)
+	if len(merged.Merged) != 1 || merged.Merged[0].AliasOfID == nil || *merged.Merged[0].AliasOfID != target.ID {
+		t.Errorf("Expected dash-old to become an alias of dash, got %+v", merged.Merged)
+	}
+
+	// Aliases no longer show up as duplicates
+	req, _ = http.NewRequest("GET", "/api/duplicates", nil)
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	json.Unmarshal(resp.Body.Bytes(), &clusters)
+	if len(clusters) != 0 {
+		t.Errorf("Expected no clusters after merge, got %+v", clusters)
+	}
+}
+
+func TestMergeDuplicatesRejectsPagesAndOtherDestinations(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestOrgGroup(t, db, "Test Group", 1, user.ID)
+
+	target := createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://example.com/dash", Slug: "dash", Title: "Dashboard"})
+	other := createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{URL: "https://example.com/other", Slug: "other", Title: "Other"})
+	page := createLinkViaAPI(t, router, user, group.ID, CreateLinkRequest{Kind: string(models.LinkKindPage), Content: "# Dashboards", Slug: "dashboards", Title: "Dashboards"})
+
+	tests := []struct {
+		name    string
+		request MergeDuplicatesRequest
+	}{
+		{"page as target", MergeDuplicatesRequest{TargetID: page.ID, LinkIDs: []uint{target.ID}}},
+		{"page merged", MergeDuplicatesRequest{TargetID: target.ID, LinkIDs: []uint{page.ID}}},
+		{"different destination", MergeDuplicatesRequest{TargetID: target.ID, LinkIDs: []uint{other.ID}}},
+	}
+	for _, tt := range tests {
+		jsonBody, _ := json.Marshal(tt.request)
+		req, _ := http.NewRequest("POST", "/api/duplicates/merge", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d: %s", tt.name, resp.Code, resp.Body.String())
+		}
+	}
+
+	// Nothing was merged
+	var aliases int64
+	db.Model(&models.Link{}).Where("alias_of_id IS NOT NULL").Count(&aliases)
+	if aliases != 0 {
+		t.Errorf("Expected no aliases, got %d", aliases)
+	}
+	var unchanged models.Link
+	db.First(&unchanged, other.ID)
+	if unchanged.URL != "https://example.com/other" {
+		t.Errorf("Expected the other link's destination to be unchanged, got %s", unchanged.URL)
+	}
+}
+
+func doUpdateState(t *testing.T, router *gin.Engine, user models.User, slug string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("PUT", "/api/links/"+slug+"/state", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func getMyLinks(t *testing.T, router *gin.Engine, user models.User, list string) []MyLinkResponse {
+	req, _ := http.NewRequest("GET", "/api/me/links/"+list, nil)
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var response []MyLinkResponse
+	json.Unmarshal(resp.Body.Bytes(), &response)
+	return response
+}
+
+func TestUserLinkStateIsPerUser(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	alice := createTestUser(t, db, "alice@example.com")
+	bob := createTestUser(t, db, "bob@example.com")
+	group := createTestGroup(t, db, "Team", alice.ID)
+	db.Create(&models.GroupMembership{UserID: bob.ID, GroupID: group.ID, Role: models.GroupRoleMember})
+
+	db.Create(&models.Link{GroupID: group.ID, CreatedByID: alice.ID, Slug: "article", URL: "https://example.com/a", IsUnread: true})
+	db.Create(&models.Link{GroupID: group.ID, CreatedByID: alice.ID, Slug: "docs", URL: "https://example.com/d", IsUnread: true})
+
+	// Alice reads the article; Bob still sees it as unread
+	resp := doUpdateState(t, router, alice, "article", `{"is_unread": false, "notes": "Worth a re-read"}`)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var state LinkStateResponse
+	json.Unmarshal(resp.Body.Bytes(), &state)
+	if state.IsUnread || state.Notes != "Worth a re-read" {
+		t.Errorf("Expected read link with notes, got %+v", state)
+	}
+
+	if unread := getMyLinks(t, router, alice, "unread"); len(unread) != 1 || unread[0].Slug != "docs" {
+		t.Errorf("Expected only docs unread for alice, got %+v", unread)
+	}
+	if unread := getMyLinks(t, router, bob, "unread"); len(unread) != 2 {
+		t.Errorf("Expected both links unread for bob, got %d", len(unread))
+	}
+
+	// The group-level flag is unchanged
+	var link models.Link
+	db.Where("slug = ?", "article").First(&link)
+	if !link.IsUnread {
+		t.Error("Expected group-level is_unread to be unchanged")
+	}
+}
+
+func TestPinnedAndFavoriteLinks(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	other := createTestUser(t, db, "other@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+	otherGroup := createTestGroup(t, db, "Other Group", other.ID)
+
+	for _, slug := range []string{"one", "two", "three"} {
+		db.Create(&models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: slug, URL: "https://example.com"})
+	}
+	db.Create(&models.Link{GroupID: otherGroup.ID, CreatedByID: other.ID, Slug: "private", URL: "https://example.com"})
+
+	doUpdateState(t, router, user, "one", `{"is_pinned": true}`)
+	doUpdateState(t, router, user, "three", `{"is_pinned": true, "is_favorite": true}`)
+
+	pinned := getMyLinks(t, router, user, "pinned")
+	if len(pinned) != 2 || !pinned[0].IsPinned {
+		t.Fatalf("Expected 2 pinned links, got %+v", pinned)
+	}
+
+	favorites := getMyLinks(t, router, user, "favorites")
+	if len(favorites) != 1 || favorites[0].Slug != "three" {
+		t.Errorf("Expected three as the only favorite, got %+v", favorites)
+	}
+
+	// Unpinning removes the link from the list
+	doUpdateState(t, router, user, "one", `{"is_pinned": false}`)
+	if pinned := getMyLinks(t, router, user, "pinned"); len(pinned) != 1 || pinned[0].Slug != "three" {
+		t.Errorf("Expected only three pinned, got %+v", pinned)
+	}
+
+	// State can't be set on links outside the user's groups
+	if resp := doUpdateState(t, router, user, "private", `{"is_pinned": true}`); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+}
+
+func doSavedSearchRequest(t *testing.T, router *gin.Engine, user models.User, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, "/api"+path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestSavedSearchRunAndShare(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	owner := createTestUser(t, db, "owner@example.com")
+	member := createTestUser(t, db, "member@example.com")
+	outsider := createTestUser(t, db, "outsider@example.com")
+	group := createTestGroup(t, db, "SRE", owner.ID)
+	db.Create(&models.GroupMembership{UserID: member.ID, GroupID: group.ID, Role: models.GroupRoleMember})
+
+	oncall := models.Tag{Name: "oncall"}
+	db.Create(&oncall)
+	tagged := models.Link{GroupID: group.ID, CreatedByID: owner.ID, Slug: "runbook", URL: "https://example.com/runbook", IsUnread: true}
+	db.Create(&tagged)
+	db.Model(&tagged).Association("Tags").Append(&oncall)
+	db.Create(&models.Link{GroupID: group.ID, CreatedByID: owner.ID, Slug: "untagged", URL: "https://example.com/other", IsUnread: true})
+	readTagged := models.Link{GroupID: group.ID, CreatedByID: owner.ID, Slug: "read-runbook", URL: "https://example.com/read"}
+	db.Create(&readTagged)
+	db.Model(&readTagged).Update("is_unread", false)
+	db.Model(&readTagged).Association("Tags").Append(&oncall)
+
+	// A personal search is only visible to its owner
+	resp := doSavedSearchRequest(t, router, owner, "POST", "/saved-searches",
+		`{"name": "Unread on-call", "params": {"tag": "oncall", "is_unread": true}}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var search SavedSearchResponse
+	json.Unmarshal(resp.Body.Bytes(), &search)
+	path := "/saved-searches/" + strconv.FormatUint(uint64(search.ID), 10)
+
+	resp = doSavedSearchRequest(t, router, owner, "GET", path+"/links", "")
+	var links []LinkResponse
+	json.Unmarshal(resp.Body.Bytes(), &links)
+	if len(links) != 1 || links[0].Slug != "runbook" {
+		t.Fatalf("Expected only runbook, got %+v", links)
+	}
+
+	if resp := doSavedSearchRequest(t, router, member, "GET", path+"/links", ""); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a personal search, got %d", resp.Code)
+	}
+
+	// Sharing with the group makes it runnable by members, but not editable
+	groupJSON := strconv.FormatUint(uint64(group.ID), 10)
+	if resp := doSavedSearchRequest(t, router, owner, "PUT", path, `{"group_id": `+groupJSON+`}`); resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp := doSavedSearchRequest(t, router, member, "GET", path+"/links", ""); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for a shared search, got %d", resp.Code)
+	}
+	if resp := doSavedSearchRequest(t, router, member, "DELETE", path, ""); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-owner member, got %d", resp.Code)
+	}
+	if resp := doSavedSearchRequest(t, router, outsider, "GET", path, ""); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a non-member, got %d", resp.Code)
+	}
+
+	resp = doSavedSearchRequest(t, router, member, "GET", "/saved-searches", "")
+	var searches []SavedSearchResponse
+	json.Unmarshal(resp.Body.Bytes(), &searches)
+	if len(searches) != 1 || searches[0].CanEdit {
+		t.Errorf("Expected one shared, read-only search for the member, got %+v", searches)
+	}
+}
+
+func TestPinnedSearches(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	outsider := createTestUser(t, db, "outsider@example.com")
+	group := createTestGroup(t, db, "SRE", user.ID)
+	groupJSON := strconv.FormatUint(uint64(group.ID), 10)
+
+	db.Create(&models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: "unread-one", URL: "https://example.com/1", IsUnread: true})
+	read := models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: "read-one", URL: "https://example.com/2"}
+	db.Create(&read)
+	db.Model(&read).Update("is_unread", false)
+
+	// Only searches shared with a group can be pinned
+	if resp := doSavedSearchRequest(t, router, user, "POST", "/saved-searches", `{"name": "Mine", "is_pinned": true}`); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.Code)
+	}
+
+	doSavedSearchRequest(t, router, user, "POST", "/saved-searches",
+		`{"name": "Unread", "group_id": `+groupJSON+`, "is_pinned": true, "params": {"is_unread": true}}`)
+	doSavedSearchRequest(t, router, user, "POST", "/saved-searches",
+		`{"name": "Not pinned", "group_id": `+groupJSON+`}`)
+
+	resp := doSavedSearchRequest(t, router, user, "GET", "/groups/"+groupJSON+"/pinned-searches", "")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var pinned []PinnedSearchResponse
+	json.Unmarshal(resp.Body.Bytes(), &pinned)
+	if len(pinned) != 1 || pinned[0].Name != "Unread" || pinned[0].LinkCount != 1 {
+		t.Errorf("Expected the pinned Unread search with 1 link, got %+v", pinned)
+	}
+
+	if resp := doSavedSearchRequest(t, router, outsider, "GET", "/groups/"+groupJSON+"/pinned-searches", ""); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a non-member, got %d", resp.Code)
+	}
+
+	// Viewers can't share searches with the group, and only admins can pin them
+	viewer := createTestUser(t, db, "viewer@example.com")
+	member := createTestUser(t, db, "member@example.com")
+	db.Create(&models.GroupMembership{UserID: viewer.ID, GroupID: group.ID, Role: models.GroupRoleViewer})
+	db.Create(&models.GroupMembership{UserID: member.ID, GroupID: group.ID, Role: models.GroupRoleMember})
+	if resp := doSavedSearchRequest(t, router, viewer, "POST", "/saved-searches", `{"name": "Shared", "group_id": `+groupJSON+`}`); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 sharing as a viewer, got %d", resp.Code)
+	}
+	if resp := doSavedSearchRequest(t, router, member, "POST", "/saved-searches", `{"name": "Pinned", "group_id": `+groupJSON+`, "is_pinned": true}`); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 pinning as a member, got %d", resp.Code)
+	}
+	resp = doSavedSearchRequest(t, router, member, "POST", "/saved-searches", `{"name": "Shared", "group_id": `+groupJSON+`}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 sharing as a member, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var shared SavedSearchResponse
+	json.Unmarshal(resp.Body.Bytes(), &shared)
+	path := "/saved-searches/" + strconv.FormatUint(uint64(shared.ID), 10)
+	if resp := doSavedSearchRequest(t, router, member, "PUT", path, `{"is_pinned": true}`); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 pinning an existing search as a member, got %d", resp.Code)
+	}
+	if resp := doSavedSearchRequest(t, router, user, "PUT", path, `{"is_pinned": true}`); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 pinning as an admin, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestNamespacedSlugs(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	admin := createTestUser(t, db, "admin@example.com")
+	member := createTestUser(t, db, "member@example.com")
+	sre := createTestGroup(t, db, "SRE", admin.ID)
+	db.Model(&sre).Update("namespace", "sre")
+	db.Create(&models.GroupMembership{UserID: member.ID, GroupID: sre.ID, Role: models.GroupRoleMember})
+	other := createTestGroup(t, db, "Other", admin.ID)
+
+	link := createLinkViaAPI(t, router, admin, sre.ID, CreateLinkRequest{URL: "https://example.com/runbook", Slug: "sre/runbook"})
+	if link.Slug != "sre/runbook" {
+		t.Errorf("Expected slug 'sre/runbook', got %q", link.Slug)
+	}
+
+	tests := []struct {
+		name    string
+		user    models.User
+		groupID uint
+		slug    string
+	}{
+		{"member who isn't an admin", member, sre.ID, "sre/oncall"},
+		{"link in another group", admin, other.ID, "sre/oncall"},
+		{"unknown namespace", admin, sre.ID, "ops/oncall"},
+		{"nested namespace", admin, sre.ID, "sre/a/b"},
+	}
+	for _, tt := range tests {
+		jsonBody, _ := json.Marshal(CreateLinkRequest{URL: "https://example.com", Slug: tt.slug})
+		req, _ := http.NewRequest("POST", "/api/groups/"+strconv.FormatUint(uint64(tt.groupID), 10)+"/links", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(tt.user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", tt.name, resp.Code)
+		}
+	}
+
+	// Members can still use top-level slugs
+	createLinkViaAPI(t, router, member, sre.ID, CreateLinkRequest{URL: "https://example.com/oncall", Slug: "oncall"})
+
+	// Namespaced links are addressed in the API with an escaped slash
+	req, _ := http.NewRequest("GET", "/api/links/sre%2Frunbook", nil)
+	req.Header.Set("Authorization", getAuthHeader(member))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestProtectedSlugChangeRequests(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	author := createTestUser(t, db, "author@example.com")
+	approver := createTestUser(t, db, "approver@example.com")
+	group := createTestOrgGroup(t, db, "Security", 1, author.ID)
+	db.Create(&models.GroupMembership{UserID: approver.ID, GroupID: group.ID, Role: models.GroupRoleMember})
+	db.Create(&models.ProtectedSlug{OrganizationID: 1, Pattern: "security*", CreatedByID: approver.ID, Approvers: []models.User{approver}})
+
+	do := func(user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	groupPath := "/api/groups/" + strconv.FormatUint(uint64(group.ID), 10) + "/links"
+
+	// Creating a protected link needs approval
+	resp := do(author, "POST", groupPath, CreateLinkRequest{URL: "https://example.com/report", Slug: "Security-Report"})
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created ChangeRequestResponse
+	json.Unmarshal(resp.Body.Bytes(), &created)
+	if created.Status != "pending" || created.Action != models.ChangeActionCreate {
+		t.Errorf("Expected a pending create request, got %+v", created)
+	}
+	var count int64
+	db.Model(&models.Link{}).Where("slug = ?", "Security-Report").Count(&count)
+	if count != 0 {
+		t.Error("Expected the link not to be created before approval")
+	}
+
+	// The requester can't approve their own change
+	crPath := "/api/change-requests/" + strconv.FormatUint(uint64(created.ID), 10)
+	if resp := do(author, "POST", crPath+"/approve", nil); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+
+	// Approvers see the request in their queue
+	resp = do(approver, "GET", "/api/change-requests", nil)
+	var queue []ChangeRequestResponse
+	json.Unmarshal(resp.Body.Bytes(), &queue)
+	if len(queue) != 1 || queue[0].ID != created.ID {
+		t.Fatalf("Expected the request in the approver's queue, got %+v", queue)
+	}
+
+	resp = do(approver, "POST", crPath+"/approve", ReviewChangeRequest{Comment: "LGTM"})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var approved ChangeRequestResponse
+	json.Unmarshal(resp.Body.Bytes(), &approved)
+	if approved.Status != "approved" || approved.ReviewComment != "LGTM" || approved.ReviewedByID == nil || *approved.ReviewedByID != approver.ID {
+		t.Errorf("Expected the decision to be recorded, got %+v", approved)
+	}
+	if approved.Link == nil || approved.Link.Slug != "Security-Report" {
+		t.Fatalf("Expected the link to be created, got %+v", approved.Link)
+	}
+	var createdLink models.Link
+	db.First(&createdLink, approved.Link.ID)
+	if createdLink.CreatedByID != author.ID {
+		t.Errorf("Expected the link to be created for the requester, got creator %d", createdLink.CreatedByID)
+	}
+
+	// Requests can only be reviewed once
+	if resp := do(approver, "POST", crPath+"/reject", nil); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", resp.Code)
+	}
+
+	// Updating the protected link needs approval too, and rejected changes aren't applied
+	resp = do(author, "PUT", "/api/links/Security-Report", UpdateLinkRequest{URL: "https://evil.example.com"})
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var update ChangeRequestResponse
+	json.Unmarshal(resp.Body.Bytes(), &update)
+	resp = do(approver, "POST", "/api/change-requests/"+strconv.FormatUint(uint64(update.ID), 10)+"/reject", ReviewChangeRequest{Comment: "Wrong URL"})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var link models.Link
+	db.Where("slug = ?", "Security-Report").First(&link)
+	if link.URL != "https://example.com/report" {
+		t.Errorf("Expected the rejected change not to be applied, got %q", link.URL)
+	}
+
+	// Renaming an unprotected link to a protected slug needs approval
+	plain := createLinkViaAPI(t, router, author, group.ID, CreateLinkRequest{URL: "https://example.com/plain", Slug: "plain"})
+	if resp := do(author, "PUT", "/api/links/"+plain.Slug, UpdateLinkRequest{Slug: "security-faq"}); resp.Code != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", resp.Code)
+	}
+
+	// Approvers change protected links directly
+	if resp := do(approver, "PUT", "/api/links/Security-Report", UpdateLinkRequest{Title: "Report"}); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	// Generated slugs pass over protected ones
+	db.Create(&models.Organization{ID: 1, Name: "Acme", Slug: "acme", SlugStrategy: "title"})
+	db.Create(&models.ProtectedSlug{OrganizationID: 1, Pattern: "status", CreatedByID: approver.ID, Approvers: []models.User{approver}})
+	resp = do(author, "POST", groupPath, CreateLinkRequest{URL: "https://example.com/status", Title: "Status"})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var generated LinkResponse
+	json.Unmarshal(resp.Body.Bytes(), &generated)
+	if generated.Slug != "status-2" {
+		t.Errorf("Expected status-2, got %q", generated.Slug)
+	}
+	if resp := do(author, "POST", groupPath, CreateLinkRequest{URL: "https://example.com/policy", Title: "Security Policy"}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 when every generated slug is protected, got %d", resp.Code)
+	}
+
+	// Requests are applied only while the requester's role still allows them
+	resp = do(author, "DELETE", "/api/links/Security-Report", nil)
+	var deletion ChangeRequestResponse
+	json.Unmarshal(resp.Body.Bytes(), &deletion)
+	db.Model(&models.GroupMembership{}).Where("user_id = ? AND group_id = ?", author.ID, group.ID).Update("role", models.GroupRoleViewer)
+	deletionPath := "/api/change-requests/" + strconv.FormatUint(uint64(deletion.ID), 10)
+	if resp := do(approver, "POST", deletionPath+"/approve", nil); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 once the requester is a viewer, got %d", resp.Code)
+	}
+	var pending models.LinkChangeRequest
+	db.First(&pending, deletion.ID)
+	if pending.Status != models.ChangeRequestPending {
+		t.Errorf("Expected the request to stay pending, got %s", pending.Status)
+	}
+	if err := db.Where("slug = ?", "Security-Report").First(&models.Link{}).Error; err != nil {
+		t.Error("Expected the link not to be deleted")
+	}
+}
+
+func TestProtectedSlugOtherChanges(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	author := createTestUser(t, db, "author@example.com")
+	approver := createTestUser(t, db, "approver@example.com")
+	group := createTestOrgGroup(t, db, "Security", 1, author.ID)
+	other := createTestOrgGroup(t, db, "Archive", 1, author.ID)
+	db.Create(&models.GroupMembership{UserID: approver.ID, GroupID: group.ID, Role: models.GroupRoleMember})
+	db.Create(&models.ProtectedSlug{OrganizationID: 1, Pattern: "security*", CreatedByID: approver.ID, Approvers: []models.User{approver}})
+	protected := models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: author.ID, Slug: "security", URL: "https://example.com/security"}
+	db.Create(&protected)
+	dup := models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: author.ID, Slug: "security-old", URL: "https://example.com/security", CanonicalHash: "security"}
+	db.Create(&dup)
+	target := models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: author.ID, Slug: "sec-target", URL: "https://example.com/security", CanonicalHash: "security"}
+	db.Create(&target)
+
+	do := func(user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Bulk changes leave protected links alone
+	on := true
+	_, bulk := doBulk(t, router, author, BulkRequest{Slugs: []string{"security"}, Action: BulkActionSetPublic, Value: &on})
+	if bulk.Failed != 1 || bulk.Results[0].Status != BulkStatusError {
+		t.Errorf("Expected the protected link to fail in bulk, got %+v", bulk)
+	}
+
+	// So do transfers and merges
+	if resp := do(author, "POST", "/api/links/security/transfer", TransferRequest{TargetGroupID: other.ID}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 moving, got %d", resp.Code)
+	}
+	if resp := do(author, "POST", "/api/duplicates/merge", MergeDuplicatesRequest{TargetID: target.ID, LinkIDs: []uint{dup.ID}}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 merging, got %d", resp.Code)
+	}
+	var unchanged models.Link
+	db.First(&unchanged, protected.ID)
+	if unchanged.IsPublic || unchanged.GroupID != group.ID {
+		t.Errorf("Expected the protected link to be unchanged, got %+v", unchanged)
+	}
+
+	// Deleting needs approval
+	resp := do(author, "DELETE", "/api/links/security", nil)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var cr ChangeRequestResponse
+	json.Unmarshal(resp.Body.Bytes(), &cr)
+	if cr.Action != models.ChangeActionDelete {
+		t.Errorf("Expected a delete request, got %+v", cr)
+	}
+	if err := db.First(&models.Link{}, protected.ID).Error; err != nil {
+		t.Fatal("Expected the link to stay until the delete is approved")
+	}
+	resp = do(approver, "POST", "/api/change-requests/"+strconv.FormatUint(uint64(cr.ID), 10)+"/approve", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if err := db.First(&models.Link{}, protected.ID).Error; err == nil {
+		t.Error("Expected the link to be deleted once approved")
+	}
+}
+
+func TestLinkGrants(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	owner := createTestUser(t, db, "owner@example.com")
+	colleague := createTestUser(t, db, "colleague@example.com")
+	teammate := createTestUser(t, db, "teammate@example.com")
+	outsider := createTestUser(t, db, "outsider@example.com")
+	group := createTestOrgGroup(t, db, "Platform", 1, owner.ID)
+	other := createTestOrgGroup(t, db, "Support", 1, teammate.ID)
+	for _, u := range []models.User{owner, colleague, teammate} {
+		db.Create(&models.OrganizationMembership{OrganizationID: 1, UserID: u.ID})
+	}
+	db.Create(&models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: owner.ID, Slug: "runbook", URL: "https://example.com/runbook", Title: "Runbook"})
+
+	do := func(user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Users outside the organization can't be granted access
+	if resp := do(owner, "POST", "/api/links/runbook/grants", CreateGrantRequest{Email: "outsider@example.com", Role: "viewer"}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.Code)
+	}
+	// Only the owning group manages grants
+	if resp := do(colleague, "POST", "/api/links/runbook/grants", CreateGrantRequest{UserID: colleague.ID, Role: "editor"}); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+
+	resp := do(owner, "POST", "/api/links/runbook/grants", CreateGrantRequest{Email: "colleague@example.com", Role: "viewer"})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var grant GrantResponse
+	json.Unmarshal(resp.Body.Bytes(), &grant)
+	if grant.UserID == nil || *grant.UserID != colleague.ID || grant.Role != "viewer" {
+		t.Errorf("Expected a viewer grant for the colleague, got %+v", grant)
+	}
+
+	// Viewers can read and find the link but not change it
+	if resp := do(colleague, "GET", "/api/links/runbook", nil); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.Code)
+	}
+	var found []LinkResponse
+	json.Unmarshal(do(colleague, "GET", "/api/links?q=Runbook", nil).Body.Bytes(), &found)
+	if len(found) != 1 {
+		t.Errorf("Expected the shared link in search, got %d", len(found))
+	}
+	if resp := do(colleague, "PUT", "/api/links/runbook", UpdateLinkRequest{Title: "Mine"}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+
+	// Granting again changes the role
+	resp = do(owner, "POST", "/api/links/runbook/grants", CreateGrantRequest{UserID: colleague.ID, Role: "editor"})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp := do(colleague, "PUT", "/api/links/runbook", UpdateLinkRequest{Title: "Updated runbook"}); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	// Groups can be granted access too, but not the owning group
+	if resp := do(owner, "POST", "/api/links/runbook/grants", CreateGrantRequest{GroupID: group.ID, Role: "viewer"}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.Code)
+	}
+	if resp := do(teammate, "GET", "/api/links/runbook", nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 before sharing, got %d", resp.Code)
+	}
+	if resp := do(owner, "POST", "/api/links/runbook/grants", CreateGrantRequest{GroupID: other.ID, Role: "viewer"}); resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp := do(teammate, "GET", "/api/links/runbook", nil); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 through the group grant, got %d", resp.Code)
+	}
+
+	var grants []GrantResponse
+	json.Unmarshal(do(owner, "GET", "/api/links/runbook/grants", nil).Body.Bytes(), &grants)
+	if len(grants) != 2 || grants[0].UserEmail != "colleague@example.com" || grants[1].GroupName != "Support" {
+		t.Fatalf("Expected the user and group grants, got %+v", grants)
+	}
+
+	// Revoking removes access
+	if resp := do(owner, "DELETE", "/api/links/runbook/grants/"+strconv.FormatUint(uint64(grants[0].ID), 10), nil); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.Code)
+	}
+	if resp := do(colleague, "GET", "/api/links/runbook", nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after revoking, got %d", resp.Code)
+	}
+	if resp := do(outsider, "GET", "/api/links/runbook", nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an outsider, got %d", resp.Code)
+	}
+}
+
+func TestPageLinkRevisions(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	author := createTestUser(t, db, "author@example.com")
+	editor := createTestUser(t, db, "editor@example.com")
+	group := createTestOrgGroup(t, db, "On-call", 1, author.ID)
+	db.Create(&models.GroupMembership{UserID: editor.ID, GroupID: group.ID, Role: models.GroupRoleMember})
+
+	do := func(user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	groupPath := "/api/groups/" + strconv.FormatUint(uint64(group.ID), 10) + "/links"
+
+	// Pages need content and can't have a URL
+	if resp := do(author, "POST", groupPath, CreateLinkRequest{Kind: "page", Slug: "oncall"}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without content, got %d", resp.Code)
+	}
+	if resp := do(author, "POST", groupPath, CreateLinkRequest{Kind: "page", Slug: "oncall", URL: "https://example.com", Content: "Hi"}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 with a URL, got %d", resp.Code)
+	}
+	if resp := do(author, "POST", groupPath, CreateLinkRequest{Slug: "oncall"}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a redirect without a URL, got %d", resp.Code)
+	}
+
+	resp := do(author, "POST", groupPath, CreateLinkRequest{Kind: "page", Slug: "oncall", Title: "On-call", Content: "Page the **primary**."})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var page LinkResponse
+	json.Unmarshal(resp.Body.Bytes(), &page)
+	if page.Kind != "page" || page.Content != "Page the **primary**." || page.URL != "" {
+		t.Errorf("Expected a page, got %+v", page)
+	}
+
+	if resp := do(editor, "PUT", "/api/links/oncall", UpdateLinkRequest{URL: "https://example.com"}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 setting a URL on a page, got %d", resp.Code)
+	}
+	content := "Page the **secondary**."
+	if resp := do(editor, "PUT", "/api/links/oncall", UpdateLinkRequest{Content: &content}); resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	// Changes that don't touch the title or content aren't revisions
+	public := true
+	do(editor, "PUT", "/api/links/oncall", UpdateLinkRequest{IsPublic: &public})
+
+	var revisions []RevisionResponse
+	json.Unmarshal(do(author, "GET", "/api/links/oncall/revisions", nil).Body.Bytes(), &revisions)
+	if len(revisions) != 2 || revisions[0].Revision != 2 || revisions[0].EditedByID != editor.ID || revisions[1].EditedByID != author.ID {
+		t.Fatalf("Expected 2 revisions, newest first, got %+v", revisions)
+	}
+
+	var first RevisionResponse
+	json.Unmarshal(do(author, "GET", "/api/links/oncall/revisions/1", nil).Body.Bytes(), &first)
+	if first.Content != "Page the **primary**." {
+		t.Errorf("Expected the original content, got %q", first.Content)
+	}
+
+	// Restoring saves a new revision with the old content
+	resp = do(author, "POST", "/api/links/oncall/revisions/1/restore", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	json.Unmarshal(resp.Body.Bytes(), &page)
+	if page.Content != "Page the **primary**." {
+		t.Errorf("Expected the content to be restored, got %q", page.Content)
+	}
+	json.Unmarshal(do(author, "GET", "/api/links/oncall/revisions", nil).Body.Bytes(), &revisions)
+	if len(revisions) != 3 || revisions[0].Revision != 3 {
+		t.Errorf("Expected a third revision, got %+v", revisions)
+	}
+
+	// Redirects have no revisions, and their content can't be set
+	createLinkViaAPI(t, router, author, group.ID, CreateLinkRequest{URL: "https://example.com/docs", Slug: "docs", Title: "Docs"})
+	if resp := do(author, "GET", "/api/links/docs/revisions", nil); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.Code)
+	}
+	if resp := do(author, "PUT", "/api/links/docs", UpdateLinkRequest{Content: &content}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.Code)
+	}
+
+	// Search covers page content
+	var found []LinkResponse
+	json.Unmarshal(do(author, "GET", "/api/links?q=primary", nil).Body.Bytes(), &found)
+	if len(found) != 1 || found[0].Slug != "oncall" {
+		t.Errorf("Expected the page in search results, got %+v", found)
+	}
+}
+
+func TestLinkHistory(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	outsider := createTestUser(t, db, "outsider@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+	link := models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: "runbook", URL: "https://example.com"}
+	db.Create(&link)
+	history.Record(db, []uint{link.ID}, user.ID, models.LinkEventTagRenamed, `Tag "k8s" renamed to "kubernetes"`)
+	history.Record(db, []uint{link.ID}, user.ID, models.LinkEventTagDeleted, `Tag "kubernetes" deleted`)
+
+	req, _ := http.NewRequest("GET", "/api/links/runbook/history", nil)
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	var events []LinkEventResponse
+	json.Unmarshal(resp.Body.Bytes(), &events)
+	if len(events) != 2 || events[0].Action != models.LinkEventTagDeleted || events[1].ActorName != "Test User" {
+		t.Errorf("Expected 2 events, newest first, got %+v", events)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/links/runbook/history", nil)
+	req.Header.Set("Authorization", getAuthHeader(outsider))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+}
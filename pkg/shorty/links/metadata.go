@@ -0,0 +1,140 @@
+package links
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/metadata"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+)
+
+// RefreshMetadataRequest represents a request to re-fetch a link's page metadata
+type RefreshMetadataRequest struct {
+	Overwrite bool `json:"overwrite"` // Replace an existing title and description
+}
+
+// fetchLinkMetadata fetches the destination page for a link and stores its metadata.
+// The title and description are only filled in when empty unless overwrite is set.
+// The fetch attempt and status are always recorded.
+func (h *Handler) fetchLinkMetadata(linkID uint, overwrite bool) (*models.Link, error) {
+	var link models.Link
+	if err := h.db.First(&link, linkID).Error; err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), metadata.DefaultTimeout)
+	defer cancel()
+
+	meta, fetchErr := h.fetcher.Fetch(ctx, link.URL)
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"fetched_at":   &now,
+		"fetch_status": 0,
+	}
+	if meta != nil {
+		updates["fetch_status"] = meta.StatusCode
+	}
+	if fetchErr == nil && meta.Title != "" && (overwrite || link.Title == "") {
+		updates["title"] = meta.Title
+	}
+	if fetchErr == nil && meta.Description != "" && (overwrite || link.Description == "") {
+		updates["description"] = meta.Description
+	}
+	if fetchErr == nil && meta.StatusCode >= 200 && meta.StatusCode <= 299 {
+		updates["image_url"] = meta.ImageURL
+		updates["favicon_url"] = meta.FaviconURL
+	}
+
+	if err := h.db.Model(&link).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.First(&link, linkID).Error; err != nil {
+		return nil, err
+	}
+	return &link, fetchErr
+}
+
+// fetchLinkMetadataAsync fetches metadata for a newly created link in the background
+func (h *Handler) fetchLinkMetadataAsync(linkID uint) {
+	h.fetchAsync(func() {
+		if _, err := h.fetchLinkMetadata(linkID, false); err != nil {
+			log.Printf("Failed to fetch metadata for link %d: %v", linkID, err)
+		}
+	})
+}
+
+// RefreshMetadata re-fetches the destination page metadata for a link
+// @Summary Refresh link metadata
+// @Description Fetch the link's destination page and update its title, description, preview image and favicon. The title and description are only replaced when empty unless overwrite is set.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Param request body RefreshMetadataRequest false "Refresh options"
+// @Success 200 {object} LinkResponse
+// @Failure 403 {object} map[string]string "Overwriting a protected link"
+// @Failure 404 {object} map[string]string "Link not found"
+// @Failure 502 {object} map[string]string "Failed to fetch metadata"
+// @Security BearerAuth
+// @Router /links/{slug}/metadata [post]
+func (h *Handler) RefreshMetadata(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	slug := c.Param("slug")
+
+	var link models.Link
+	if err := h.db.Where("slug = ?", slug).First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+
+	// Check access
+	if !access.CanEdit(h.db, userID, &link) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+	if link.IsPage() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pages have no URL to fetch"})
+		return
+	}
+
+	// The body is optional
+	var req RefreshMetadataRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Replacing the title and description of a protected link needs approval,
+	// so only its approvers can do it here
+	if req.Overwrite {
+		rule, err := h.protectionFor(userID, link.OrganizationID, link.Slug)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check protected slugs"})
+			return
+		}
+		if rule != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "This link is protected; update its title and description to request approval"})
+			return
+		}
+	}
+
+	updated, err := h.fetchLinkMetadata(link.ID, req.Overwrite)
+	if updated == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update link"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch metadata: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, linkToResponse(*updated))
+}
@@ -0,0 +1,194 @@
+package links
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// RevisionResponse represents a page revision in API responses. Content is
+// left out when listing revisions.
+type RevisionResponse struct {
+	ID           uint   `json:"id"`
+	Revision     uint   `json:"revision"`
+	Title        string `json:"title"`
+	Content      string `json:"content,omitempty"`
+	EditedByID   uint   `json:"edited_by_id"`
+	EditedByName string `json:"edited_by_name"`
+	CreatedAt    string `json:"created_at"`
+}
+
+func revisionToResponse(rev models.LinkRevision, withContent bool) RevisionResponse {
+	response := RevisionResponse{
+		ID:           rev.ID,
+		Revision:     rev.Revision,
+		Title:        rev.Title,
+		EditedByID:   rev.EditedByID,
+		EditedByName: rev.EditedBy.Name,
+		CreatedAt:    rev.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if withContent {
+		response.Content = rev.Content
+	}
+	return response
+}
+
+// recordRevision saves the page's current title and content as its next revision
+func (h *Handler) recordRevision(link *models.Link, editorID uint) error {
+	return saveRevision(h.db, link, editorID)
+}
+
+// saveRevision is recordRevision for callers that are inside a transaction
+func saveRevision(db *gorm.DB, link *models.Link, editorID uint) error {
+	var latest uint
+	db.Model(&models.LinkRevision{}).Where("link_id = ?", link.ID).Select("COALESCE(MAX(revision), 0)").Scan(&latest)
+
+	return db.Create(&models.LinkRevision{
+		LinkID:     link.ID,
+		Revision:   latest + 1,
+		Title:      link.Title,
+		Content:    link.Content,
+		EditedByID: editorID,
+	}).Error
+}
+
+// findViewablePage looks up a page by slug that the user can see
+func (h *Handler) findViewablePage(c *gin.Context, userID uint) (*models.Link, bool) {
+	var link models.Link
+	if err := h.db.Where("slug = ?", c.Param("slug")).First(&link).Error; err != nil || !access.CanView(h.db, userID, &link) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return nil, false
+	}
+	if !link.IsPage() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only pages have revisions"})
+		return nil, false
+	}
+	return &link, true
+}
+
+// findRevision looks up one of a page's revisions by number
+func (h *Handler) findRevision(c *gin.Context, link *models.Link) (*models.LinkRevision, bool) {
+	var rev models.LinkRevision
+	if err := h.db.Preload("EditedBy").Where("link_id = ? AND revision = ?", link.ID, c.Param("revision")).First(&rev).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return nil, false
+	}
+	return &rev, true
+}
+
+// ListRevisions returns the history of a page
+// @Summary List page revisions
+// @Description Get the revision history of a page link, newest first
+// @Tags links
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Success 200 {array} RevisionResponse
+// @Failure 400 {object} map[string]string "Not a page"
+// @Failure 404 {object} map[string]string "Link not found"
+// @Security BearerAuth
+// @Router /links/{slug}/revisions [get]
+func (h *Handler) ListRevisions(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findViewablePage(c, userID)
+	if !ok {
+		return
+	}
+
+	var revisions []models.LinkRevision
+	if err := h.db.Preload("EditedBy").Where("link_id = ?", link.ID).Order("revision DESC").Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+
+	responses := make([]RevisionResponse, len(revisions))
+	for i, rev := range revisions {
+		responses[i] = revisionToResponse(rev, false)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetRevision returns one revision of a page
+// @Summary Get a page revision
+// @Description Get the title and content of a page as of a revision
+// @Tags links
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} RevisionResponse
+// @Failure 400 {object} map[string]string "Not a page"
+// @Failure 404 {object} map[string]string "Revision not found"
+// @Security BearerAuth
+// @Router /links/{slug}/revisions/{revision} [get]
+func (h *Handler) GetRevision(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findViewablePage(c, userID)
+	if !ok {
+		return
+	}
+
+	rev, ok := h.findRevision(c, link)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, revisionToResponse(*rev, true))
+}
+
+// RestoreRevision puts an earlier revision of a page back
+// @Summary Restore a page revision
+// @Description Restore a page's title and content from an earlier revision. The restore is saved as a new revision. Pages with a protected slug return a change request for approval instead.
+// @Tags links
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} LinkResponse
+// @Success 202 {object} ChangeRequestResponse "Awaiting approval"
+// @Failure 400 {object} map[string]string "Not a page"
+// @Failure 403 {object} map[string]string "Read-only access to the link"
+// @Failure 404 {object} map[string]string "Revision not found"
+// @Security BearerAuth
+// @Router /links/{slug}/revisions/{revision}/restore [post]
+func (h *Handler) RestoreRevision(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findViewablePage(c, userID)
+	if !ok {
+		return
+	}
+	if !access.CanEdit(h.db, userID, link) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You have read-only access to this link"})
+		return
+	}
+
+	rev, ok := h.findRevision(c, link)
+	if !ok {
+		return
+	}
+
+	req := UpdateLinkRequest{Title: rev.Title, Content: &rev.Content}
+
+	// Protected slugs only change with approval
+	rule, err := h.protectionFor(userID, link.OrganizationID, link.Slug)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check protected slugs"})
+		return
+	}
+	if rule != nil {
+		h.requestChange(c, userID, rule, models.ChangeActionUpdate, link.GroupID, &link.ID, link.Slug, req)
+		return
+	}
+
+	if err := h.updateLink(link, req, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore revision"})
+		return
+	}
+
+	c.JSON(http.StatusOK, linkToResponse(*link))
+}
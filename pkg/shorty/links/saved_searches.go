@@ -0,0 +1,425 @@
+package links
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+)
+
+// CreateSavedSearchRequest represents the request to save a search
+type CreateSavedSearchRequest struct {
+	Name     string       `json:"name" binding:"required,min=1,max=100"`
+	GroupID  *uint        `json:"group_id"` // Share with this group's members
+	Params   SearchParams `json:"params"`
+	IsPinned bool         `json:"is_pinned"` // Show on the group's dashboard (requires group_id)
+}
+
+// UpdateSavedSearchRequest represents the request to update a saved search.
+// Omitted fields are left unchanged; a group_id of 0 makes the search personal again.
+type UpdateSavedSearchRequest struct {
+	Name     string        `json:"name" binding:"omitempty,min=1,max=100"`
+	GroupID  *uint         `json:"group_id"`
+	Params   *SearchParams `json:"params"`
+	IsPinned *bool         `json:"is_pinned"`
+}
+
+// SavedSearchResponse represents a saved search in API responses
+type SavedSearchResponse struct {
+	ID        uint         `json:"id"`
+	Name      string       `json:"name"`
+	UserID    uint         `json:"user_id"`
+	GroupID   *uint        `json:"group_id,omitempty"`
+	Params    SearchParams `json:"params"`
+	IsPinned  bool         `json:"is_pinned"`
+	CanEdit   bool         `json:"can_edit"`
+	CreatedAt string       `json:"created_at"`
+	UpdatedAt string       `json:"updated_at"`
+}
+
+// PinnedSearchResponse represents a saved search pinned to a group's dashboard
+type PinnedSearchResponse struct {
+	SavedSearchResponse
+	LinkCount int64 `json:"link_count"`
+}
+
+func (h *Handler) savedSearchToResponse(userID uint, search models.SavedSearch) SavedSearchResponse {
+	var params SearchParams
+	json.Unmarshal([]byte(search.Params), &params)
+
+	return SavedSearchResponse{
+		ID:        search.ID,
+		Name:      search.Name,
+		UserID:    search.UserID,
+		GroupID:   search.GroupID,
+		Params:    params,
+		IsPinned:  search.IsPinned,
+		CanEdit:   h.canEditSavedSearch(userID, &search),
+		CreatedAt: search.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: search.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// canEditSavedSearch reports whether the user owns the search or administers the group it's shared with
+func (h *Handler) canEditSavedSearch(userID uint, search *models.SavedSearch) bool {
+	if search.UserID == userID {
+		return true
+	}
+	if search.GroupID == nil {
+		return false
+	}
+	return access.IsGroupAdmin(h.db, userID, *search.GroupID)
+}
+
+// authorizeSharing checks the user can share a search with a group, which
+// needs a role that can edit the group's links, and pin it to the group's
+// dashboard, which only group admins can do. It writes the error response.
+func (h *Handler) authorizeSharing(c *gin.Context, userID, groupID uint, pinned bool) bool {
+	action := access.ActionEdit
+	if pinned {
+		action = access.ActionManage
+	}
+	switch access.Authorize(h.db, userID, groupID, action) {
+	case nil:
+		return true
+	case access.ErrForbidden:
+		if pinned {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only group admins can pin searches to the group's dashboard"})
+		} else {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your role in this group doesn't allow sharing searches with it"})
+		}
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+	}
+	return false
+}
+
+// findSavedSearch loads a saved search the user can see: their own, or one shared with a group they belong to
+func (h *Handler) findSavedSearch(c *gin.Context, userID uint) (*models.SavedSearch, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search ID"})
+		return nil, false
+	}
+
+	var search models.SavedSearch
+	if err := h.db.First(&search, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+		return nil, false
+	}
+
+	if search.UserID != userID {
+		if search.GroupID == nil || access.Authorize(h.db, userID, *search.GroupID, access.ActionView) != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+			return nil, false
+		}
+	}
+
+	return &search, true
+}
+
+// ListSavedSearches returns the user's saved searches and those shared with their groups
+// @Summary List saved searches
+// @Description Get the current user's saved searches and searches shared with their groups
+// @Tags saved-searches
+// @Produce json
+// @Param group_id query int false "Only searches shared with this group"
+// @Success 200 {array} SavedSearchResponse
+// @Security BearerAuth
+// @Router /saved-searches [get]
+func (h *Handler) ListSavedSearches(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	groupIDs, err := h.getUserGroupIDs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
+		return
+	}
+
+	query := h.db.Order("name, id")
+	if len(groupIDs) > 0 {
+		query = query.Where("user_id = ? OR group_id IN ?", userID, groupIDs)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+	if groupID, err := strconv.ParseUint(c.Query("group_id"), 10, 32); err == nil {
+		query = query.Where("group_id = ?", groupID)
+	}
+
+	var searches []models.SavedSearch
+	if err := query.Find(&searches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch saved searches"})
+		return
+	}
+
+	responses := make([]SavedSearchResponse, len(searches))
+	for i, search := range searches {
+		responses[i] = h.savedSearchToResponse(userID, search)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// CreateSavedSearch saves a search
+// @Summary Create a saved search
+// @Description Save a named search. Set group_id to share it with a group's members, which needs a role that can edit the group's links, and is_pinned to show it on the group's dashboard, which needs admin access to the group.
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param request body CreateSavedSearchRequest true "Saved search"
+// @Success 201 {object} SavedSearchResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Role doesn't allow sharing or pinning in the group"
+// @Failure 404 {object} map[string]string "Group not found"
+// @Security BearerAuth
+// @Router /saved-searches [post]
+func (h *Handler) CreateSavedSearch(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	var req CreateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := req.Params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.GroupID != nil && *req.GroupID == 0 {
+		req.GroupID = nil
+	}
+	if req.IsPinned && req.GroupID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only searches shared with a group can be pinned"})
+		return
+	}
+	if req.GroupID != nil && !h.authorizeSharing(c, userID, *req.GroupID, req.IsPinned) {
+		return
+	}
+
+	params, _ := json.Marshal(req.Params)
+	search := models.SavedSearch{
+		UserID:   userID,
+		GroupID:  req.GroupID,
+		Name:     strings.TrimSpace(req.Name),
+		Params:   string(params),
+		IsPinned: req.IsPinned,
+	}
+
+	if err := h.db.Create(&search).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create saved search"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.savedSearchToResponse(userID, search))
+}
+
+// GetSavedSearch returns a saved search
+// @Summary Get a saved search
+// @Description Get a saved search owned by the user or shared with one of their groups
+// @Tags saved-searches
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Success 200 {object} SavedSearchResponse
+// @Failure 404 {object} map[string]string "Saved search not found"
+// @Security BearerAuth
+// @Router /saved-searches/{id} [get]
+func (h *Handler) GetSavedSearch(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	search, ok := h.findSavedSearch(c, userID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, h.savedSearchToResponse(userID, *search))
+}
+
+// UpdateSavedSearch updates a saved search (owner or group admin)
+// @Summary Update a saved search
+// @Description Update a saved search. The owner or an admin of the group it is shared with can update it. Sharing it with a group needs a role that can edit the group's links, and pinning it needs admin access to the group.
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Param request body UpdateSavedSearchRequest true "Updated saved search"
+// @Success 200 {object} SavedSearchResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Not allowed to edit this saved search, or to share or pin it in the group"
+// @Failure 404 {object} map[string]string "Saved search not found"
+// @Security BearerAuth
+// @Router /saved-searches/{id} [put]
+func (h *Handler) UpdateSavedSearch(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	search, ok := h.findSavedSearch(c, userID)
+	if !ok {
+		return
+	}
+
+	if !h.canEditSavedSearch(userID, search) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to edit this saved search"})
+		return
+	}
+
+	var req UpdateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Name != "" {
+		search.Name = strings.TrimSpace(req.Name)
+	}
+	if req.GroupID != nil {
+		if *req.GroupID == 0 {
+			search.GroupID = nil
+			search.IsPinned = false
+		} else {
+			search.GroupID = req.GroupID
+		}
+	}
+	if req.Params != nil {
+		if err := req.Params.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		params, _ := json.Marshal(req.Params)
+		search.Params = string(params)
+	}
+	if req.IsPinned != nil {
+		if *req.IsPinned && search.GroupID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only searches shared with a group can be pinned"})
+			return
+		}
+		search.IsPinned = *req.IsPinned
+	}
+	// Sharing with a group, or pinning to it, is checked against the group
+	// the search ends up in
+	if search.GroupID != nil && (req.GroupID != nil || (req.IsPinned != nil && *req.IsPinned)) {
+		if !h.authorizeSharing(c, userID, *search.GroupID, search.IsPinned) {
+			return
+		}
+	}
+
+	if err := h.db.Save(search).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.savedSearchToResponse(userID, *search))
+}
+
+// DeleteSavedSearch deletes a saved search (owner or group admin)
+// @Summary Delete a saved search
+// @Description Delete a saved search. The owner or an admin of the group it is shared with can delete it.
+// @Tags saved-searches
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Success 200 {object} map[string]string "Saved search deleted"
+// @Failure 403 {object} map[string]string "Not allowed to delete this saved search"
+// @Failure 404 {object} map[string]string "Saved search not found"
+// @Security BearerAuth
+// @Router /saved-searches/{id} [delete]
+func (h *Handler) DeleteSavedSearch(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	search, ok := h.findSavedSearch(c, userID)
+	if !ok {
+		return
+	}
+
+	if !h.canEditSavedSearch(userID, search) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to delete this saved search"})
+		return
+	}
+
+	if err := h.db.Delete(search).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Saved search deleted"})
+}
+
+// RunSavedSearch returns the links matching a saved search
+// @Summary Run a saved search
+// @Description Get the links matching a saved search. Results are limited to links the current user can see, so a shared search may return different links for different members.
+// @Tags saved-searches
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Param limit query int false "Max results (default 50, max 100)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {array} LinkResponse
+// @Failure 404 {object} map[string]string "Saved search not found"
+// @Security BearerAuth
+// @Router /saved-searches/{id}/links [get]
+func (h *Handler) RunSavedSearch(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	search, ok := h.findSavedSearch(c, userID)
+	if !ok {
+		return
+	}
+
+	var params SearchParams
+	json.Unmarshal([]byte(search.Params), &params)
+
+	var links []models.Link
+	if err := paginate(c, h.visibleSearchQuery(userID, params).Order("links.created_at DESC")).Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search links"})
+		return
+	}
+
+	responses := make([]LinkResponse, len(links))
+	for i, link := range links {
+		responses[i] = linkToResponse(link)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// ListPinnedSearches returns the saved searches pinned to a group's dashboard
+// @Summary List a group's pinned searches
+// @Description Get the saved searches pinned to a group's dashboard, with the number of links each currently matches for the caller
+// @Tags saved-searches
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {array} PinnedSearchResponse
+// @Failure 400 {object} map[string]string "Invalid group ID"
+// @Failure 404 {object} map[string]string "Group not found"
+// @Security BearerAuth
+// @Router /groups/{id}/pinned-searches [get]
+func (h *Handler) ListPinnedSearches(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	// Check membership
+	if err := access.Authorize(h.db, userID, uint(groupID), access.ActionView); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	var searches []models.SavedSearch
+	if err := h.db.Where("group_id = ? AND is_pinned = ?", groupID, true).Order("name, id").Find(&searches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pinned searches"})
+		return
+	}
+
+	responses := make([]PinnedSearchResponse, len(searches))
+	for i, search := range searches {
+		responses[i] = PinnedSearchResponse{SavedSearchResponse: h.savedSearchToResponse(userID, search)}
+		h.visibleSearchQuery(userID, responses[i].Params).Distinct("links.id").Count(&responses[i].LinkCount)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
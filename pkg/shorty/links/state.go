@@ -0,0 +1,279 @@
+package links
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// UpdateLinkStateRequest represents a change to the current user's state for a link.
+// Omitted fields are left unchanged.
+type UpdateLinkStateRequest struct {
+	IsFavorite *bool   `json:"is_favorite"`
+	IsPinned   *bool   `json:"is_pinned"`
+	IsUnread   *bool   `json:"is_unread"`
+	Notes      *string `json:"notes" binding:"omitempty,max=10000"`
+}
+
+// LinkStateResponse represents the current user's state for a link
+type LinkStateResponse struct {
+	LinkID     uint   `json:"link_id"`
+	IsFavorite bool   `json:"is_favorite"`
+	IsPinned   bool   `json:"is_pinned"`
+	IsUnread   bool   `json:"is_unread"` // The user's own status, or the group-level default if unset
+	Notes      string `json:"notes"`
+	PinnedAt   string `json:"pinned_at,omitempty"`
+}
+
+// MyLinkResponse represents a link along with the current user's state for it.
+// is_unread is the user's own status rather than the group-level default.
+type MyLinkResponse struct {
+	LinkResponse
+	IsFavorite bool   `json:"is_favorite"`
+	IsPinned   bool   `json:"is_pinned"`
+	IsUnread   bool   `json:"is_unread"`
+	Notes      string `json:"notes,omitempty"`
+}
+
+func stateToResponse(link *models.Link, state *models.UserLinkState) LinkStateResponse {
+	response := LinkStateResponse{
+		LinkID:   link.ID,
+		IsUnread: state.EffectiveUnread(link),
+	}
+	if state != nil {
+		response.IsFavorite = state.IsFavorite
+		response.IsPinned = state.IsPinned
+		response.Notes = state.Notes
+		if state.PinnedAt != nil {
+			response.PinnedAt = state.PinnedAt.Format("2006-01-02T15:04:05Z")
+		}
+	}
+	return response
+}
+
+// findUserLinkState returns the user's state for a link, or nil if they have none
+func (h *Handler) findUserLinkState(userID, linkID uint) (*models.UserLinkState, error) {
+	var state models.UserLinkState
+	err := h.db.Where("user_id = ? AND link_id = ?", userID, linkID).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// findMemberLink looks up a link by slug and checks the user belongs to its
+// group or that it is shared with them
+func (h *Handler) findMemberLink(c *gin.Context, userID uint) (*models.Link, bool) {
+	var link models.Link
+	if err := h.db.Where("slug = ?", c.Param("slug")).First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return nil, false
+	}
+
+	// Check access
+	if access.Role(h.db, userID, &link) == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return nil, false
+	}
+
+	return &link, true
+}
+
+// GetState returns the current user's state for a link
+// @Summary Get my link state
+// @Description Get the current user's favorite, pinned, unread and notes state for a link
+// @Tags links
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Success 200 {object} LinkStateResponse
+// @Failure 404 {object} map[string]string "Link not found"
+// @Security BearerAuth
+// @Router /links/{slug}/state [get]
+func (h *Handler) GetState(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findMemberLink(c, userID)
+	if !ok {
+		return
+	}
+
+	state, err := h.findUserLinkState(userID, link.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch link state"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stateToResponse(link, state))
+}
+
+// UpdateState updates the current user's state for a link
+// @Summary Update my link state
+// @Description Set the current user's favorite, pinned, unread or notes state for a link. This doesn't affect other group members; the link's own is_unread remains the group-level default.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Param request body UpdateLinkStateRequest true "State changes"
+// @Success 200 {object} LinkStateResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 404 {object} map[string]string "Link not found"
+// @Security BearerAuth
+// @Router /links/{slug}/state [put]
+func (h *Handler) UpdateState(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	link, ok := h.findMemberLink(c, userID)
+	if !ok {
+		return
+	}
+
+	var req UpdateLinkStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, err := h.findUserLinkState(userID, link.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch link state"})
+		return
+	}
+	if state == nil {
+		state = &models.UserLinkState{UserID: userID, LinkID: link.ID}
+	}
+
+	if req.IsFavorite != nil {
+		state.IsFavorite = *req.IsFavorite
+	}
+	if req.IsPinned != nil && *req.IsPinned != state.IsPinned {
+		state.IsPinned = *req.IsPinned
+		state.PinnedAt = nil
+		if state.IsPinned {
+			now := time.Now()
+			state.PinnedAt = &now
+		}
+	}
+	if req.IsUnread != nil {
+		state.IsUnread = req.IsUnread
+	}
+	if req.Notes != nil {
+		state.Notes = *req.Notes
+	}
+
+	if err := h.db.Save(state).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update link state"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stateToResponse(link, state))
+}
+
+// myLinksQuery returns links in the user's groups, and links shared with them,
+// joined with the user's state
+func (h *Handler) myLinksQuery(userID uint) *gorm.DB {
+	return h.db.Model(&models.Link{}).
+		Joins("LEFT JOIN user_link_states ON user_link_states.link_id = links.id AND user_link_states.user_id = ?", userID).
+		Scopes(access.Visible(h.db, userID))
+}
+
+// listMyLinks runs a query built by filter against the user's links and writes
+// the results along with the user's state for each link
+func (h *Handler) listMyLinks(c *gin.Context, filter func(*gorm.DB) *gorm.DB) {
+	userID, _ := auth.GetUserID(c)
+
+	var links []models.Link
+	if err := paginate(c, filter(h.myLinksQuery(userID))).Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch links"})
+		return
+	}
+
+	linkIDs := make([]uint, len(links))
+	for i, link := range links {
+		linkIDs[i] = link.ID
+	}
+
+	var states []models.UserLinkState
+	if len(linkIDs) > 0 {
+		if err := h.db.Where("user_id = ? AND link_id IN ?", userID, linkIDs).Find(&states).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch link state"})
+			return
+		}
+	}
+	byLink := make(map[uint]*models.UserLinkState, len(states))
+	for i := range states {
+		byLink[states[i].LinkID] = &states[i]
+	}
+
+	responses := make([]MyLinkResponse, len(links))
+	for i := range links {
+		state := byLink[links[i].ID]
+		responses[i] = MyLinkResponse{
+			LinkResponse: linkToResponse(links[i]),
+			IsUnread:     state.EffectiveUnread(&links[i]),
+		}
+		if state != nil {
+			responses[i].IsFavorite = state.IsFavorite
+			responses[i].IsPinned = state.IsPinned
+			responses[i].Notes = state.Notes
+		}
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// ListPinned returns the current user's pinned links
+// @Summary List my pinned links
+// @Description Get the links the current user has pinned across all their groups, most recently pinned first
+// @Tags links
+// @Produce json
+// @Param limit query int false "Max results (default 50, max 100)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {array} MyLinkResponse
+// @Security BearerAuth
+// @Router /me/links/pinned [get]
+func (h *Handler) ListPinned(c *gin.Context) {
+	h.listMyLinks(c, func(query *gorm.DB) *gorm.DB {
+		return query.Where("user_link_states.is_pinned = ?", true).Order("user_link_states.pinned_at DESC")
+	})
+}
+
+// ListFavorites returns the current user's favorite links
+// @Summary List my favorite links
+// @Description Get the links the current user has marked as favorites across all their groups
+// @Tags links
+// @Produce json
+// @Param limit query int false "Max results (default 50, max 100)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {array} MyLinkResponse
+// @Security BearerAuth
+// @Router /me/links/favorites [get]
+func (h *Handler) ListFavorites(c *gin.Context) {
+	h.listMyLinks(c, func(query *gorm.DB) *gorm.DB {
+		return query.Where("user_link_states.is_favorite = ?", true).Order("links.created_at DESC")
+	})
+}
+
+// ListUnread returns the current user's reading list
+// @Summary List my unread links
+// @Description Get the links that are unread for the current user across all their groups. A link the user hasn't marked read or unread themselves follows its group-level is_unread flag.
+// @Tags links
+// @Produce json
+// @Param limit query int false "Max results (default 50, max 100)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {array} MyLinkResponse
+// @Security BearerAuth
+// @Router /me/links/unread [get]
+func (h *Handler) ListUnread(c *gin.Context) {
+	h.listMyLinks(c, func(query *gorm.DB) *gorm.DB {
+		return query.Where("COALESCE(user_link_states.is_unread, links.is_unread) = ?", true).Order("links.created_at DESC")
+	})
+}
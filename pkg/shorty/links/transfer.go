@@ -0,0 +1,448 @@
+package links
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/slugs"
+	"github.com/mikepea/shorty/pkg/shorty/tags"
+	"github.com/mikepea/shorty/pkg/shorty/urlcanon"
+	"gorm.io/gorm"
+)
+
+// Transfer modes
+const (
+	TransferModeMove = "move"
+	TransferModeCopy = "copy"
+)
+
+// Slug conflict strategies used when the slug is already taken in the target organization
+const (
+	ConflictSkip   = "skip"
+	ConflictRename = "rename"
+	ConflictFail   = "fail"
+)
+
+// Transfer result statuses
+const (
+	TransferStatusMoved   = "moved"
+	TransferStatusCopied  = "copied"
+	TransferStatusSkipped = "skipped"
+)
+
+// TransferRequest represents a request to move or copy links to another group
+type TransferRequest struct {
+	TargetGroupID uint   `json:"target_group_id" binding:"required"`
+	Mode          string `json:"mode" binding:"omitempty,oneof=move copy"`               // Defaults to move
+	OnConflict    string `json:"on_conflict" binding:"omitempty,oneof=skip rename fail"` // Defaults to fail
+}
+
+// TransferResult reports the outcome of moving or copying a single link
+type TransferResult struct {
+	ID      uint   `json:"id"`
+	Slug    string `json:"slug"`
+	NewID   uint   `json:"new_id,omitempty"`
+	NewSlug string `json:"new_slug,omitempty"`
+	Status  string `json:"status"`
+}
+
+// TransferResponse represents the result of a move or copy operation
+type TransferResponse struct {
+	Mode          string           `json:"mode"`
+	TargetGroupID uint             `json:"target_group_id"`
+	Transferred   int              `json:"transferred"`
+	Skipped       int              `json:"skipped"`
+	Results       []TransferResult `json:"results"`
+}
+
+// SlugConflictError is returned when a slug is taken or can't be used in the
+// target organization and the conflict strategy is "fail"
+type SlugConflictError struct {
+	Slug   string
+	Reason string // Why the slug can't be used, when it isn't simply taken
+}
+
+func (e *SlugConflictError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("Slug '%s' can't be used in the target organization: %s", e.Slug, e.Reason)
+	}
+	return fmt.Sprintf("Slug '%s' already exists in the target organization", e.Slug)
+}
+
+// errTransferSkipped signals that a link was left in place because of a slug conflict
+var errTransferSkipped = errors.New("transfer skipped")
+
+// slugTaken is the reason slugProblem gives for a slug another link holds
+const slugTaken = "taken"
+
+// slugProblem returns why a slug can't be used in the target group, or "" if
+// it can. Slugs are checked the same way as when a link is created there.
+func (h *Handler) slugProblem(slug string, excludeID uint, target *models.Group, userID uint) (string, error) {
+	taken, err := slugs.Taken(h.db, target.OrganizationID, slug, excludeID)
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return slugTaken, nil
+	}
+
+	var validationErr *ValidationError
+	if err := h.validateSlugForOrg(slug, excludeID, target.OrganizationID, target.ID, userID); errors.As(err, &validationErr) {
+		return validationErr.Message, nil
+	} else if err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// resolveSlugConflict returns the slug a link should use in the target group.
+// Returns errTransferSkipped or a SlugConflictError depending on the conflict strategy.
+func (h *Handler) resolveSlugConflict(slug string, excludeID uint, target *models.Group, onConflict string, userID uint) (string, error) {
+	problem, err := h.slugProblem(slug, excludeID, target, userID)
+	if err != nil || problem == "" {
+		return slug, err
+	}
+
+	switch onConflict {
+	case ConflictSkip:
+		return "", errTransferSkipped
+	case ConflictRename:
+		for i := 2; i < 100; i++ {
+			suffix := "-" + strconv.Itoa(i)
+			base := slug
+			if len(base)+len(suffix) > slugs.MaxLength {
+				base = base[:slugs.MaxLength-len(suffix)]
+			}
+			candidate := base + suffix
+			candidateProblem, err := h.slugProblem(candidate, excludeID, target, userID)
+			if err != nil {
+				return "", err
+			}
+			if candidateProblem == "" {
+				return candidate, nil
+			}
+		}
+	}
+
+	if problem == slugTaken {
+		return "", &SlugConflictError{Slug: slug}
+	}
+	return "", &SlugConflictError{Slug: slug, Reason: problem}
+}
+
+// transferLink moves or copies a link into the target group, resolving slug conflicts
+// in the target organization according to onConflict
+func (h *Handler) transferLink(link *models.Link, target *models.Group, mode, onConflict string, userID uint) (TransferResult, error) {
+	result := TransferResult{ID: link.ID, Slug: link.Slug}
+
+	if mode == TransferModeMove && link.GroupID == target.ID {
+		result.NewID = link.ID
+		result.NewSlug = link.Slug
+		result.Status = TransferStatusMoved
+		return result, nil
+	}
+
+	// A moved link keeps its own row, so it never conflicts with itself
+	var excludeID uint
+	if mode == TransferModeMove {
+		excludeID = link.ID
+	}
+
+	// A namespaced slug only keeps its namespace if the target group owns it
+	slug := link.Slug
+	if namespace, name := slugs.SplitNamespace(slug); namespace != "" && namespace != target.Namespace {
+		slug = name
+	}
+
+	slug, err := h.resolveSlugConflict(slug, excludeID, target, onConflict, userID)
+	if err == errTransferSkipped {
+		result.Status = TransferStatusSkipped
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+
+	// The link must follow the rules of the group it's going into
+	if link.GroupID != target.ID {
+		arriving := *link
+		arriving.Slug = slug
+		if err := checkGroupPolicy(target, &arriving); err != nil {
+			return result, err
+		}
+	}
+
+	// Tags belong to an organization, so links leaving it take on the
+	// target organization's tags of the same names
+	crossOrg := target.OrganizationID != link.OrganizationID
+	var linkTags []models.Tag
+	if err := h.db.Model(link).Association("Tags").Find(&linkTags); err != nil {
+		return result, err
+	}
+	if crossOrg {
+		var err error
+		if linkTags, err = tags.FindOrCreate(h.db, target.OrganizationID, tags.Names(linkTags)); err != nil {
+			return result, err
+		}
+	}
+
+	if mode == TransferModeCopy {
+		copied := models.Link{
+			OrganizationID: target.OrganizationID,
+			GroupID:        target.ID,
+			CreatedByID:    userID,
+			Slug:           slug,
+			Kind:           link.Kind,
+			URL:            link.URL,
+			Title:          link.Title,
+			Description:    link.Description,
+			Content:        link.Content,
+			ImageURL:       link.ImageURL,
+			FaviconURL:     link.FaviconURL,
+			ExpiresAt:      link.ExpiresAt,
+			Tags:           linkTags,
+		}
+		// Pages have no destination, so they're never duplicates
+		if !link.IsPage() {
+			copied.CanonicalHash = urlcanon.HashForOrg(h.db, target.OrganizationID, link.URL)
+		}
+		if err := h.db.Create(&copied).Error; err != nil {
+			return result, err
+		}
+		// A copied page starts its own history
+		if copied.IsPage() {
+			if err := saveRevision(h.db, &copied, userID); err != nil {
+				return result, err
+			}
+		}
+		// Explicitly set boolean fields to override GORM defaults
+		if err := h.db.Model(&copied).Updates(map[string]interface{}{
+			"is_public": link.IsPublic,
+			"is_unread": link.IsUnread,
+		}).Error; err != nil {
+			return result, err
+		}
+
+		result.NewID = copied.ID
+		result.NewSlug = copied.Slug
+		result.Status = TransferStatusCopied
+		return result, nil
+	}
+
+	updates := map[string]interface{}{
+		"organization_id": target.OrganizationID,
+		"group_id":        target.ID,
+		"slug":            slug,
+	}
+	if crossOrg {
+		// Tracking parameters are per organization, and aliases can't span organizations
+		if !link.IsPage() {
+			updates["canonical_hash"] = urlcanon.HashForOrg(h.db, target.OrganizationID, link.URL)
+		}
+		updates["alias_of_id"] = nil
+	}
+	if err := h.db.Model(link).Updates(updates).Error; err != nil {
+		return result, err
+	}
+	if crossOrg {
+		if err := h.db.Model(link).Association("Tags").Replace(linkTags); err != nil {
+			return result, err
+		}
+		// Grants were made to users and groups of the old organization
+		if err := h.db.Where("link_id = ?", link.ID).Delete(&models.LinkGrant{}).Error; err != nil {
+			return result, err
+		}
+	}
+
+	result.NewID = link.ID
+	result.NewSlug = slug
+	result.Status = TransferStatusMoved
+	return result, nil
+}
+
+// transferLinks moves or copies links in a single transaction and writes the response.
+// With the "fail" strategy any slug conflict rolls back the whole operation.
+func (h *Handler) transferLinks(c *gin.Context, userID uint, links []models.Link, req *TransferRequest) {
+	mode := req.Mode
+	if mode == "" {
+		mode = TransferModeMove
+	}
+	onConflict := req.OnConflict
+	if onConflict == "" {
+		onConflict = ConflictFail
+	}
+
+	// The caller must administer the target group
+	if !access.IsGroupAdmin(h.db, userID, req.TargetGroupID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access to the target group required"})
+		return
+	}
+
+	var target models.Group
+	if err := h.db.First(&target, req.TargetGroupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	// Protected links are only moved or copied by their approvers
+	for i := range links {
+		rule, err := h.transferProtection(userID, &links[i], &target, mode)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check protected slugs"})
+			return
+		}
+		if rule != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Link '%s' is protected, so only its approvers can move or copy it", links[i].Slug), "slug": links[i].Slug})
+			return
+		}
+	}
+
+	response := TransferResponse{
+		Mode:          mode,
+		TargetGroupID: target.ID,
+		Results:       []TransferResult{},
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		for i := range links {
+			result, err := h.withDB(tx).transferLink(&links[i], &target, mode, onConflict, userID)
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				return &ValidationError{fmt.Sprintf("Link '%s': %s", links[i].Slug, validationErr.Message)}
+			}
+			if err != nil {
+				return err
+			}
+			if result.Status == TransferStatusSkipped {
+				response.Skipped++
+			} else {
+				response.Transferred++
+			}
+			response.Results = append(response.Results, result)
+		}
+		return nil
+	})
+
+	var conflictErr *SlugConflictError
+	var validationErr *ValidationError
+	if errors.As(err, &conflictErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": conflictErr.Error(), "slug": conflictErr.Slug})
+		return
+	}
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transfer links"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Transfer moves or copies a single link to another group
+// @Summary Move or copy a link
+// @Description Move or copy a link to another group the caller administers, possibly in another organization. Slug conflicts in the target organization are skipped, renamed or fail the request depending on on_conflict.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param slug path string true "Link slug"
+// @Param request body TransferRequest true "Transfer details"
+// @Success 200 {object} TransferResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Admin access to the target group required, or the link is protected"
+// @Failure 404 {object} map[string]string "Link not found"
+// @Failure 409 {object} map[string]string "Slug conflict"
+// @Security BearerAuth
+// @Router /links/{slug}/transfer [post]
+func (h *Handler) Transfer(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	slug := c.Param("slug")
+
+	var link models.Link
+	if err := h.db.Where("slug = ?", slug).First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+
+	// Check membership
+	if err := access.Authorize(h.db, userID, link.GroupID, access.ActionView); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+
+	var req TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Moving a link out of its group changes it; copying only reads it
+	if req.Mode != TransferModeCopy && access.Authorize(h.db, userID, link.GroupID, access.EditAction(userID, &link)) != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your role in this group doesn't allow moving this link"})
+		return
+	}
+
+	h.transferLinks(c, userID, []models.Link{link}, &req)
+}
+
+// TransferGroup moves or copies all links in a group to another group
+// @Summary Move or copy a group's links
+// @Description Move or copy every link in a group to another group. Moving requires admin access to the source group; both modes require admin access to the target group.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path int true "Source group ID"
+// @Param request body TransferRequest true "Transfer details"
+// @Success 200 {object} TransferResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Admin access required, or a link is protected"
+// @Failure 404 {object} map[string]string "Group not found"
+// @Failure 409 {object} map[string]string "Slug conflict"
+// @Security BearerAuth
+// @Router /groups/{id}/links/transfer [post]
+func (h *Handler) TransferGroup(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	if err := access.Authorize(h.db, userID, uint(groupID), access.ActionView); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	var req TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Emptying a group requires admin access to it
+	if req.Mode != TransferModeCopy && !access.IsGroupAdmin(h.db, userID, uint(groupID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	if uint(groupID) == req.TargetGroupID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Target group must differ from the source group"})
+		return
+	}
+
+	var links []models.Link
+	if err := h.db.Where("group_id = ?", groupID).Order("id").Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch links"})
+		return
+	}
+
+	h.transferLinks(c, userID, links, &req)
+}
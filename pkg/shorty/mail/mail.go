@@ -0,0 +1,84 @@
+// Package mail sends notification emails. Without SMTP configuration,
+// messages are written to the log instead.
+package mail
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Sender delivers messages
+type Sender interface {
+	Send(msg Message) error
+}
+
+// LogSender writes messages to the log. It is used when SMTP isn't configured.
+type LogSender struct{}
+
+// Send logs the message
+func (LogSender) Send(msg Message) error {
+	log.Printf("Mail to %s: %s\n%s", strings.Join(msg.To, ", "), msg.Subject, msg.Body)
+	return nil
+}
+
+// SMTPSender delivers messages through an SMTP server
+type SMTPSender struct {
+	Addr     string // host:port
+	From     string
+	Username string
+	Password string
+}
+
+// Send delivers the message
+func (s *SMTPSender) Send(msg Message) error {
+	if len(msg.To) == 0 {
+		return nil
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, _ := net.SplitHostPort(s.Addr)
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+
+	body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
+		s.From, strings.Join(msg.To, ", "), msg.Subject, msg.Body)
+	return smtp.SendMail(s.Addr, auth, s.From, msg.To, []byte(body))
+}
+
+// FromEnv returns an SMTP sender configured by SHORTY_SMTP_HOST, SHORTY_SMTP_PORT,
+// SHORTY_SMTP_USERNAME, SHORTY_SMTP_PASSWORD and SHORTY_MAIL_FROM, or a
+// LogSender if SHORTY_SMTP_HOST isn't set
+func FromEnv() Sender {
+	host := os.Getenv("SHORTY_SMTP_HOST")
+	if host == "" {
+		return LogSender{}
+	}
+
+	port := os.Getenv("SHORTY_SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SHORTY_MAIL_FROM")
+	if from == "" {
+		from = "shorty@" + host
+	}
+
+	return &SMTPSender{
+		Addr:     net.JoinHostPort(host, port),
+		From:     from,
+		Username: os.Getenv("SHORTY_SMTP_USERNAME"),
+		Password: os.Getenv("SHORTY_SMTP_PASSWORD"),
+	}
+}
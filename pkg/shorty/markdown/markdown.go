@@ -0,0 +1,45 @@
+// Package markdown renders user-supplied Markdown, such as the body of page
+// links, to HTML that is safe to serve. Raw HTML is dropped, and links and
+// images only keep destinations with trusted protocols.
+package markdown
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+)
+
+const htmlFlags = blackfriday.SkipHTML | blackfriday.Safelink |
+	blackfriday.NofollowLinks | blackfriday.NoreferrerLinks | blackfriday.NoopenerLinks
+
+// renderer is the standard HTML renderer, minus images from untrusted sources
+type renderer struct {
+	*blackfriday.HTMLRenderer
+}
+
+func (r renderer) RenderNode(w io.Writer, node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+	if node.Type == blackfriday.Image && !safeImage(string(node.LinkData.Destination)) {
+		return blackfriday.SkipChildren
+	}
+	return r.HTMLRenderer.RenderNode(w, node, entering)
+}
+
+// safeImage allows http(s) and relative image sources
+func safeImage(dest string) bool {
+	lower := strings.ToLower(dest)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		return true
+	}
+	return dest != "" && !strings.Contains(strings.SplitN(dest, "/", 2)[0], ":")
+}
+
+// Render converts Markdown to sanitized HTML
+func Render(source string) template.HTML {
+	r := renderer{blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{Flags: htmlFlags})}
+	output := blackfriday.Run([]byte(source),
+		blackfriday.WithRenderer(r),
+		blackfriday.WithExtensions(blackfriday.CommonExtensions|blackfriday.AutoHeadingIDs))
+	return template.HTML(output)
+}
@@ -0,0 +1,43 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	output := string(Render("# On-call\n\n- [Runbook](https://example.com/runbook)\n- `kubectl get pods`\n"))
+
+	for _, want := range []string{`<h1 id="on-call">On-call</h1>`, `<a href="https://example.com/runbook" rel="nofollow noreferrer noopener">Runbook</a>`, `<code>kubectl get pods</code>`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		banned string
+	}{
+		{"script block", "<script>alert(1)</script>", "<script"},
+		{"inline html", "Hello <img src=x onerror=alert(1)>", "onerror"},
+		{"javascript link", "[click](javascript:alert(1))", "javascript:"},
+		{"javascript image", "![x](javascript:alert(1))", "javascript:"},
+		{"data image", "![x](data:image/svg+xml;base64,PHN2Zz4=)", "data:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if output := string(Render(tt.source)); strings.Contains(output, tt.banned) {
+				t.Errorf("Expected %q to be removed, got %s", tt.banned, output)
+			}
+		})
+	}
+
+	// Relative and https images are kept
+	if output := string(Render("![logo](/static/logo.png) ![x](https://example.com/x.png)")); !strings.Contains(output, `src="/static/logo.png"`) || !strings.Contains(output, `src="https://example.com/x.png"`) {
+		t.Errorf("Expected images to be kept, got %s", output)
+	}
+}
@@ -0,0 +1,334 @@
+// Package metadata fetches page metadata (title, description, preview image and
+// favicon) for link destinations.
+//
+// Fetching is done with conservative timeouts and size limits, and by default
+// refuses to connect to loopback, private and link-local addresses so that user
+// supplied URLs can't be used to probe internal services (SSRF).
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// Default limits used by NewFetcher when options are left at zero values
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxBytes     = 1 << 20 // 1 MiB
+	DefaultMaxRedirects = 5
+	DefaultUserAgent    = "ShortyBot/1.0 (+https://github.com/mikepea/shorty)"
+)
+
+var (
+	// ErrBlockedAddress is returned when the destination resolves to a disallowed address
+	ErrBlockedAddress = errors.New("destination address is not allowed")
+	// ErrUnsupportedScheme is returned for URLs that are not http or https
+	ErrUnsupportedScheme = errors.New("only http and https URLs can be fetched")
+	// ErrNotHTML is returned when the destination doesn't serve an HTML document
+	ErrNotHTML = errors.New("destination is not an HTML page")
+)
+
+// Metadata holds the details extracted from a page
+type Metadata struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url"`
+	FaviconURL  string `json:"favicon_url"`
+	StatusCode  int    `json:"status_code"`
+}
+
+// Options configures a Fetcher
+type Options struct {
+	Timeout      time.Duration
+	MaxBytes     int64
+	MaxRedirects int
+	UserAgent    string
+	// AllowPrivateNetworks disables SSRF protection. Only intended for tests
+	// that fetch from local httptest servers.
+	AllowPrivateNetworks bool
+}
+
+// Fetcher retrieves and parses page metadata
+type Fetcher struct {
+	client    *http.Client
+	maxBytes  int64
+	userAgent string
+}
+
+// NewFetcher creates a new metadata fetcher
+func NewFetcher(opts Options) *Fetcher {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxBytes == 0 {
+		opts.MaxBytes = DefaultMaxBytes
+	}
+	if opts.MaxRedirects == 0 {
+		opts.MaxRedirects = DefaultMaxRedirects
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !opts.AllowPrivateNetworks {
+		// Checking the address at connect time (after DNS resolution) also
+		// covers hostnames that resolve to internal addresses and redirects
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || isBlockedIP(ip) {
+				return ErrBlockedAddress
+			}
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil, // Never route through a proxy, which would bypass the address checks
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   opts.Timeout,
+		ResponseHeaderTimeout: opts.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	maxRedirects := opts.MaxRedirects
+	client := &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrUnsupportedScheme
+			}
+			return nil
+		},
+	}
+
+	return &Fetcher{
+		client:    client,
+		maxBytes:  opts.MaxBytes,
+		userAgent: opts.UserAgent,
+	}
+}
+
+// blockedNetworks lists ranges not covered by the net.IP helpers
+var blockedNetworks = []*net.IPNet{
+	mustParseCIDR("100.64.0.0/10"), // Carrier-grade NAT
+	mustParseCIDR("192.0.0.0/24"),  // IETF protocol assignments
+	mustParseCIDR("198.18.0.0/15"), // Benchmarking
+	mustParseCIDR("64:ff9b::/96"),  // NAT64, can embed internal IPv4 addresses
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// isBlockedIP reports whether connecting to ip should be refused
+func isBlockedIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, n := range blockedNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch downloads the page at rawURL and extracts its metadata.
+// A non-2xx response is returned as metadata with only StatusCode set.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Metadata, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, ErrUnsupportedScheme
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	meta := &Metadata{StatusCode: resp.StatusCode}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return meta, nil
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+			return meta, ErrNotHTML
+		}
+	}
+
+	// The final URL after redirects is the base for relative references
+	parse(io.LimitReader(resp.Body, f.maxBytes), resp.Request.URL, meta)
+	return meta, nil
+}
+
+// parse extracts metadata from an HTML document. Parsing stops at the end of
+// the <head> element since everything of interest lives there.
+func parse(r io.Reader, base *url.URL, meta *Metadata) {
+	var (
+		title, ogTitle, twitterTitle            string
+		description, ogDescription, twitterDesc string
+		ogImage, twitterImage                   string
+		icon, shortcutIcon                      string
+		inTitle                                 bool
+	)
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			break loop
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = tt == html.StartTagToken
+			case "meta":
+				if !hasAttr {
+					continue
+				}
+				attrs := readAttrs(z)
+				key := strings.ToLower(attrs["property"])
+				if key == "" {
+					key = strings.ToLower(attrs["name"])
+				}
+				content := strings.TrimSpace(attrs["content"])
+				switch key {
+				case "og:title":
+					ogTitle = content
+				case "twitter:title":
+					twitterTitle = content
+				case "description":
+					description = content
+				case "og:description":
+					ogDescription = content
+				case "twitter:description":
+					twitterDesc = content
+				case "og:image", "og:image:url":
+					if ogImage == "" {
+						ogImage = content
+					}
+				case "twitter:image", "twitter:image:src":
+					if twitterImage == "" {
+						twitterImage = content
+					}
+				}
+			case "link":
+				if !hasAttr {
+					continue
+				}
+				attrs := readAttrs(z)
+				rels := strings.Fields(strings.ToLower(attrs["rel"]))
+				for _, rel := range rels {
+					if rel == "icon" && icon == "" {
+						icon = attrs["href"]
+					}
+					if rel == "shortcut" && shortcutIcon == "" {
+						shortcutIcon = attrs["href"]
+					}
+				}
+			case "body":
+				break loop
+			}
+
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = strings.TrimSpace(string(z.Text()))
+			}
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				break loop
+			}
+		}
+	}
+
+	meta.Title = collapseSpace(firstNonEmpty(ogTitle, twitterTitle, title))
+	meta.Description = collapseSpace(firstNonEmpty(ogDescription, twitterDesc, description))
+	meta.ImageURL = resolve(base, firstNonEmpty(ogImage, twitterImage))
+	meta.FaviconURL = resolve(base, firstNonEmpty(icon, shortcutIcon, "/favicon.ico"))
+}
+
+// readAttrs collects the current tag's attributes with lowercased keys
+func readAttrs(z *html.Tokenizer) map[string]string {
+	attrs := make(map[string]string)
+	for {
+		key, val, more := z.TagAttr()
+		attrs[strings.ToLower(string(key))] = string(val)
+		if !more {
+			return attrs
+		}
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// resolve turns a possibly relative reference into an absolute http(s) URL.
+// Returns an empty string for unusable references such as data: or javascript: URLs.
+func resolve(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := base.Parse(strings.TrimSpace(ref))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
@@ -0,0 +1,170 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>  Plain   Title </title>
+  <meta name="description" content="Plain description">
+  <meta property="og:title" content="OpenGraph Title">
+  <meta name="twitter:description" content="Twitter description">
+  <meta property="og:image" content="/images/preview.png">
+  <link rel="shortcut icon" href="/static/favicon.png">
+</head>
+<body><title>Not this one</title></body>
+</html>`
+
+func newTestFetcher() *Fetcher {
+	return NewFetcher(Options{AllowPrivateNetworks: true})
+}
+
+func TestFetchParsesMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(testPage))
+	}))
+	defer server.Close()
+
+	meta, err := newTestFetcher().Fetch(context.Background(), server.URL+"/page")
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	if meta.Title != "OpenGraph Title" {
+		t.Errorf("Expected OpenGraph title, got %q", meta.Title)
+	}
+	if meta.Description != "Twitter description" {
+		t.Errorf("Expected twitter description, got %q", meta.Description)
+	}
+	if meta.ImageURL != server.URL+"/images/preview.png" {
+		t.Errorf("Expected absolute image URL, got %q", meta.ImageURL)
+	}
+	if meta.FaviconURL != server.URL+"/static/favicon.png" {
+		t.Errorf("Expected favicon from link tag, got %q", meta.FaviconURL)
+	}
+	if meta.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", meta.StatusCode)
+	}
+}
+
+func TestFetchFallsBackToTitleTag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Tom &amp; Jerry</title><meta name="description" content="Cartoons"></head></html>`))
+	}))
+	defer server.Close()
+
+	meta, err := newTestFetcher().Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	if meta.Title != "Tom & Jerry" {
+		t.Errorf("Expected title from <title>, got %q", meta.Title)
+	}
+	if meta.Description != "Cartoons" {
+		t.Errorf("Expected meta description, got %q", meta.Description)
+	}
+	if meta.FaviconURL != server.URL+"/favicon.ico" {
+		t.Errorf("Expected default favicon, got %q", meta.FaviconURL)
+	}
+}
+
+func TestFetchRespectsSizeLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head>" + strings.Repeat(" ", 2048) + "<title>Too far</title></head></html>"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(Options{AllowPrivateNetworks: true, MaxBytes: 1024})
+	meta, err := fetcher.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if meta.Title != "" {
+		t.Errorf("Expected title beyond the size limit to be ignored, got %q", meta.Title)
+	}
+}
+
+func TestFetchNonHTML(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.4"))
+	}))
+	defer server.Close()
+
+	_, err := newTestFetcher().Fetch(context.Background(), server.URL)
+	if !errors.Is(err, ErrNotHTML) {
+		t.Errorf("Expected ErrNotHTML, got %v", err)
+	}
+}
+
+func TestFetchErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	meta, err := newTestFetcher().Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if meta.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", meta.StatusCode)
+	}
+}
+
+func TestFetchBlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testPage))
+	}))
+	defer server.Close()
+
+	_, err := NewFetcher(Options{}).Fetch(context.Background(), server.URL)
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Expected ErrBlockedAddress for loopback server, got %v", err)
+	}
+}
+
+func TestFetchRejectsUnsupportedScheme(t *testing.T) {
+	_, err := NewFetcher(Options{}).Fetch(context.Background(), "file:///etc/passwd")
+	if !errors.Is(err, ErrUnsupportedScheme) {
+		t.Errorf("Expected ErrUnsupportedScheme, got %v", err)
+	}
+}
+
+func TestIsBlockedIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"::1", true},
+		{"fd00::1", true},
+		{"fe80::1", true},
+		{"93.184.216.34", false},
+		{"2606:2800:220:1:248:1893:25c8:1946", false},
+	}
+
+	for _, tt := range tests {
+		if got := isBlockedIP(net.ParseIP(tt.ip)); got != tt.blocked {
+			t.Errorf("isBlockedIP(%s) = %v, want %v", tt.ip, got, tt.blocked)
+		}
+	}
+}
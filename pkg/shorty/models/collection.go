@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Collection item kinds
+const (
+	CollectionItemLink    = "link"
+	CollectionItemSection = "section"
+)
+
+// Collection represents a hand-ordered list of links owned by a group,
+// e.g. "Day 1 links" for onboarding. Public collections are rendered at /c/:slug.
+type Collection struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	OrganizationID uint           `gorm:"not null;uniqueIndex:idx_org_collection_slug" json:"organization_id"` // Denormalized from Group
+	GroupID        uint           `gorm:"not null;index" json:"group_id"`
+	CreatedByID    uint           `gorm:"not null" json:"created_by_id"`
+	Slug           string         `gorm:"not null;uniqueIndex:idx_org_collection_slug" json:"slug"` // Unique within organization
+	Title          string         `gorm:"not null" json:"title"`
+	Description    string         `gorm:"type:text" json:"description"`
+	IsPublic       bool           `gorm:"default:false" json:"is_public"` // Rendered read-only at /c/:slug
+
+	// Relationships
+	Group     Group            `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+	CreatedBy User             `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+	Items     []CollectionItem `gorm:"foreignKey:CollectionID" json:"items,omitempty"`
+}
+
+// CollectionItem is an entry in a collection: either a link with an optional
+// note, or a section heading that groups the links after it
+type CollectionItem struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	CollectionID uint           `gorm:"not null;index" json:"collection_id"`
+	Position     int            `gorm:"not null;default:0" json:"position"`
+	Kind         string         `gorm:"not null;default:'link'" json:"kind"` // link or section
+	LinkID       *uint          `gorm:"index" json:"link_id,omitempty"`      // Set for link items
+	Heading      string         `json:"heading,omitempty"`                   // Set for section items
+	Note         string         `gorm:"type:text" json:"note,omitempty"`
+
+	// Relationships
+	Link *Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+}
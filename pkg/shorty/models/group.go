@@ -1,6 +1,8 @@
 package models
 
 import (
+	"net/url"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -9,18 +11,85 @@ import (
 // Group represents a group that owns links
 // Users can belong to multiple groups, and each user has a personal group
 // Groups belong to an organization for multi-tenancy scoping
+// Groups can nest, e.g. Engineering > SRE > Oncall, and members of a group are
+// members of the groups below it with the same role
 type Group struct {
 	ID             uint           `gorm:"primarykey" json:"id"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 	OrganizationID uint           `gorm:"not null;index" json:"organization_id"` // FK to Organization
+	ParentID       *uint          `gorm:"index" json:"parent_id,omitempty"`      // Group above this one; its members are members here too
 	ExternalID     string         `gorm:"index" json:"external_id,omitempty"`    // SCIM externalId
 	Name           string         `gorm:"not null" json:"name"`
 	Description    string         `json:"description"`
+	Namespace      string         `gorm:"index" json:"namespace,omitempty"`    // Slug prefix owned by the group, e.g. "sre" for sre/runbook
+	PublicSlug     string         `gorm:"index" json:"public_slug,omitempty"`  // Path of the group's public page, e.g. "sre" for /g/sre
+	IsPublicPage   bool           `gorm:"default:false" json:"is_public_page"` // Whether the public page lists the group's public links
+
+	// Defaults and rules for links created in or imported into the group
+	DefaultIsPublic   bool   `gorm:"default:false" json:"default_is_public"` // Visibility of new links that don't choose one
+	DefaultTags       string `json:"default_tags"`                           // Comma-separated tags added to every new link
+	DefaultExpiryDays int    `gorm:"default:0" json:"default_expiry_days"`   // New links without an expiry expire after this many days; 0 for never
+	AllowedDomains    string `json:"allowed_domains"`                        // Comma-separated destination domains, including their subdomains; empty allows any
+	RequireTitle      bool   `gorm:"default:false" json:"require_title"`     // New links must have a title
+	SlugPrefix        string `json:"slug_prefix"`                            // New slugs must start with this, e.g. "sre-"
 
 	// Relationships
 	Organization Organization      `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
 	Members      []GroupMembership `gorm:"foreignKey:GroupID" json:"members,omitempty"`
 	Links        []Link            `gorm:"foreignKey:GroupID" json:"links,omitempty"`
 }
+
+// DefaultTagList returns the tags added to new links in the group
+func (g *Group) DefaultTagList() []string {
+	return splitList(g.DefaultTags)
+}
+
+// AllowedDomainList returns the destination domains links in the group may use
+func (g *Group) AllowedDomainList() []string {
+	return splitList(g.AllowedDomains)
+}
+
+// AllowsURL reports whether a link destination is on one of the group's
+// allowed domains or their subdomains. Any destination is allowed when the
+// group doesn't restrict domains.
+func (g *Group) AllowsURL(rawURL string) bool {
+	domains := g.AllowedDomainList()
+	if len(domains) == 0 {
+		return true
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultExpiry returns when a link created now expires under the group's
+// default, or nil if links don't expire by default
+func (g *Group) DefaultExpiry(now time.Time) *time.Time {
+	if g.DefaultExpiryDays <= 0 {
+		return nil
+	}
+	expiresAt := now.AddDate(0, 0, g.DefaultExpiryDays)
+	return &expiresAt
+}
+
+// splitList splits a comma-separated setting, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
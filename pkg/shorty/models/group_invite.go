@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GroupInvite invites people to join a group with a role. An email invite is
+// for one address and is used once; an invite link has no email and can be
+// used by anyone with the token until it expires or runs out of uses.
+// Only a hash of the token is stored.
+type GroupInvite struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	GroupID      uint           `gorm:"not null;index" json:"group_id"`
+	Email        string         `gorm:"index" json:"email,omitempty"` // Empty for invite links
+	Role         GroupRole      `gorm:"type:varchar(20);default:'member'" json:"role"`
+	TokenHash    string         `gorm:"not null;uniqueIndex" json:"-"`
+	TokenPrefix  string         `gorm:"not null" json:"token_prefix"` // First few chars for identification
+	ExpiresAt    *time.Time     `json:"expires_at,omitempty"`
+	MaxUses      int            `gorm:"default:0" json:"max_uses"` // 0 means unlimited
+	Uses         int            `gorm:"default:0" json:"uses"`
+	RevokedAt    *time.Time     `json:"revoked_at,omitempty"`
+	CreatedByID  uint           `gorm:"not null" json:"created_by_id"`
+	AcceptedByID *uint          `json:"accepted_by_id,omitempty"` // Email invites only
+
+	// Relationships
+	Group     Group `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+	CreatedBy User  `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+}
@@ -10,8 +10,11 @@ import (
 type GroupRole string
 
 const (
-	GroupRoleAdmin  GroupRole = "admin"
-	GroupRoleMember GroupRole = "member"
+	GroupRoleAdmin       GroupRole = "admin"
+	GroupRoleEditor      GroupRole = "editor"      // Creates links and edits any link in the group
+	GroupRoleMember      GroupRole = "member"      // Same as editor; the default role
+	GroupRoleContributor GroupRole = "contributor" // Creates links and edits only their own
+	GroupRoleViewer      GroupRole = "viewer"      // Read only
 )
 
 // GroupMembership represents the many-to-many relationship between users and groups
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// JobLock is a lease on a background job, so that only one server instance
+// runs it at a time. An instance holds the lease until ExpiresAt and renews it
+// each run; another instance takes over once it lapses.
+type JobLock struct {
+	Name      string    `gorm:"primarykey" json:"name"`
+	Holder    string    `gorm:"not null" json:"holder"` // Identifies the instance holding the lease
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+}
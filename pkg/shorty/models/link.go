@@ -6,6 +6,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// LinkKind is what a link does when its slug is visited
+type LinkKind string
+
+const (
+	LinkKindRedirect LinkKind = "redirect" // Redirects to URL
+	LinkKindPage     LinkKind = "page"     // Renders Content as Markdown
+)
+
 // Link represents a shortened URL/bookmark
 // Links are scoped to organizations - the same slug can exist in different organizations
 type Link struct {
@@ -17,12 +25,29 @@ type Link struct {
 	GroupID        uint           `gorm:"not null;index" json:"group_id"`
 	CreatedByID    uint           `gorm:"not null" json:"created_by_id"`
 	Slug           string         `gorm:"not null;uniqueIndex:idx_org_slug" json:"slug"` // Unique within organization
-	URL            string         `gorm:"not null" json:"url"`
+	Kind           LinkKind       `gorm:"default:'redirect'" json:"kind"`
+	URL            string         `gorm:"not null" json:"url"`                // Empty for pages
+	Content        string         `gorm:"type:text" json:"content,omitempty"` // Markdown body of a page
+	CanonicalHash  string         `gorm:"index" json:"-"`                     // SHA-256 of the canonicalized URL, for duplicate detection
+	AliasOfID      *uint          `gorm:"index" json:"alias_of_id,omitempty"` // Redirects follow this link's URL when set
 	Title          string         `json:"title"`
 	Description    string         `json:"description"`
 	IsPublic       bool           `gorm:"default:false" json:"is_public"`
 	IsUnread       bool           `gorm:"default:true" json:"is_unread"`
 	ClickCount     uint           `gorm:"default:0" json:"click_count"`
+	CommentCount   uint           `gorm:"default:0" json:"comment_count"`    // Maintained by the comments handler
+	ExpiresAt      *time.Time     `gorm:"index" json:"expires_at,omitempty"` // Redirects stop working after this time
+	ImageURL       string         `json:"image_url"`                         // Preview image (og:image / twitter:image)
+	FaviconURL     string         `json:"favicon_url"`
+	FetchedAt      *time.Time     `json:"fetched_at,omitempty"`          // Last metadata fetch attempt
+	FetchStatus    int            `gorm:"default:0" json:"fetch_status"` // HTTP status of the last fetch (0 if the request failed)
+	LastClickedAt  *time.Time     `json:"last_clicked_at,omitempty"`
+	TrackedSince   *time.Time     `json:"-"` // When the link was added; CreatedAt can be an imported bookmark's original date
+
+	// Stale link reclamation. Group admins are notified when a link goes stale,
+	// and it is archived after a grace period, releasing its slug.
+	StaleNotifiedAt *time.Time `json:"stale_notified_at,omitempty"`
+	ArchivedSlug    string     `json:"archived_slug,omitempty"` // The slug an archived link held before it was released
 
 	// Relationships
 	Organization Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
@@ -30,3 +55,23 @@ type Link struct {
 	CreatedBy    User         `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
 	Tags         []Tag        `gorm:"many2many:link_tags;" json:"tags,omitempty"`
 }
+
+// BeforeCreate records when the link was added, which stale link detection
+// measures idle time from
+func (l *Link) BeforeCreate(tx *gorm.DB) error {
+	if l.TrackedSince == nil {
+		now := time.Now()
+		l.TrackedSince = &now
+	}
+	return nil
+}
+
+// IsPage reports whether the link renders content instead of redirecting
+func (l *Link) IsPage() bool {
+	return l.Kind == LinkKindPage
+}
+
+// IsExpired reports whether the link has passed its expiry time
+func (l *Link) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
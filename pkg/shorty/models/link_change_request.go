@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ChangeRequestStatus represents the review state of a change request
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestPending  ChangeRequestStatus = "pending"
+	ChangeRequestApproved ChangeRequestStatus = "approved"
+	ChangeRequestRejected ChangeRequestStatus = "rejected"
+)
+
+// Change request actions
+const (
+	ChangeActionCreate = "create"
+	ChangeActionUpdate = "update"
+	ChangeActionDelete = "delete"
+)
+
+// LinkChangeRequest is a pending create, update or delete of a link with a
+// protected slug. The change is applied only once an approver accepts it.
+type LinkChangeRequest struct {
+	ID              uint                `gorm:"primarykey" json:"id"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt      `gorm:"index" json:"-"`
+	OrganizationID  uint                `gorm:"not null;index" json:"organization_id"`
+	GroupID         uint                `gorm:"not null;index" json:"group_id"`
+	LinkID          *uint               `gorm:"index" json:"link_id,omitempty"` // Set for updates and deletes
+	ProtectedSlugID uint                `gorm:"not null;index" json:"protected_slug_id"`
+	Action          string              `gorm:"type:varchar(20);not null" json:"action"` // create, update or delete
+	Slug            string              `gorm:"not null" json:"slug"`                    // The protected slug being changed
+	Payload         string              `gorm:"type:text" json:"payload"`                // JSON-encoded create or update request; empty object for deletes
+	Status          ChangeRequestStatus `gorm:"type:varchar(20);default:'pending';index" json:"status"`
+	RequestedByID   uint                `gorm:"not null;index" json:"requested_by_id"`
+	ReviewedByID    *uint               `json:"reviewed_by_id,omitempty"`
+	ReviewedAt      *time.Time          `json:"reviewed_at,omitempty"`
+	ReviewComment   string              `gorm:"type:text" json:"review_comment,omitempty"`
+
+	// Relationships
+	Link          *Link         `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+	ProtectedSlug ProtectedSlug `gorm:"foreignKey:ProtectedSlugID" json:"protected_slug,omitempty"`
+	RequestedBy   User          `gorm:"foreignKey:RequestedByID" json:"requested_by,omitempty"`
+	ReviewedBy    *User         `gorm:"foreignKey:ReviewedByID" json:"reviewed_by,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LinkComment represents a comment left on a link by a team member.
+// Threads are one level deep: a reply's ParentID is always a top-level comment.
+type LinkComment struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	LinkID    uint           `gorm:"not null;index" json:"link_id"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"` // Author
+	ParentID  *uint          `gorm:"index" json:"parent_id,omitempty"`
+	Body      string         `gorm:"type:text;not null" json:"body"`
+
+	// Relationships
+	Link     Link          `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+	User     User          `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Replies  []LinkComment `gorm:"foreignKey:ParentID" json:"replies,omitempty"`
+	Mentions []User        `gorm:"many2many:link_comment_mentions;" json:"mentions,omitempty"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Link history actions
+const (
+	LinkEventTagRenamed = "tag_renamed"
+	LinkEventTagMerged  = "tag_merged"
+	LinkEventTagDeleted = "tag_deleted"
+)
+
+// LinkEvent is an entry in a link's history, recording a change made to the
+// link by someone other than through editing it directly, such as an admin
+// renaming one of its tags
+type LinkEvent struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	LinkID    uint      `gorm:"not null;index" json:"link_id"`
+	ActorID   uint      `gorm:"not null" json:"actor_id"`
+	Action    string    `gorm:"not null" json:"action"`
+	Detail    string    `json:"detail"` // Human-readable description of the change
+
+	// Relationships
+	Link  Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+	Actor User `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
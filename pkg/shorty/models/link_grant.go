@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GrantRole is the access a link grant gives
+type GrantRole string
+
+const (
+	GrantRoleViewer GrantRole = "viewer"
+	GrantRoleEditor GrantRole = "editor"
+)
+
+// LinkGrant shares a single link with a user or group outside the group that
+// owns it. Exactly one of UserID and GroupID is set.
+type LinkGrant struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	LinkID      uint           `gorm:"not null;index" json:"link_id"`
+	UserID      *uint          `gorm:"index" json:"user_id,omitempty"`
+	GroupID     *uint          `gorm:"index" json:"group_id,omitempty"`
+	Role        GrantRole      `gorm:"type:varchar(20);default:'viewer'" json:"role"`
+	GrantedByID uint           `gorm:"not null" json:"granted_by_id"`
+
+	// Relationships
+	Link  Link   `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+	User  *User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Group *Group `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+}
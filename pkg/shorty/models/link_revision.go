@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// LinkRevision is a saved version of a page link's title and content.
+// A revision is recorded each time a page is created or its body changes.
+type LinkRevision struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LinkID     uint      `gorm:"not null;uniqueIndex:idx_link_revision" json:"link_id"`
+	Revision   uint      `gorm:"not null;uniqueIndex:idx_link_revision" json:"revision"` // Numbered from 1 per link
+	Title      string    `json:"title"`
+	Content    string    `gorm:"type:text" json:"content"`
+	EditedByID uint      `gorm:"not null" json:"edited_by_id"`
+
+	// Relationships
+	Link     Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+	EditedBy User `gorm:"foreignKey:EditedByID" json:"edited_by,omitempty"`
+}
@@ -12,12 +12,24 @@ func AllModels() []interface{} {
 		&User{},
 		&Group{},
 		&GroupMembership{},
+		&GroupInvite{},
 		&Link{},
 		&Tag{},
+		&UserLinkState{},
+		&LinkComment{},
+		&SavedSearch{},
+		&Collection{},
+		&CollectionItem{},
+		&ProtectedSlug{},
+		&LinkChangeRequest{},
+		&LinkGrant{},
+		&LinkRevision{},
+		&LinkEvent{},
 		&APIKey{},
 		&OIDCProvider{},
 		&OIDCIdentity{},
 		&SCIMToken{},
+		&JobLock{},
 	}
 }
 
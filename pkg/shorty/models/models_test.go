@@ -24,7 +24,7 @@ func TestAutoMigrate(t *testing.T) {
 	}
 
 	// Verify tables exist by checking if we can query them
-	tables := []string{"users", "groups", "group_memberships", "links", "tags", "api_keys", "link_tags"}
+	tables := []string{"users", "groups", "group_memberships", "links", "tags", "api_keys", "link_tags", "user_link_states", "link_comments", "saved_searches", "collections", "collection_items", "protected_slugs", "protected_slug_approvers", "link_change_requests", "link_grants", "link_revisions", "link_events", "job_locks"}
 	for _, table := range tables {
 		if !db.Migrator().HasTable(table) {
 			t.Errorf("Expected table %s to exist", table)
@@ -168,3 +168,28 @@ func TestSlugUniqueness(t *testing.T) {
 		t.Error("Expected error when creating link with duplicate slug")
 	}
 }
+
+func TestGroupAllowsURL(t *testing.T) {
+	group := Group{AllowedDomains: "example.com, Docs.Acme.io"}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com/page", true},
+		{"https://wiki.example.com", true},
+		{"https://docs.acme.io/guide", true},
+		{"https://acme.io", false},
+		{"https://notexample.com", false},
+		{"https://example.com.evil.net", false},
+	}
+	for _, tt := range tests {
+		if got := group.AllowsURL(tt.url); got != tt.want {
+			t.Errorf("AllowsURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+
+	if !(&Group{}).AllowsURL("https://anything.net") {
+		t.Error("Expected a group without allowed domains to allow any URL")
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,6 +15,15 @@ const (
 	OrgRoleMember OrgRole = "member"
 )
 
+// DeparturePolicy decides what happens to the links of a user who leaves
+type DeparturePolicy string
+
+const (
+	DeparturePolicyTransfer DeparturePolicy = "transfer" // Reassign links to the designated owner or a group admin
+	DeparturePolicyKeep     DeparturePolicy = "keep"     // Leave links in place under the departed user
+	DeparturePolicyDelete   DeparturePolicy = "delete"   // Delete the links
+)
+
 // Organization represents a tenant in the multi-tenancy system.
 // Organizations scope SSO settings, SCIM provisioning, teams/groups, and link slugs.
 // There is always a special "Shorty Global" organization (IsGlobal=true) that serves
@@ -27,12 +37,62 @@ type Organization struct {
 	Slug      string         `gorm:"uniqueIndex;not null" json:"slug"` // URL-safe identifier, unique across all orgs
 	IsGlobal  bool           `gorm:"default:false" json:"is_global"`   // True only for "Shorty Global"
 
+	// TrackingParams is a comma-separated list of extra query parameters ignored
+	// when detecting duplicate link destinations (e.g. "ref,source,campaign_*")
+	TrackingParams string `json:"tracking_params"`
+
+	// Slug generation settings for links created without an explicit slug
+	SlugStrategy string `gorm:"type:varchar(20);default:'random'" json:"slug_strategy"` // random, words, title or sequential
+	SlugLength   int    `gorm:"default:8" json:"slug_length"`                           // Length of random slugs
+	SlugSequence uint64 `gorm:"default:0" json:"-"`                                     // Counter for the sequential strategy
+
+	// ReservedNamespaces is a comma-separated list of slug namespaces that no
+	// group in the organization may claim (e.g. "go,docs")
+	ReservedNamespaces string `json:"reserved_namespaces"`
+
+	// What happens to a user's links when they are deleted, deactivated or
+	// removed from the organization
+	DeparturePolicy  DeparturePolicy `gorm:"type:varchar(20);default:'transfer'" json:"departure_policy"`
+	DepartureOwnerID *uint           `json:"departure_owner_id,omitempty"` // Receives transferred links; the admins of each link's group if unset
+
 	// Relationships
 	Members []OrganizationMembership `gorm:"foreignKey:OrganizationID" json:"members,omitempty"`
 	Domains []OrganizationDomain     `gorm:"foreignKey:OrganizationID" json:"domains,omitempty"`
 	Groups  []Group                  `gorm:"foreignKey:OrganizationID" json:"groups,omitempty"`
 }
 
+// TrackingParamList returns the organization's extra tracking parameters
+func (o *Organization) TrackingParamList() []string {
+	var params []string
+	for _, p := range strings.Split(o.TrackingParams, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// ReservedNamespaceList returns the organization's reserved namespaces
+func (o *Organization) ReservedNamespaceList() []string {
+	var namespaces []string
+	for _, ns := range strings.Split(o.ReservedNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// IsReservedNamespace reports whether the organization has reserved a namespace
+func (o *Organization) IsReservedNamespace(namespace string) bool {
+	for _, ns := range o.ReservedNamespaceList() {
+		if strings.EqualFold(ns, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
 // OrganizationMembership represents the many-to-many relationship between users and organizations.
 // Users can belong to multiple organizations with different roles in each.
 type OrganizationMembership struct {
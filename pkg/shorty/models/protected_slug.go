@@ -0,0 +1,32 @@
+package models
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ProtectedSlug marks a slug, or a pattern of slugs, whose links only change
+// with approval. Patterns use shell-style wildcards, e.g. "security*" or "hr/*".
+// When no approvers are designated, the organization's admins approve changes.
+type ProtectedSlug struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	OrganizationID uint           `gorm:"not null;index" json:"organization_id"`
+	Pattern        string         `gorm:"not null" json:"pattern"`
+	CreatedByID    uint           `gorm:"not null" json:"created_by_id"`
+
+	// Relationships
+	Organization Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	Approvers    []User       `gorm:"many2many:protected_slug_approvers;" json:"approvers,omitempty"`
+}
+
+// Matches reports whether a slug is covered by the pattern. Matching ignores case.
+func (p *ProtectedSlug) Matches(slug string) bool {
+	ok, err := path.Match(strings.ToLower(p.Pattern), strings.ToLower(slug))
+	return err == nil && ok
+}
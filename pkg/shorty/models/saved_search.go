@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SavedSearch is a named link search owned by a user.
+// A saved search can be shared with a group, and a shared search that is pinned
+// appears on the group's dashboard.
+type SavedSearch struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"`   // Owner
+	GroupID   *uint          `gorm:"index" json:"group_id,omitempty"` // Shared with this group's members when set
+	Name      string         `gorm:"not null" json:"name"`
+	Params    string         `gorm:"type:text" json:"params"`        // JSON-encoded search query and filters
+	IsPinned  bool           `gorm:"default:false" json:"is_pinned"` // Shown on the group's dashboard
+
+	// Relationships
+	User  User   `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Group *Group `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+}
@@ -6,13 +6,17 @@ import (
 	"gorm.io/gorm"
 )
 
-// Tag represents a tag that can be applied to links
+// Tag represents a tag that can be applied to links.
+// Tags belong to an organization; names are unique within it.
 type Tag struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
-	Name      string         `gorm:"uniqueIndex;not null" json:"name"`
+	ID             uint           `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	OrganizationID uint           `gorm:"not null;default:0;uniqueIndex:idx_org_tag" json:"organization_id"`
+	Name           string         `gorm:"not null;uniqueIndex:idx_org_tag" json:"name"`
+	Description    string         `json:"description"`
+	Color          string         `json:"color"` // Display color as #rrggbb
 
 	// Relationships
 	Links []Link `gorm:"many2many:link_tags;" json:"links,omitempty"`
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// UserLinkState holds one user's personal state for a link: favorite and pinned
+// flags, their own read/unread status and private notes.
+// Rows are created lazily the first time a user changes any of these.
+type UserLinkState struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	UserID     uint       `gorm:"not null;uniqueIndex:idx_user_link" json:"user_id"`
+	LinkID     uint       `gorm:"not null;uniqueIndex:idx_user_link;index" json:"link_id"`
+	IsFavorite bool       `gorm:"default:false" json:"is_favorite"`
+	IsPinned   bool       `gorm:"default:false" json:"is_pinned"`
+	PinnedAt   *time.Time `json:"pinned_at,omitempty"`
+	IsUnread   *bool      `json:"is_unread,omitempty"` // Nil falls back to the link's group-level IsUnread
+	Notes      string     `gorm:"type:text" json:"notes"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Link Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+}
+
+// EffectiveUnread returns the user's unread status for a link, falling back to
+// the link's group-level default when the user hasn't set their own
+func (s *UserLinkState) EffectiveUnread(link *Link) bool {
+	if s != nil && s.IsUnread != nil {
+		return *s.IsUnread
+	}
+	return link.IsUnread
+}
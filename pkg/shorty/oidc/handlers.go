@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/invites"
 	"github.com/mikepea/shorty/pkg/shorty/models"
 	"golang.org/x/oauth2"
 	"gorm.io/gorm"
@@ -38,6 +40,7 @@ type StateData struct {
 	ProviderID uint   `json:"provider_id"`
 	ReturnURL  string `json:"return_url"`
 	Nonce      string `json:"nonce"`
+	Invite     string `json:"invite,omitempty"`
 }
 
 // NewHandler creates a new OIDC handler
@@ -131,6 +134,7 @@ func (h *Handler) ListProviders(c *gin.Context) {
 // AuthURLRequest represents a request for an auth URL
 type AuthURLRequest struct {
 	ReturnURL string `json:"return_url"`
+	Invite    string `json:"invite"` // Optional group invite token to accept after logging in
 }
 
 // GetAuthURL returns the authorization URL for an OIDC provider
@@ -161,6 +165,7 @@ func (h *Handler) GetAuthURL(c *gin.Context) {
 		ProviderID: provider.ID,
 		ReturnURL:  req.ReturnURL,
 		Nonce:      nonce,
+		Invite:     req.Invite,
 	}
 	stateJSON, _ := json.Marshal(stateData)
 	state := base64.URLEncoding.EncodeToString(stateJSON)
@@ -269,12 +274,28 @@ func (h *Handler) Callback(c *gin.Context) {
 		return
 	}
 
+	// Join the groups the user was invited to. Email invites for the address
+	// are only accepted without the token if the provider verified it.
+	if stateData.Invite != "" {
+		if invite, err := invites.Find(h.db, stateData.Invite); err != nil {
+			log.Printf("Warning: Ignoring invalid invite for %s", user.Email)
+		} else if err := invites.Accept(h.db, invite, user); err != nil {
+			log.Printf("Warning: Failed to accept invite for %s: %v", user.Email, err)
+		}
+	}
+	if claims.EmailVerified {
+		if err := invites.AcceptPending(h.db, user); err != nil {
+			log.Printf("Warning: Failed to accept invites for %s: %v", user.Email, err)
+		}
+	}
+
 	// Generate JWT
 	token, err := auth.GenerateToken(user.ID, user.Email, string(user.SystemRole))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
+	auth.SetSessionCookie(c, token)
 
 	// Redirect with token or return JSON based on return URL
 	if stateData.ReturnURL != "" {
@@ -1,6 +1,7 @@
 package organizations
 
 import (
+	"fmt"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -9,6 +10,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
 	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/ownership"
+	"github.com/mikepea/shorty/pkg/shorty/urlcanon"
 	"gorm.io/gorm"
 )
 
@@ -32,7 +35,15 @@ type CreateOrgRequest struct {
 
 // UpdateOrgRequest represents the request to update an organization
 type UpdateOrgRequest struct {
-	Name string `json:"name" binding:"omitempty,min=1,max=100"`
+	Name           string    `json:"name" binding:"omitempty,min=1,max=100"`
+	TrackingParams *[]string `json:"tracking_params" binding:"omitempty,max=50,dive,min=1,max=100"` // Extra query params ignored for duplicate detection; "prefix_*" matches a prefix
+	SlugStrategy   string    `json:"slug_strategy" binding:"omitempty,oneof=random words title sequential"`
+	SlugLength     int       `json:"slug_length" binding:"omitempty,min=4,max=50"` // Length of random slugs
+
+	ReservedNamespaces *[]string `json:"reserved_namespaces" binding:"omitempty,max=100,dive,min=1,max=30"` // Slug namespaces no group may claim
+
+	DeparturePolicy  string `json:"departure_policy" binding:"omitempty,oneof=transfer keep delete"` // What happens to a departing member's links
+	DepartureOwnerID *uint  `json:"departure_owner_id"`                                              // Member who receives transferred links; 0 for the admins of each link's group
 }
 
 // OrgResponse represents an organization in API responses
@@ -44,6 +55,15 @@ type OrgResponse struct {
 	Role        string `json:"role,omitempty"`     // User's role in this org
 	MemberCount int    `json:"member_count,omitempty"`
 	CreatedAt   string `json:"created_at"`
+
+	TrackingParams []string `json:"tracking_params,omitempty"`
+	SlugStrategy   string   `json:"slug_strategy,omitempty"`
+	SlugLength     int      `json:"slug_length,omitempty"`
+
+	ReservedNamespaces []string `json:"reserved_namespaces,omitempty"`
+
+	DeparturePolicy  string `json:"departure_policy,omitempty"`
+	DepartureOwnerID *uint  `json:"departure_owner_id,omitempty"`
 }
 
 // MemberResponse represents a member in API responses
@@ -56,6 +76,12 @@ type MemberResponse struct {
 	CreatedAt string `json:"created_at"`
 }
 
+// DepartureResponse lists what happens to a departing member's links
+type DepartureResponse struct {
+	Message string              `json:"message,omitempty"`
+	Links   []ownership.Outcome `json:"links"`
+}
+
 // AddMemberRequest represents the request to add a member
 type AddMemberRequest struct {
 	Email string `json:"email" binding:"required,email"`
@@ -249,6 +275,15 @@ func (h *Handler) Get(c *gin.Context) {
 		Role:        string(membership.Role),
 		MemberCount: int(memberCount),
 		CreatedAt:   org.CreatedAt.Format("2006-01-02T15:04:05Z"),
+
+		TrackingParams: org.TrackingParamList(),
+		SlugStrategy:   org.SlugStrategy,
+		SlugLength:     org.SlugLength,
+
+		ReservedNamespaces: org.ReservedNamespaceList(),
+
+		DeparturePolicy:  string(org.DeparturePolicy),
+		DepartureOwnerID: org.DepartureOwnerID,
 	})
 }
 
@@ -302,12 +337,73 @@ func (h *Handler) Update(c *gin.Context) {
 	if req.Name != "" {
 		org.Name = strings.TrimSpace(req.Name)
 	}
+	if req.SlugStrategy != "" {
+		org.SlugStrategy = req.SlugStrategy
+	}
+	if req.SlugLength != 0 {
+		org.SlugLength = req.SlugLength
+	}
+	trackingParamsChanged := false
+	if req.TrackingParams != nil {
+		params := make([]string, 0, len(*req.TrackingParams))
+		for _, p := range *req.TrackingParams {
+			if p = strings.TrimSpace(p); p != "" {
+				params = append(params, p)
+			}
+		}
+		if joined := strings.Join(params, ","); joined != org.TrackingParams {
+			org.TrackingParams = joined
+			trackingParamsChanged = true
+		}
+	}
+
+	if req.ReservedNamespaces != nil {
+		namespaces := make([]string, 0, len(*req.ReservedNamespaces))
+		for _, ns := range *req.ReservedNamespaces {
+			if ns = strings.ToLower(strings.TrimSpace(ns)); ns != "" {
+				namespaces = append(namespaces, ns)
+			}
+		}
+
+		// A namespace a group already owns has to be released before it can be reserved
+		if len(namespaces) > 0 {
+			var claimed models.Group
+			if err := h.db.Where("organization_id = ? AND namespace IN ?", org.ID, namespaces).First(&claimed).Error; err == nil {
+				c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Namespace '%s' is already owned by group '%s'", claimed.Namespace, claimed.Name)})
+				return
+			}
+		}
+		org.ReservedNamespaces = strings.Join(namespaces, ",")
+	}
+
+	if req.DeparturePolicy != "" {
+		org.DeparturePolicy = models.DeparturePolicy(req.DeparturePolicy)
+	}
+	if req.DepartureOwnerID != nil {
+		if *req.DepartureOwnerID == 0 {
+			org.DepartureOwnerID = nil
+		} else {
+			if err := h.db.Where("user_id = ? AND organization_id = ?", *req.DepartureOwnerID, org.ID).First(&models.OrganizationMembership{}).Error; err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Departure owner must be a member of the organization"})
+				return
+			}
+			org.DepartureOwnerID = req.DepartureOwnerID
+		}
+	}
 
 	if err := h.db.Save(&org).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update organization"})
 		return
 	}
 
+	// Duplicate detection depends on the tracking params, so existing links need rehashing
+	if trackingParamsChanged {
+		if err := urlcanon.RehashOrganization(h.db, org.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update link hashes"})
+			return
+		}
+	}
+
 	var memberCount int64
 	h.db.Model(&models.OrganizationMembership{}).Where("organization_id = ?", orgID).Count(&memberCount)
 
@@ -319,6 +415,15 @@ func (h *Handler) Update(c *gin.Context) {
 		Role:        string(membership.Role),
 		MemberCount: int(memberCount),
 		CreatedAt:   org.CreatedAt.Format("2006-01-02T15:04:05Z"),
+
+		TrackingParams: org.TrackingParamList(),
+		SlugStrategy:   org.SlugStrategy,
+		SlugLength:     org.SlugLength,
+
+		ReservedNamespaces: org.ReservedNamespaceList(),
+
+		DeparturePolicy:  string(org.DeparturePolicy),
+		DepartureOwnerID: org.DepartureOwnerID,
 	})
 }
 
@@ -551,14 +656,59 @@ func (h *Handler) UpdateMember(c *gin.Context) {
 	})
 }
 
+// PreviewRemoveMember shows what removing a member would do to their links (admin only)
+// @Summary Preview removing a member
+// @Description Show which of a member's links would be transferred, kept or deleted by the organization's departure policy if they were removed (requires admin role)
+// @Tags organizations
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} DepartureResponse
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 404 {object} map[string]string "Member not found"
+// @Security BearerAuth
+// @Router /organizations/{id}/members/{userId}/departure-preview [get]
+func (h *Handler) PreviewRemoveMember(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return
+	}
+	targetUserID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Check admin membership
+	if err := h.db.Where("user_id = ? AND organization_id = ? AND role = ?", userID, orgID, models.OrgRoleAdmin).First(&models.OrganizationMembership{}).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	if err := h.db.Where("organization_id = ? AND user_id = ?", orgID, targetUserID).First(&models.OrganizationMembership{}).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	outcomes, err := ownership.Preview(h.db, uint(targetUserID), uint(orgID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview links"})
+		return
+	}
+
+	c.JSON(http.StatusOK, DepartureResponse{Links: outcomes})
+}
+
 // RemoveMember removes a member from an organization (admin only)
 // @Summary Remove a member from an organization
-// @Description Remove a member from an organization (requires admin role)
+// @Description Remove a member from an organization (requires admin role). Their links in the organization are transferred, kept or deleted according to its departure policy.
 // @Tags organizations
 // @Produce json
 // @Param id path int true "Organization ID"
 // @Param userId path int true "User ID"
-// @Success 200 {object} map[string]string "Member removed"
+// @Success 200 {object} DepartureResponse "Member removed"
 // @Failure 403 {object} map[string]string "Admin access required"
 // @Security BearerAuth
 // @Router /organizations/{id}/members/{userId} [delete]
@@ -600,12 +750,21 @@ func (h *Handler) RemoveMember(c *gin.Context) {
 		}
 	}
 
-	if err := h.db.Delete(&membership).Error; err != nil {
+	var outcomes []ownership.Outcome
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&membership).Error; err != nil {
+			return err
+		}
+		var err error
+		outcomes, err = ownership.Apply(tx, membership.UserID, membership.OrganizationID)
+		return err
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove member"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+	c.JSON(http.StatusOK, DepartureResponse{Message: "Member removed", Links: outcomes})
 }
 
 // RegisterRoutes registers organization routes
@@ -623,4 +782,5 @@ func (h *Handler) RegisterMemberRoutes(rg *gin.RouterGroup) {
 	rg.POST("/:id/members", h.AddMember)
 	rg.PUT("/:id/members/:userId", h.UpdateMember)
 	rg.DELETE("/:id/members/:userId", h.RemoveMember)
+	rg.GET("/:id/members/:userId/departure-preview", h.PreviewRemoveMember)
 }
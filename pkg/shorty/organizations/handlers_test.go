@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -58,6 +59,7 @@ func setupTestRouter(db *gorm.DB) *gin.Engine {
 	orgs.Use(auth.AuthMiddleware())
 	handler.RegisterRoutes(orgs)
 	handler.RegisterMemberRoutes(orgs)
+	handler.RegisterProtectedSlugRoutes(orgs)
 
 	return r
 }
@@ -226,6 +228,79 @@ func TestUpdateOrganization(t *testing.T) {
 	}
 }
 
+func TestUpdateOrganizationSlugSettings(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+
+	org := models.Organization{Name: "Test Org", Slug: "test-org"}
+	db.Create(&org)
+	db.Create(&models.OrganizationMembership{OrganizationID: org.ID, UserID: user.ID, Role: models.OrgRoleAdmin})
+
+	update := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", "/organizations/1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := update(`{"slug_strategy": "words", "slug_length": 6}`)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var response OrgResponse
+	json.Unmarshal(resp.Body.Bytes(), &response)
+	if response.SlugStrategy != "words" || response.SlugLength != 6 {
+		t.Errorf("Expected words strategy with length 6, got %s/%d", response.SlugStrategy, response.SlugLength)
+	}
+
+	if resp := update(`{"slug_strategy": "emoji"}`); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for unknown strategy, got %d", resp.Code)
+	}
+	if resp := update(`{"slug_length": 2}`); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for too short length, got %d", resp.Code)
+	}
+}
+
+func TestUpdateOrganizationReservedNamespaces(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+
+	org := models.Organization{Name: "Test Org", Slug: "test-org"}
+	db.Create(&org)
+	db.Create(&models.OrganizationMembership{OrganizationID: org.ID, UserID: user.ID, Role: models.OrgRoleAdmin})
+	db.Create(&models.Group{OrganizationID: org.ID, Name: "Wiki", Namespace: "wiki"})
+
+	update := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", "/organizations/1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := update(`{"reserved_namespaces": ["Go", " docs "]}`)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var response OrgResponse
+	json.Unmarshal(resp.Body.Bytes(), &response)
+	if !reflect.DeepEqual(response.ReservedNamespaces, []string{"go", "docs"}) {
+		t.Errorf("Expected reserved namespaces [go docs], got %v", response.ReservedNamespaces)
+	}
+
+	// Namespaces already owned by a group can't be reserved
+	if resp := update(`{"reserved_namespaces": ["go", "wiki"]}`); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", resp.Code)
+	}
+}
+
 func TestDeleteOrganization(t *testing.T) {
 	db := setupTestDB(t)
 	router := setupTestRouter(db)
@@ -375,6 +450,64 @@ func TestRemoveMember(t *testing.T) {
 	}
 }
 
+func TestRemoveMemberAppliesDeparturePolicy(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	admin := createTestUser(t, db, "admin@example.com")
+	member := createTestUser(t, db, "member@example.com")
+	createTestUser(t, db, "outsider@example.com") // User 3 isn't a member
+
+	org := models.Organization{Name: "Test Org", Slug: "test-org"}
+	db.Create(&org)
+	db.Create(&models.OrganizationMembership{OrganizationID: org.ID, UserID: admin.ID, Role: models.OrgRoleAdmin})
+	db.Create(&models.OrganizationMembership{OrganizationID: org.ID, UserID: member.ID, Role: models.OrgRoleMember})
+	group := models.Group{OrganizationID: org.ID, Name: "Team"}
+	db.Create(&group)
+	db.Create(&models.Link{OrganizationID: org.ID, GroupID: group.ID, CreatedByID: member.ID, Slug: "team-docs", URL: "https://example.com"})
+	db.Create(&models.Link{OrganizationID: 99, GroupID: 99, CreatedByID: member.ID, Slug: "elsewhere", URL: "https://example.com"})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(admin))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// The designated owner must belong to the organization
+	if resp := do("PUT", "/organizations/1", `{"departure_owner_id": 3}`); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.Code)
+	}
+	resp := do("PUT", "/organizations/1", `{"departure_policy": "transfer", "departure_owner_id": 1}`)
+	var updated OrgResponse
+	json.Unmarshal(resp.Body.Bytes(), &updated)
+	if updated.DeparturePolicy != "transfer" || updated.DepartureOwnerID == nil || *updated.DepartureOwnerID != admin.ID {
+		t.Errorf("Expected transfers to the admin, got %+v", updated)
+	}
+
+	// Previewing only covers this organization's links
+	resp = do("GET", "/organizations/1/members/2/departure-preview", "")
+	var preview DepartureResponse
+	json.Unmarshal(resp.Body.Bytes(), &preview)
+	if len(preview.Links) != 1 || preview.Links[0].Slug != "team-docs" || preview.Links[0].NewOwnerID != admin.ID {
+		t.Errorf("Expected team-docs to be transferred to the admin, got %+v", preview.Links)
+	}
+	if resp := do("GET", "/organizations/1/members/3/departure-preview", ""); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a non-member, got %d", resp.Code)
+	}
+
+	if resp := do("DELETE", "/organizations/1/members/2", ""); resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var moved, untouched models.Link
+	db.Where("slug = ?", "team-docs").First(&moved)
+	db.Where("slug = ?", "elsewhere").First(&untouched)
+	if moved.CreatedByID != admin.ID || untouched.CreatedByID != member.ID {
+		t.Errorf("Expected only the org's link to move, got creators %d and %d", moved.CreatedByID, untouched.CreatedByID)
+	}
+}
+
 func TestCannotRemoveOnlyAdmin(t *testing.T) {
 	db := setupTestDB(t)
 	router := setupTestRouter(db)
@@ -439,3 +572,77 @@ func TestSlugValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestProtectedSlugs(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	admin := createTestUser(t, db, "admin@example.com")
+	member := createTestUser(t, db, "member@example.com")
+	outsider := createTestUser(t, db, "outsider@example.com")
+
+	org := models.Organization{Name: "Test Org", Slug: "test-org"}
+	db.Create(&org)
+	db.Create(&models.OrganizationMembership{OrganizationID: org.ID, UserID: admin.ID, Role: models.OrgRoleAdmin})
+	db.Create(&models.OrganizationMembership{OrganizationID: org.ID, UserID: member.ID, Role: models.OrgRoleMember})
+
+	do := func(user models.User, method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Only admins can protect slugs
+	if resp := do(member, "POST", "/organizations/1/protected-slugs", `{"pattern": "security*"}`); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+
+	// Approvers must belong to the organization
+	if resp := do(admin, "POST", "/organizations/1/protected-slugs", `{"pattern": "security*", "approver_ids": [3]}`); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.Code)
+	}
+	if resp := do(admin, "POST", "/organizations/1/protected-slugs", `{"pattern": "security["}`); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a malformed pattern, got %d", resp.Code)
+	}
+
+	resp := do(admin, "POST", "/organizations/1/protected-slugs", `{"pattern": "Security*", "approver_ids": [2]}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var rule ProtectedSlugResponse
+	json.Unmarshal(resp.Body.Bytes(), &rule)
+	if rule.Pattern != "security*" || len(rule.Approvers) != 1 || rule.Approvers[0].UserID != member.ID {
+		t.Errorf("Expected pattern 'security*' approved by the member, got %+v", rule)
+	}
+
+	// Members can see which slugs are protected
+	resp = do(member, "GET", "/organizations/1/protected-slugs", "")
+	var rules []ProtectedSlugResponse
+	json.Unmarshal(resp.Body.Bytes(), &rules)
+	if len(rules) != 1 {
+		t.Errorf("Expected 1 protected slug, got %d", len(rules))
+	}
+	if resp := do(outsider, "GET", "/organizations/1/protected-slugs", ""); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+
+	// Clearing the approvers hands approval back to org admins
+	resp = do(admin, "PUT", "/organizations/1/protected-slugs/1", `{"pattern": "security/*"}`)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var stored models.ProtectedSlug
+	db.Preload("Approvers").First(&stored, rule.ID)
+	if stored.Pattern != "security/*" || len(stored.Approvers) != 0 {
+		t.Errorf("Expected pattern 'security/*' with no approvers, got %q with %d", stored.Pattern, len(stored.Approvers))
+	}
+
+	if resp := do(admin, "DELETE", "/organizations/1/protected-slugs/1", ""); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.Code)
+	}
+	if resp := do(admin, "DELETE", "/organizations/1/protected-slugs/1", ""); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,265 @@
+package organizations
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+)
+
+// ProtectedSlugRequest represents the request to create or update a protected slug
+type ProtectedSlugRequest struct {
+	Pattern     string `json:"pattern" binding:"required,min=1,max=255"` // A slug, or a pattern such as "security*"
+	ApproverIDs []uint `json:"approver_ids" binding:"max=50"`            // Org members who approve changes; org admins if empty
+}
+
+// ProtectedSlugResponse represents a protected slug in API responses
+type ProtectedSlugResponse struct {
+	ID          uint             `json:"id"`
+	Pattern     string           `json:"pattern"`
+	Approvers   []MemberResponse `json:"approvers"`
+	CreatedByID uint             `json:"created_by_id"`
+	CreatedAt   string           `json:"created_at"`
+}
+
+func protectedSlugToResponse(rule models.ProtectedSlug) ProtectedSlugResponse {
+	approvers := make([]MemberResponse, len(rule.Approvers))
+	for i, u := range rule.Approvers {
+		approvers[i] = MemberResponse{UserID: u.ID, Email: u.Email, Name: u.Name}
+	}
+	return ProtectedSlugResponse{
+		ID:          rule.ID,
+		Pattern:     rule.Pattern,
+		Approvers:   approvers,
+		CreatedByID: rule.CreatedByID,
+		CreatedAt:   rule.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// bindProtectedSlug validates a protected slug request and loads its approvers
+func (h *Handler) bindProtectedSlug(c *gin.Context, orgID uint64) (string, []models.User, bool) {
+	var req ProtectedSlugRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", nil, false
+	}
+
+	pattern := strings.ToLower(strings.TrimSpace(req.Pattern))
+	if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid slug pattern"})
+		return "", nil, false
+	}
+
+	var approvers []models.User
+	if len(req.ApproverIDs) > 0 {
+		if err := h.db.Where("id IN (?)",
+			h.db.Model(&models.OrganizationMembership{}).Select("user_id").Where("organization_id = ? AND user_id IN ?", orgID, req.ApproverIDs),
+		).Find(&approvers).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch approvers"})
+			return "", nil, false
+		}
+		if len(approvers) != len(uniqueIDs(req.ApproverIDs)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Approvers must be members of the organization"})
+			return "", nil, false
+		}
+	}
+
+	return pattern, approvers, true
+}
+
+func uniqueIDs(ids []uint) map[uint]bool {
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return seen
+}
+
+// ListProtectedSlugs returns an organization's protected slugs
+// @Summary List protected slugs
+// @Description Get the slugs and patterns whose links only change with approval
+// @Tags organizations
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {array} ProtectedSlugResponse
+// @Failure 404 {object} map[string]string "Organization not found"
+// @Security BearerAuth
+// @Router /organizations/{id}/protected-slugs [get]
+func (h *Handler) ListProtectedSlugs(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return
+	}
+
+	// Check membership
+	if err := h.db.Where("user_id = ? AND organization_id = ?", userID, orgID).First(&models.OrganizationMembership{}).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+		return
+	}
+
+	var rules []models.ProtectedSlug
+	if err := h.db.Preload("Approvers").Where("organization_id = ?", orgID).Order("pattern").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch protected slugs"})
+		return
+	}
+
+	responses := make([]ProtectedSlugResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = protectedSlugToResponse(rule)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// CreateProtectedSlug protects a slug or pattern (admin only)
+// @Summary Protect a slug
+// @Description Protect a slug or pattern so that creating or changing matching links needs approval (requires admin role)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param request body ProtectedSlugRequest true "Protected slug"
+// @Success 201 {object} ProtectedSlugResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Security BearerAuth
+// @Router /organizations/{id}/protected-slugs [post]
+func (h *Handler) CreateProtectedSlug(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return
+	}
+
+	// Check admin membership
+	if err := h.db.Where("user_id = ? AND organization_id = ? AND role = ?", userID, orgID, models.OrgRoleAdmin).First(&models.OrganizationMembership{}).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	pattern, approvers, ok := h.bindProtectedSlug(c, orgID)
+	if !ok {
+		return
+	}
+
+	rule := models.ProtectedSlug{
+		OrganizationID: uint(orgID),
+		Pattern:        pattern,
+		CreatedByID:    userID,
+		Approvers:      approvers,
+	}
+	if err := h.db.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to protect slug"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, protectedSlugToResponse(rule))
+}
+
+// UpdateProtectedSlug changes a protected slug's pattern or approvers (admin only)
+// @Summary Update a protected slug
+// @Description Change a protected slug's pattern or approvers (requires admin role)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param ruleId path int true "Protected slug ID"
+// @Param request body ProtectedSlugRequest true "Protected slug"
+// @Success 200 {object} ProtectedSlugResponse
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 404 {object} map[string]string "Protected slug not found"
+// @Security BearerAuth
+// @Router /organizations/{id}/protected-slugs/{ruleId} [put]
+func (h *Handler) UpdateProtectedSlug(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return
+	}
+
+	// Check admin membership
+	if err := h.db.Where("user_id = ? AND organization_id = ? AND role = ?", userID, orgID, models.OrgRoleAdmin).First(&models.OrganizationMembership{}).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var rule models.ProtectedSlug
+	if err := h.db.Where("id = ? AND organization_id = ?", c.Param("ruleId"), orgID).First(&rule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Protected slug not found"})
+		return
+	}
+
+	pattern, approvers, ok := h.bindProtectedSlug(c, orgID)
+	if !ok {
+		return
+	}
+
+	rule.Pattern = pattern
+	if err := h.db.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update protected slug"})
+		return
+	}
+	if err := h.db.Model(&rule).Association("Approvers").Replace(approvers); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update approvers"})
+		return
+	}
+	rule.Approvers = approvers
+
+	c.JSON(http.StatusOK, protectedSlugToResponse(rule))
+}
+
+// DeleteProtectedSlug removes a protected slug (admin only)
+// @Summary Remove a protected slug
+// @Description Stop protecting a slug or pattern. Pending change requests can still be reviewed. (requires admin role)
+// @Tags organizations
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param ruleId path int true "Protected slug ID"
+// @Success 200 {object} map[string]string "Protected slug removed"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 404 {object} map[string]string "Protected slug not found"
+// @Security BearerAuth
+// @Router /organizations/{id}/protected-slugs/{ruleId} [delete]
+func (h *Handler) DeleteProtectedSlug(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return
+	}
+
+	// Check admin membership
+	if err := h.db.Where("user_id = ? AND organization_id = ? AND role = ?", userID, orgID, models.OrgRoleAdmin).First(&models.OrganizationMembership{}).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	result := h.db.Where("id = ? AND organization_id = ?", c.Param("ruleId"), orgID).Delete(&models.ProtectedSlug{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove protected slug"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Protected slug not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Protected slug removed"})
+}
+
+// RegisterProtectedSlugRoutes registers protected slug management routes
+func (h *Handler) RegisterProtectedSlugRoutes(rg *gin.RouterGroup) {
+	rg.GET("/:id/protected-slugs", h.ListProtectedSlugs)
+	rg.POST("/:id/protected-slugs", h.CreateProtectedSlug)
+	rg.PUT("/:id/protected-slugs/:ruleId", h.UpdateProtectedSlug)
+	rg.DELETE("/:id/protected-slugs/:ruleId", h.DeleteProtectedSlug)
+}
@@ -0,0 +1,133 @@
+// Package ownership applies an organization's departure policy to the links
+// of a user who is deleted, deactivated or removed from the organization, so
+// that team links don't disappear when their creator leaves.
+package ownership
+
+import (
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// Outcome describes what happens to one of the departing user's links
+type Outcome struct {
+	LinkID         uint   `json:"link_id"`
+	Slug           string `json:"slug"`
+	OrganizationID uint   `json:"organization_id"`
+	GroupID        uint   `json:"group_id"`
+	Action         string `json:"action"`                 // transfer, keep or delete
+	NewOwnerID     uint   `json:"new_owner_id,omitempty"` // Set when the link is transferred
+}
+
+// Preview returns what the departure policy would do to a user's links.
+// orgID limits it to one organization; 0 covers every organization.
+func Preview(db *gorm.DB, userID, orgID uint) ([]Outcome, error) {
+	query := db.Where("created_by_id = ?", userID).Order("id")
+	if orgID != 0 {
+		query = query.Where("organization_id = ?", orgID)
+	}
+	var links []models.Link
+	if err := query.Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	orgs := make(map[uint]*models.Organization)
+	owners := make(map[uint]uint) // By group
+	outcomes := make([]Outcome, 0, len(links))
+	for _, link := range links {
+		org, ok := orgs[link.OrganizationID]
+		if !ok {
+			org = &models.Organization{}
+			if err := db.First(org, link.OrganizationID).Error; err != nil {
+				// Links in a deleted organization fall back to the default policy
+				org = &models.Organization{ID: link.OrganizationID, DeparturePolicy: models.DeparturePolicyTransfer}
+			}
+			orgs[link.OrganizationID] = org
+		}
+
+		outcome := Outcome{
+			LinkID:         link.ID,
+			Slug:           link.Slug,
+			OrganizationID: link.OrganizationID,
+			GroupID:        link.GroupID,
+			Action:         string(models.DeparturePolicyKeep),
+		}
+		switch org.DeparturePolicy {
+		case models.DeparturePolicyDelete:
+			outcome.Action = string(models.DeparturePolicyDelete)
+		case models.DeparturePolicyKeep:
+		default:
+			owner, ok := owners[link.GroupID]
+			if !ok {
+				var err error
+				if owner, err = newOwner(db, org, link.GroupID, userID); err != nil {
+					return nil, err
+				}
+				owners[link.GroupID] = owner
+			}
+			// Links with nobody to take them over stay where they are
+			if owner != 0 {
+				outcome.Action = string(models.DeparturePolicyTransfer)
+				outcome.NewOwnerID = owner
+			}
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	return outcomes, nil
+}
+
+// Apply applies the departure policy to a user's links and returns what was done.
+// orgID limits it to one organization; 0 covers every organization.
+func Apply(db *gorm.DB, userID, orgID uint) ([]Outcome, error) {
+	outcomes, err := Preview(db, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range outcomes {
+		switch models.DeparturePolicy(o.Action) {
+		case models.DeparturePolicyTransfer:
+			err = db.Model(&models.Link{}).Where("id = ?", o.LinkID).Update("created_by_id", o.NewOwnerID).Error
+		case models.DeparturePolicyDelete:
+			err = db.Delete(&models.Link{}, o.LinkID).Error
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return outcomes, nil
+}
+
+// newOwner picks who receives a departing user's links in a group: the
+// organization's designated owner if they are still an active member, or else
+// the longest-standing active admin of the group. It returns 0 if there is
+// nobody.
+func newOwner(db *gorm.DB, org *models.Organization, groupID, departingID uint) (uint, error) {
+	if id := org.DepartureOwnerID; id != nil && *id != departingID {
+		var count int64
+		if err := db.Model(&models.OrganizationMembership{}).
+			Joins("JOIN users ON users.id = organization_memberships.user_id AND users.deleted_at IS NULL").
+			Where("organization_memberships.organization_id = ? AND organization_memberships.user_id = ? AND users.active = ?", org.ID, *id, true).
+			Count(&count).Error; err != nil {
+			return 0, err
+		}
+		if count > 0 {
+			return *id, nil
+		}
+	}
+
+	var admins []uint
+	if err := db.Model(&models.GroupMembership{}).
+		Joins("JOIN users ON users.id = group_memberships.user_id AND users.deleted_at IS NULL").
+		Where("group_memberships.group_id = ? AND group_memberships.role = ? AND group_memberships.user_id != ? AND users.active = ?", groupID, models.GroupRoleAdmin, departingID, true).
+		Order("group_memberships.created_at, group_memberships.id").
+		Limit(1).
+		Pluck("group_memberships.user_id", &admins).Error; err != nil {
+		return 0, err
+	}
+	if len(admins) == 0 {
+		return 0, nil
+	}
+	return admins[0], nil
+}
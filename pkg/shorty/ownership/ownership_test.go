@@ -0,0 +1,125 @@
+package ownership
+
+import (
+	"testing"
+
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	models.AutoMigrate(db)
+	return db
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, email string) models.User {
+	user := models.User{Email: email, Name: "Test User", SystemRole: models.SystemRoleUser}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	return user
+}
+
+func TestPreviewAndApply(t *testing.T) {
+	db := setupTestDB(t)
+	leaver := createTestUser(t, db, "leaver@example.com")
+	admin := createTestUser(t, db, "admin@example.com")
+	owner := createTestUser(t, db, "owner@example.com")
+	inactiveAdmin := createTestUser(t, db, "inactive@example.com")
+	db.Model(&inactiveAdmin).Update("active", false)
+
+	transferOrg := models.Organization{Name: "Transfer", Slug: "transfer"}
+	designatedOrg := models.Organization{Name: "Designated", Slug: "designated"}
+	deleteOrg := models.Organization{Name: "Delete", Slug: "delete", DeparturePolicy: models.DeparturePolicyDelete}
+	keepOrg := models.Organization{Name: "Keep", Slug: "keep", DeparturePolicy: models.DeparturePolicyKeep}
+	for _, org := range []*models.Organization{&transferOrg, &designatedOrg, &deleteOrg, &keepOrg} {
+		db.Create(org)
+	}
+	db.Create(&models.OrganizationMembership{OrganizationID: designatedOrg.ID, UserID: owner.ID})
+	db.Model(&designatedOrg).Update("departure_owner_id", owner.ID)
+
+	withAdmin := models.Group{OrganizationID: transferOrg.ID, Name: "With admin"}
+	orphaned := models.Group{OrganizationID: transferOrg.ID, Name: "Only an inactive admin"}
+	designated := models.Group{OrganizationID: designatedOrg.ID, Name: "Designated"}
+	deleted := models.Group{OrganizationID: deleteOrg.ID, Name: "Deleted"}
+	kept := models.Group{OrganizationID: keepOrg.ID, Name: "Kept"}
+	for _, g := range []*models.Group{&withAdmin, &orphaned, &designated, &deleted, &kept} {
+		db.Create(g)
+	}
+	db.Create(&models.GroupMembership{UserID: leaver.ID, GroupID: withAdmin.ID, Role: models.GroupRoleAdmin})
+	db.Create(&models.GroupMembership{UserID: admin.ID, GroupID: withAdmin.ID, Role: models.GroupRoleAdmin})
+	db.Create(&models.GroupMembership{UserID: inactiveAdmin.ID, GroupID: orphaned.ID, Role: models.GroupRoleAdmin})
+	db.Create(&models.GroupMembership{UserID: admin.ID, GroupID: designated.ID, Role: models.GroupRoleAdmin})
+
+	links := []models.Link{
+		{OrganizationID: transferOrg.ID, GroupID: withAdmin.ID, Slug: "to-admin"},
+		{OrganizationID: transferOrg.ID, GroupID: orphaned.ID, Slug: "nobody"},
+		{OrganizationID: designatedOrg.ID, GroupID: designated.ID, Slug: "to-owner"},
+		{OrganizationID: deleteOrg.ID, GroupID: deleted.ID, Slug: "deleted"},
+		{OrganizationID: keepOrg.ID, GroupID: kept.ID, Slug: "kept"},
+	}
+	for i := range links {
+		links[i].CreatedByID = leaver.ID
+		links[i].URL = "https://example.com"
+		db.Create(&links[i])
+	}
+
+	expected := []struct {
+		action   string
+		newOwner uint
+	}{
+		{"transfer", admin.ID},
+		{"keep", 0},
+		{"transfer", owner.ID},
+		{"delete", 0},
+		{"keep", 0},
+	}
+
+	outcomes, err := Preview(db, leaver.ID, 0)
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	if len(outcomes) != len(expected) {
+		t.Fatalf("Expected %d outcomes, got %d", len(expected), len(outcomes))
+	}
+	for i, want := range expected {
+		if outcomes[i].Action != want.action || outcomes[i].NewOwnerID != want.newOwner {
+			t.Errorf("%s: expected %s to %d, got %s to %d", outcomes[i].Slug, want.action, want.newOwner, outcomes[i].Action, outcomes[i].NewOwnerID)
+		}
+	}
+
+	// Previewing changes nothing
+	var count int64
+	db.Model(&models.Link{}).Where("created_by_id = ?", leaver.ID).Count(&count)
+	if count != 5 {
+		t.Errorf("Expected preview to leave all 5 links, got %d", count)
+	}
+
+	// Scoped to one organization
+	if scoped, _ := Preview(db, leaver.ID, deleteOrg.ID); len(scoped) != 1 || scoped[0].Slug != "deleted" {
+		t.Errorf("Expected only the delete org's link, got %+v", scoped)
+	}
+
+	if _, err := Apply(db, leaver.ID, 0); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	var toAdmin, toOwner models.Link
+	db.First(&toAdmin, links[0].ID)
+	db.First(&toOwner, links[2].ID)
+	if toAdmin.CreatedByID != admin.ID || toOwner.CreatedByID != owner.ID {
+		t.Errorf("Expected links transferred to %d and %d, got %d and %d", admin.ID, owner.ID, toAdmin.CreatedByID, toOwner.CreatedByID)
+	}
+	if err := db.First(&models.Link{}, links[3].ID).Error; err == nil {
+		t.Error("Expected the link in the delete org to be deleted")
+	}
+	db.Model(&models.Link{}).Where("created_by_id = ?", leaver.ID).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected 2 links kept by the leaver, got %d", count)
+	}
+}
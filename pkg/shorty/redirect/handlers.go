@@ -3,6 +3,7 @@ package redirect
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/models"
@@ -20,11 +21,14 @@ func NewHandler(db *gorm.DB) *Handler {
 }
 
 // resolveOrgFromHost looks up an organization by the request's Host header.
-// If no matching domain is found, returns the global organization ID.
-// Returns 0 if neither can be found (shouldn't happen if DB is properly seeded).
 func (h *Handler) resolveOrgFromHost(c *gin.Context) uint {
-	host := c.Request.Host
+	return ResolveOrganization(h.db, c.Request.Host)
+}
 
+// ResolveOrganization looks up an organization by host name (as found in the Host header).
+// If no matching domain is found, returns the global organization ID.
+// Returns 0 if neither can be found (shouldn't happen if DB is properly seeded).
+func ResolveOrganization(db *gorm.DB, host string) uint {
 	// Remove port if present (e.g., "localhost:8080" -> "localhost")
 	if colonIdx := strings.LastIndex(host, ":"); colonIdx != -1 {
 		host = host[:colonIdx]
@@ -32,13 +36,13 @@ func (h *Handler) resolveOrgFromHost(c *gin.Context) uint {
 
 	// Look up domain in OrganizationDomain table
 	var domain models.OrganizationDomain
-	if err := h.db.Where("domain = ?", host).First(&domain).Error; err == nil {
+	if err := db.Where("domain = ?", host).First(&domain).Error; err == nil {
 		return domain.OrganizationID
 	}
 
 	// Fall back to global organization
 	var globalOrg models.Organization
-	if err := h.db.Where("is_global = ?", true).First(&globalOrg).Error; err == nil {
+	if err := db.Where("is_global = ?", true).First(&globalOrg).Error; err == nil {
 		return globalOrg.ID
 	}
 
@@ -50,9 +54,18 @@ func (h *Handler) resolveOrgFromHost(c *gin.Context) uint {
 // Public links redirect without authentication.
 // Private links also redirect (the URL itself is not secret, just the metadata).
 // Click count is incremented for all redirects.
+// Aliases redirect to their target's URL and credit the click to the target.
+// Links in a group namespace are served at /namespace/name.
+// Page links render their Markdown content instead of redirecting. Private
+// pages are only shown to users who can see the link.
 func (h *Handler) Redirect(c *gin.Context) {
 	slug := c.Param("slug")
 
+	// Namespaced slugs span two path segments, e.g. /sre/runbook
+	if name := c.Param("name"); name != "" {
+		slug += "/" + name
+	}
+
 	// Resolve organization from Host header
 	orgID := h.resolveOrgFromHost(c)
 	if orgID == 0 {
@@ -67,11 +80,49 @@ func (h *Handler) Redirect(c *gin.Context) {
 		return
 	}
 
-	// Increment click count (fire and forget - don't block redirect on DB update)
+	// Expired links no longer redirect. An alias expires on its own, so it
+	// is checked before being followed, and the link it follows after.
+	now := time.Now()
+	if link.IsExpired(now) {
+		c.JSON(http.StatusGone, gin.H{"error": "Link has expired"})
+		return
+	}
+
+	// Aliases follow their target. If the target is gone, the alias's own URL is used.
+	clicked := link
+	if link.AliasOfID != nil {
+		var target models.Link
+		if err := h.db.Where("id = ? AND organization_id = ?", *link.AliasOfID, orgID).First(&target).Error; err == nil {
+			link = target
+		}
+	}
+	if link.IsExpired(now) {
+		c.JSON(http.StatusGone, gin.H{"error": "Link has expired"})
+		return
+	}
+
+	// Private pages show their content, so they need the same access as in the API
+	if link.IsPage() && !link.IsPublic && !h.canViewPage(c, &link) {
+		return
+	}
+
+	// Increment click count (fire and forget - don't block redirect on DB update).
+	// The alias that was followed is marked as used too, so it isn't reclaimed as stale.
 	go func() {
-		h.db.Model(&link).Update("click_count", gorm.Expr("click_count + 1"))
+		h.db.Model(&link).Updates(map[string]interface{}{
+			"click_count":     gorm.Expr("click_count + 1"),
+			"last_clicked_at": now,
+		})
+		if clicked.ID != link.ID {
+			h.db.Model(&clicked).Update("last_clicked_at", now)
+		}
 	}()
 
+	if link.IsPage() {
+		h.renderPage(c, &link)
+		return
+	}
+
 	// Redirect to the target URL
 	c.Redirect(http.StatusFound, link.URL)
 }
@@ -82,4 +133,5 @@ func (h *Handler) RegisterRoutes(r *gin.Engine) {
 	// Match any path that could be a slug
 	// This is registered last to avoid conflicts with /api, /health, etc.
 	r.GET("/:slug", h.Redirect)
+	r.GET("/:slug/:name", h.Redirect)
 }
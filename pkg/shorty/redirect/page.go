@@ -0,0 +1,82 @@
+package redirect
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/apikeys"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/markdown"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+)
+
+// pageTemplate wraps a page link's rendered Markdown
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #222; line-height: 1.5; }
+pre, code { background: #f4f4f4; border-radius: 3px; }
+pre { padding: 0.75rem; overflow-x: auto; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 0.25rem 0.5rem; }
+</style>
+</head>
+<body>
+{{.Body}}
+</body>
+</html>
+`))
+
+// canViewPage reports whether the request may see a private page. Pages are
+// subject to the same access checks as links in the API. Browsers send the
+// web UI's session cookie; other clients can send a bearer token or API key
+// as they would to the API. Public pages need no check.
+func (h *Handler) canViewPage(c *gin.Context, link *models.Link) bool {
+	userID, ok := h.pageViewer(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Sign in to view this page"})
+		return false
+	}
+	if !access.CanView(h.db, userID, link) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return false
+	}
+	return true
+}
+
+// pageViewer returns the user making the request, from the Authorization
+// header if one is sent and the session cookie otherwise
+func (h *Handler) pageViewer(c *gin.Context) (uint, bool) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return auth.SessionUserID(c)
+	}
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return 0, false
+	}
+	userID, err := apikeys.UserIDForToken(h.db, parts[1])
+	return userID, err == nil
+}
+
+// renderPage serves a page link's Markdown content as sanitized HTML
+func (h *Handler) renderPage(c *gin.Context, link *models.Link) {
+	title := link.Title
+	if title == "" {
+		title = link.Slug
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	pageTemplate.Execute(c.Writer, gin.H{
+		"Title": title,
+		"Body":  markdown.Render(link.Content),
+	})
+}
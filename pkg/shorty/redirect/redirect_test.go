@@ -1,12 +1,16 @@
 package redirect
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
 	"github.com/mikepea/shorty/pkg/shorty/models"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -107,6 +111,27 @@ func TestRedirectPrivateLink(t *testing.T) {
 	}
 }
 
+func TestRedirectNamespacedLink(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	globalOrg := createGlobalOrg(t, db)
+	createTestLink(t, db, globalOrg.ID, "ns-team/runbook", "https://runbook.example.com", false)
+
+	req, _ := http.NewRequest("GET", "/ns-team/runbook", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusFound {
+		t.Errorf("Expected status 302, got %d", resp.Code)
+	}
+
+	location := resp.Header().Get("Location")
+	if location != "https://runbook.example.com" {
+		t.Errorf("Expected Location 'https://runbook.example.com', got %s", location)
+	}
+}
+
 func TestRedirectNotFound(t *testing.T) {
 	db := setupTestDB(t)
 	router := setupTestRouter(db)
@@ -244,3 +269,181 @@ func TestRedirectDomainResolution(t *testing.T) {
 		t.Errorf("Expected Location 'https://acme.example.com', got %s", location)
 	}
 }
+
+func TestRedirectExpiredLink(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	globalOrg := createGlobalOrg(t, db)
+	link := createTestLink(t, db, globalOrg.ID, "expired-link", "https://example.com", true)
+
+	expiresAt := time.Now().Add(-time.Hour)
+	db.Model(&link).Update("expires_at", expiresAt)
+
+	req, _ := http.NewRequest("GET", "/expired-link", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusGone {
+		t.Errorf("Expected status 410, got %d", resp.Code)
+	}
+}
+
+func TestRedirectAliasFollowsTarget(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	globalOrg := createGlobalOrg(t, db)
+	target := createTestLink(t, db, globalOrg.ID, "alias-target", "https://example.com/dashboard", true)
+	alias := createTestLink(t, db, globalOrg.ID, "alias-source", "https://example.com/old-dashboard", true)
+	db.Model(&alias).Update("alias_of_id", target.ID)
+
+	req, _ := http.NewRequest("GET", "/alias-source", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusFound {
+		t.Errorf("Expected status 302, got %d", resp.Code)
+	}
+	if location := resp.Header().Get("Location"); location != "https://example.com/dashboard" {
+		t.Errorf("Expected redirect to the target's URL, got %s", location)
+	}
+
+	// Once the target is gone the alias falls back to its own URL
+	db.Delete(&target)
+
+	req, _ = http.NewRequest("GET", "/alias-source", nil)
+	resp = httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	if location := resp.Header().Get("Location"); location != "https://example.com/old-dashboard" {
+		t.Errorf("Expected redirect to the alias's own URL, got %s", location)
+	}
+}
+
+func TestRedirectExpiredAlias(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	globalOrg := createGlobalOrg(t, db)
+	target := createTestLink(t, db, globalOrg.ID, "live-target", "https://example.com/dashboard", true)
+	alias := createTestLink(t, db, globalOrg.ID, "expired-alias", "https://example.com/old-dashboard", true)
+	db.Model(&alias).Updates(map[string]interface{}{"alias_of_id": target.ID, "expires_at": time.Now().Add(-time.Hour)})
+
+	req, _ := http.NewRequest("GET", "/expired-alias", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusGone {
+		t.Errorf("Expected status 410 for an expired alias, got %d", resp.Code)
+	}
+
+	// The target itself still redirects
+	req, _ = http.NewRequest("GET", "/live-target", nil)
+	resp = httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusFound {
+		t.Errorf("Expected status 302 for the target, got %d", resp.Code)
+	}
+}
+
+func TestPageLinks(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	globalOrg := createGlobalOrg(t, db)
+
+	member := models.User{Email: "page-member@example.com", Name: "Member", SystemRole: models.SystemRoleUser}
+	outsider := models.User{Email: "page-outsider@example.com", Name: "Outsider", SystemRole: models.SystemRoleUser}
+	db.Create(&member)
+	db.Create(&outsider)
+	group := models.Group{Name: "On-call", OrganizationID: globalOrg.ID}
+	db.Create(&group)
+	db.Create(&models.GroupMembership{UserID: member.ID, GroupID: group.ID, Role: models.GroupRoleMember})
+
+	content := "# Cheat sheet\n\n<script>alert(1)</script>\n\n- [Dashboards](https://example.com/dash)\n"
+	for _, page := range []models.Link{
+		{Slug: "public-cheatsheet", IsPublic: true},
+		{Slug: "private-cheatsheet"},
+	} {
+		page.OrganizationID = globalOrg.ID
+		page.GroupID = group.ID
+		page.CreatedByID = member.ID
+		page.Kind = models.LinkKindPage
+		page.Content = content
+		db.Create(&page)
+	}
+
+	get := func(slug string, user *models.User) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/"+slug, nil)
+		if user != nil {
+			token, _ := auth.GenerateToken(user.ID, user.Email, string(user.SystemRole))
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := get("public-cheatsheet", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.Code)
+	}
+	body := resp.Body.String()
+	if !strings.Contains(body, "<h1 id=\"cheat-sheet\">Cheat sheet</h1>") || !strings.Contains(body, "https://example.com/dash") {
+		t.Errorf("Expected the rendered page, got %s", body)
+	}
+	if strings.Contains(body, "<script>") {
+		t.Errorf("Expected raw HTML to be removed, got %s", body)
+	}
+
+	// Private pages need the same access as the link
+	if resp := get("private-cheatsheet", nil); resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", resp.Code)
+	}
+	if resp := get("private-cheatsheet", &outsider); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+	if resp := get("private-cheatsheet", &member); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.Code)
+	}
+
+	// The web UI's session cookie works too, as browsers following a link
+	// can't send an Authorization header
+	token, _ := auth.GenerateToken(member.ID, member.Email, string(member.SystemRole))
+	req, _ := http.NewRequest("GET", "/private-cheatsheet", nil)
+	req.AddCookie(&http.Cookie{Name: auth.SessionCookie, Value: token})
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with the session cookie, got %d", resp.Code)
+	}
+	outsiderToken, _ := auth.GenerateToken(outsider.ID, outsider.Email, string(outsider.SystemRole))
+	req, _ = http.NewRequest("GET", "/private-cheatsheet", nil)
+	req.AddCookie(&http.Cookie{Name: auth.SessionCookie, Value: outsiderToken})
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an outsider's session, got %d", resp.Code)
+	}
+	req, _ = http.NewRequest("GET", "/private-cheatsheet", nil)
+	req.AddCookie(&http.Cookie{Name: auth.SessionCookie, Value: "not-a-token"})
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for an invalid session, got %d", resp.Code)
+	}
+
+	// API keys work as they do in the API
+	hash := sha256.Sum256([]byte("cheatsheetkey"))
+	db.Create(&models.APIKey{UserID: member.ID, KeyHash: hex.EncodeToString(hash[:]), KeyPrefix: "cheatshe", CreatedByID: member.ID})
+	req, _ = http.NewRequest("GET", "/private-cheatsheet", nil)
+	req.Header.Set("Authorization", "Bearer cheatsheetkey")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with an API key, got %d", resp.Code)
+	}
+}
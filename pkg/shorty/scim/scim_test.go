@@ -3,6 +3,7 @@ package scim
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -219,6 +220,40 @@ func TestPatchUserActive(t *testing.T) {
 	}
 }
 
+func TestDeactivateUserAppliesDeparturePolicy(t *testing.T) {
+	db := setupTestDB(t)
+	r := setupTestRouter()
+	h := NewUserHandler(db, "http://localhost:8080")
+
+	leaver := createTestUser(t, db, "leaver@test.com", "Leaver")
+	lead := createTestUser(t, db, "lead@test.com", "Team Lead")
+	group := createTestGroup(t, db, "Team")
+	db.Create(&models.GroupMembership{UserID: lead.ID, GroupID: group.ID, Role: models.GroupRoleAdmin})
+	link := models.Link{GroupID: group.ID, CreatedByID: leaver.ID, Slug: "team-docs", URL: "https://example.com"}
+	db.Create(&link)
+
+	r.PATCH("/scim/v2/Users/:id", h.PatchUser)
+
+	patch := PatchOp{
+		Schemas:    []string{SchemaPatchOp},
+		Operations: []PatchOperation{{Op: "replace", Path: "active", Value: false}},
+	}
+	jsonBody, _ := json.Marshal(patch)
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("/scim/v2/Users/%d", leaver.ID), bytes.NewReader(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var reloaded models.Link
+	db.First(&reloaded, link.ID)
+	if reloaded.CreatedByID != lead.ID {
+		t.Errorf("Expected the link to be transferred to the group admin, got creator %d", reloaded.CreatedByID)
+	}
+}
+
 func TestDeleteUser(t *testing.T) {
 	db := setupTestDB(t)
 	r := setupTestRouter()
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/ownership"
 	"gorm.io/gorm"
 )
 
@@ -323,13 +324,14 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	user.GivenName = req.Name.GivenName
 	user.FamilyName = req.Name.FamilyName
 
+	wasActive := user.Active
 	if req.Active != nil {
 		user.Active = *req.Active
 	}
 
 	user.UpdatedAt = time.Now()
 
-	if err := h.db.Save(&user).Error; err != nil {
+	if err := h.saveUser(&user, wasActive); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Schemas: []string{SchemaError},
 			Detail:  "Failed to update user",
@@ -373,6 +375,7 @@ func (h *UserHandler) PatchUser(c *gin.Context) {
 		return
 	}
 
+	wasActive := user.Active
 	for _, op := range patch.Operations {
 		switch strings.ToLower(op.Op) {
 		case "replace":
@@ -386,7 +389,7 @@ func (h *UserHandler) PatchUser(c *gin.Context) {
 
 	user.UpdatedAt = time.Now()
 
-	if err := h.db.Save(&user).Error; err != nil {
+	if err := h.saveUser(&user, wasActive); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Schemas: []string{SchemaError},
 			Detail:  "Failed to update user",
@@ -398,6 +401,21 @@ func (h *UserHandler) PatchUser(c *gin.Context) {
 	c.JSON(http.StatusOK, h.userToSCIM(&user))
 }
 
+// saveUser saves a user. Deactivating a user applies their organizations'
+// departure policy to their links.
+func (h *UserHandler) saveUser(user *models.User, wasActive bool) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		if wasActive && !user.Active {
+			_, err := ownership.Apply(tx, user.ID, 0)
+			return err
+		}
+		return nil
+	})
+}
+
 func (h *UserHandler) applyReplaceOp(user *models.User, op PatchOperation) {
 	path := strings.ToLower(op.Path)
 
@@ -494,7 +512,9 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		tx.Where("user_id = ?", user.ID).Delete(&models.APIKey{})
 		tx.Where("user_id = ?", user.ID).Delete(&models.GroupMembership{})
 		tx.Where("user_id = ?", user.ID).Delete(&models.OIDCIdentity{})
-		tx.Where("created_by_id = ?", user.ID).Delete(&models.Link{})
+		if _, err := ownership.Apply(tx, user.ID, 0); err != nil {
+			return err
+		}
 		return tx.Delete(&user).Error
 	})
 
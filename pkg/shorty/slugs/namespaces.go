@@ -0,0 +1,66 @@
+package slugs
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// MaxNamespaceLength is the longest namespace a group can claim
+const MaxNamespaceLength = 30
+
+var namespaceRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// reservedNamespaces can't be claimed by any group because they are the first
+// path segment of application routes, e.g. /c/:slug for public collections and
+// /t/:tag and /g/:slug for public tag and group pages
+var reservedNamespaces = []string{"c", "t", "g", "swagger", "scim", "assets", "links", "groups"}
+
+// SplitNamespace splits a slug of the form "namespace/name". Slugs without a
+// namespace return an empty namespace and the slug unchanged.
+func SplitNamespace(slug string) (namespace, name string) {
+	if ns, rest, ok := strings.Cut(slug, "/"); ok {
+		return ns, rest
+	}
+	return "", slug
+}
+
+// IsValidNamespace reports whether a namespace is well-formed: lowercase letters,
+// numbers, hyphens and underscores, starting with a letter or number
+func IsValidNamespace(namespace string) bool {
+	return len(namespace) <= MaxNamespaceLength && namespaceRegex.MatchString(namespace)
+}
+
+// IsReservedNamespace reports whether a namespace clashes with application routes
+func IsReservedNamespace(namespace string) bool {
+	if IsReserved(namespace) {
+		return true
+	}
+	for _, r := range reservedNamespaces {
+		if strings.EqualFold(namespace, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReservedClaims finds groups holding a namespace that was reserved after they
+// claimed it, e.g. "t" or "g" before tag and group pages existed. Their links
+// are hidden behind the application routes until the group moves to another
+// namespace, so the server reports them at startup.
+func ReservedClaims(db *gorm.DB) ([]models.Group, error) {
+	var groups []models.Group
+	if err := db.Where("namespace <> ''").Order("id").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+
+	var claims []models.Group
+	for _, group := range groups {
+		if IsReservedNamespace(group.Namespace) {
+			claims = append(claims, group)
+		}
+	}
+	return claims, nil
+}
@@ -0,0 +1,315 @@
+// Package slugs generates unique link slugs within an organization.
+//
+// Four strategies are supported and can be chosen per organization:
+//
+//   - random: crypto-random base62 (the default)
+//   - words: human-readable adjective-noun pairs, e.g. "brave-otter"
+//   - title: derived from the link title, e.g. "quarterly-report"
+//   - sequential: an incrementing per-organization counter in base36
+//
+// Candidates are checked against existing slugs (including soft-deleted links,
+// which still hold their slug in the unique index). Because another request can
+// claim a slug between the check and the insert, CreateLink retries the insert
+// with a fresh candidate and a short backoff when that happens.
+//
+// Groups can set a slug prefix, e.g. "sre-", which CreateLinkWithPrefix puts
+// in front of every candidate.
+package slugs
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// Slug strategies
+const (
+	StrategyRandom     = "random"
+	StrategyWords      = "words"
+	StrategyTitle      = "title"
+	StrategySequential = "sequential"
+)
+
+// Strategies lists the valid strategy names
+var Strategies = []string{StrategyRandom, StrategyWords, StrategyTitle, StrategySequential}
+
+// Length limits for random slugs
+const (
+	DefaultLength = 8
+	MinLength     = 4
+	MaxLength     = 50 // Matches the max length accepted for user-supplied slugs
+)
+
+const (
+	maxAttempts = 10
+	baseBackoff = 5 * time.Millisecond
+	maxBackoff  = 200 * time.Millisecond
+)
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+const base36 = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// ErrExhausted is returned when no unique slug could be found
+var ErrExhausted = errors.New("could not generate a unique slug")
+
+// reserved slugs clash with application routes
+var reserved = []string{"api", "health", "admin", "login", "logout", "register", "auth"}
+
+// IsReserved reports whether a slug is reserved for application routes
+func IsReserved(slug string) bool {
+	for _, r := range reserved {
+		if strings.EqualFold(slug, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Taken checks whether a slug exists in an organization, including soft-deleted
+// links which still hold their slug in the unique index
+func Taken(db *gorm.DB, orgID uint, slug string, excludeID uint) (bool, error) {
+	var count int64
+	query := db.Unscoped().Model(&models.Link{}).Where("organization_id = ? AND slug = ?", orgID, slug)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Random returns a crypto-random string of the given length from the base62 alphabet
+func Random(length int) string {
+	return randomString(base62, length)
+}
+
+func randomString(alphabet string, length int) string {
+	max := big.NewInt(int64(len(alphabet)))
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic("slugs: crypto/rand failed: " + err.Error())
+		}
+		b[i] = alphabet[n.Int64()]
+	}
+	return string(b)
+}
+
+// Generator creates unique slugs using each organization's configured strategy
+type Generator struct {
+	db    *gorm.DB
+	sleep func(time.Duration) // Replaced in tests
+}
+
+// NewGenerator creates a new slug generator
+func NewGenerator(db *gorm.DB) *Generator {
+	return &Generator{db: db, sleep: time.Sleep}
+}
+
+// settings returns the slug strategy and length configured for an organization
+func (g *Generator) settings(db *gorm.DB, orgID uint) (string, int) {
+	var org models.Organization
+	if err := db.First(&org, orgID).Error; err != nil {
+		return StrategyRandom, DefaultLength
+	}
+	strategy := org.SlugStrategy
+	if strategy == "" {
+		strategy = StrategyRandom
+	}
+	length := org.SlugLength
+	if length < MinLength || length > MaxLength {
+		length = DefaultLength
+	}
+	return strategy, length
+}
+
+// Generate returns a slug that is currently unused in the organization.
+// The title is only used by the title strategy.
+func (g *Generator) Generate(orgID uint, title string) (string, error) {
+	return g.generate(g.db, orgID, "", title, nil)
+}
+
+func (g *Generator) generate(db *gorm.DB, orgID uint, prefix, title string, skip func(string) bool) (string, error) {
+	strategy, length := g.settings(db, orgID)
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		slug, err := g.candidate(db, orgID, strategy, length, title, attempt)
+		if err != nil {
+			return "", err
+		}
+		if prefix != "" {
+			if len(prefix)+len(slug) > MaxLength {
+				slug = strings.TrimRight(slug[:MaxLength-len(prefix)], "-")
+			}
+			slug = prefix + slug
+		}
+		if IsReserved(slug) || (skip != nil && skip(slug)) {
+			continue
+		}
+		taken, err := Taken(db, orgID, slug, 0)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+	}
+
+	return "", ErrExhausted
+}
+
+// candidate returns the slug to try for the given attempt. Later attempts move
+// away from the preferred form (longer random slugs, numeric suffixes) so that
+// retries become increasingly unlikely to collide.
+func (g *Generator) candidate(db *gorm.DB, orgID uint, strategy string, length int, title string, attempt int) (string, error) {
+	switch strategy {
+	case StrategyWords:
+		slug := wordPair()
+		if attempt > 0 {
+			slug += "-" + randomString("0123456789", 1+attempt/2)
+		}
+		return slug, nil
+
+	case StrategyTitle:
+		base := FromTitle(title)
+		if base == "" {
+			break
+		}
+		var suffix string
+		switch {
+		case attempt == 0:
+			return base, nil
+		case attempt < 5:
+			suffix = "-" + strconv.Itoa(attempt+1)
+		default:
+			suffix = "-" + randomString(base36, 4)
+		}
+		if len(base)+len(suffix) > MaxLength {
+			base = strings.TrimRight(base[:MaxLength-len(suffix)], "-")
+		}
+		return base + suffix, nil
+
+	case StrategySequential:
+		n, err := nextSequence(db, orgID)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(n, 36), nil
+	}
+
+	// Random, and the fallback for titles with no usable characters.
+	// Every few collisions the slug grows by a character.
+	length += attempt / 3
+	if length > MaxLength {
+		length = MaxLength
+	}
+	return Random(length), nil
+}
+
+// nextSequence atomically increments and returns the organization's slug counter
+func nextSequence(db *gorm.DB, orgID uint) (uint64, error) {
+	var org models.Organization
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Organization{}).Where("id = ?", orgID).
+			UpdateColumn("slug_sequence", gorm.Expr("slug_sequence + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Select("slug_sequence").First(&org, orgID).Error
+	})
+	return org.SlugSequence, err
+}
+
+// FromTitle derives a slug from a title: lowercase ASCII letters and digits
+// separated by single hyphens, truncated at a word boundary to MaxLength
+func FromTitle(title string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		case r == '\'' || r == '’':
+			// Drop apostrophes so "Tom's notes" becomes "toms-notes"
+		default:
+			hyphen = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > MaxLength {
+		slug = slug[:MaxLength]
+		if i := strings.LastIndex(slug, "-"); i > 0 {
+			slug = slug[:i]
+		}
+	}
+	return slug
+}
+
+// CreateLink assigns a generated slug to link and inserts it. If the insert
+// fails because another request claimed the slug in the meantime, it backs off
+// briefly and retries with a new slug.
+func (g *Generator) CreateLink(link *models.Link) error {
+	return g.CreateLinkWithPrefix(link, "")
+}
+
+// CreateLinkWithPrefix is like CreateLink, but every generated slug starts
+// with the prefix
+func (g *Generator) CreateLinkWithPrefix(link *models.Link, prefix string) error {
+	return g.CreateLinkExcept(link, prefix, nil)
+}
+
+// CreateLinkExcept is like CreateLinkWithPrefix, but also passes over slugs
+// for which skip returns true, e.g. slugs the user can't create directly
+func (g *Generator) CreateLinkExcept(link *models.Link, prefix string, skip func(string) bool) error {
+	backoff := baseBackoff
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		slug, err := g.generate(g.db, link.OrganizationID, prefix, link.Title, skip)
+		if err != nil {
+			return err
+		}
+		link.Slug = slug
+
+		err = g.db.Create(link).Error
+		if err == nil {
+			return nil
+		}
+
+		// Only retry when the failure was a slug collision
+		taken, checkErr := Taken(g.db, link.OrganizationID, slug, 0)
+		if checkErr != nil || !taken {
+			return err
+		}
+		link.ID = 0
+
+		// Jittered exponential backoff spreads out competing requests
+		g.sleep(backoff/2 + time.Duration(randomInt(int64(backoff/2))))
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+	return ErrExhausted
+}
+
+func randomInt(max int64) int64 {
+	if max <= 0 {
+		return 0
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(max))
+	if err != nil {
+		return 0
+	}
+	return n.Int64()
+}
@@ -0,0 +1,237 @@
+package slugs
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	models.AutoMigrate(db)
+	return db
+}
+
+func createTestOrg(t *testing.T, db *gorm.DB, slug, strategy string, length int) models.Organization {
+	org := models.Organization{Name: "Test Org", Slug: slug, SlugStrategy: strategy, SlugLength: length}
+	if err := db.Create(&org).Error; err != nil {
+		t.Fatalf("Failed to create test organization: %v", err)
+	}
+	return org
+}
+
+func TestFromTitle(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{"Quarterly Report", "quarterly-report"},
+		{"  Q3 -- Planning (Draft)  ", "q3-planning-draft"},
+		{"Tom's Notes", "toms-notes"},
+		{"Café Menü", "caf-men"},
+		{"!!!", ""},
+		{strings.Repeat("word ", 20), strings.TrimSuffix(strings.Repeat("word-", 10), "-")},
+	}
+
+	for _, tt := range tests {
+		if got := FromTitle(tt.title); got != tt.expected {
+			t.Errorf("FromTitle(%q) = %q, want %q", tt.title, got, tt.expected)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	base62Regex := regexp.MustCompile(`^[0-9A-Za-z]{12}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		s := Random(12)
+		if !base62Regex.MatchString(s) {
+			t.Fatalf("Expected 12 base62 characters, got %q", s)
+		}
+		if seen[s] {
+			t.Fatalf("Random produced a repeat: %q", s)
+		}
+		seen[s] = true
+	}
+}
+
+func TestGenerateStrategies(t *testing.T) {
+	db := setupTestDB(t)
+	gen := NewGenerator(db)
+
+	tests := []struct {
+		strategy string
+		length   int
+		title    string
+		pattern  string
+	}{
+		{StrategyRandom, 0, "", `^[0-9A-Za-z]{8}$`},
+		{StrategyRandom, 12, "", `^[0-9A-Za-z]{12}$`},
+		{StrategyWords, 0, "", `^[a-z]+-[a-z]+$`},
+		{StrategyTitle, 0, "Team Runbook", `^team-runbook$`},
+		{StrategyTitle, 0, "", `^[0-9A-Za-z]{8}$`}, // No usable title falls back to random
+		{StrategySequential, 0, "", `^1$`},
+	}
+
+	for i, tt := range tests {
+		org := createTestOrg(t, db, "org-"+strconv.Itoa(i), tt.strategy, tt.length)
+
+		slug, err := gen.Generate(org.ID, tt.title)
+		if err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.strategy, err)
+		}
+		if !regexp.MustCompile(tt.pattern).MatchString(slug) {
+			t.Errorf("%s: slug %q doesn't match %s", tt.strategy, slug, tt.pattern)
+		}
+	}
+}
+
+func TestGenerateTitleConflicts(t *testing.T) {
+	db := setupTestDB(t)
+	gen := NewGenerator(db)
+	org := createTestOrg(t, db, "test-org", StrategyTitle, 0)
+
+	db.Create(&models.Link{OrganizationID: org.ID, GroupID: 1, CreatedByID: 1, Slug: "runbook", URL: "https://example.com"})
+	deleted := models.Link{OrganizationID: org.ID, GroupID: 1, CreatedByID: 1, Slug: "runbook-2", URL: "https://example.com"}
+	db.Create(&deleted)
+	db.Delete(&deleted) // Soft-deleted links still hold their slug
+
+	slug, err := gen.Generate(org.ID, "Runbook")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if slug != "runbook-3" {
+		t.Errorf("Expected runbook-3, got %q", slug)
+	}
+}
+
+func TestGenerateSkipsReserved(t *testing.T) {
+	db := setupTestDB(t)
+	gen := NewGenerator(db)
+	org := createTestOrg(t, db, "test-org", StrategyTitle, 0)
+
+	slug, err := gen.Generate(org.ID, "Admin")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if slug != "admin-2" {
+		t.Errorf("Expected admin-2 for reserved title, got %q", slug)
+	}
+}
+
+func TestGenerateSequential(t *testing.T) {
+	db := setupTestDB(t)
+	gen := NewGenerator(db)
+	org := createTestOrg(t, db, "test-org", StrategySequential, 0)
+
+	// "2" is already taken by a user-chosen slug and is skipped
+	db.Create(&models.Link{OrganizationID: org.ID, GroupID: 1, CreatedByID: 1, Slug: "2", URL: "https://example.com"})
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		slug, err := gen.Generate(org.ID, "")
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		got = append(got, slug)
+	}
+
+	if strings.Join(got, ",") != "1,3,4" {
+		t.Errorf("Expected 1,3,4, got %v", got)
+	}
+}
+
+func TestCreateLink(t *testing.T) {
+	db := setupTestDB(t)
+	gen := NewGenerator(db)
+	org := createTestOrg(t, db, "test-org", StrategyTitle, 0)
+
+	for i := 0; i < 3; i++ {
+		link := models.Link{OrganizationID: org.ID, GroupID: 1, CreatedByID: 1, URL: "https://example.com", Title: "Weekly Sync"}
+		if err := gen.CreateLink(&link); err != nil {
+			t.Fatalf("CreateLink failed: %v", err)
+		}
+	}
+
+	var slugs []string
+	db.Model(&models.Link{}).Order("id").Pluck("slug", &slugs)
+	if strings.Join(slugs, ",") != "weekly-sync,weekly-sync-2,weekly-sync-3" {
+		t.Errorf("Unexpected slugs: %v", slugs)
+	}
+}
+
+func TestCreateLinkWithPrefix(t *testing.T) {
+	db := setupTestDB(t)
+	gen := NewGenerator(db)
+	org := createTestOrg(t, db, "test-org", StrategyTitle, 0)
+
+	for i := 0; i < 2; i++ {
+		link := models.Link{OrganizationID: org.ID, GroupID: 1, CreatedByID: 1, URL: "https://example.com", Title: "Weekly Sync"}
+		if err := gen.CreateLinkWithPrefix(&link, "sre-"); err != nil {
+			t.Fatalf("CreateLinkWithPrefix failed: %v", err)
+		}
+	}
+
+	var slugs []string
+	db.Model(&models.Link{}).Order("id").Pluck("slug", &slugs)
+	if strings.Join(slugs, ",") != "sre-weekly-sync,sre-weekly-sync-2" {
+		t.Errorf("Unexpected slugs: %v", slugs)
+	}
+}
+
+func TestSplitNamespace(t *testing.T) {
+	tests := []struct {
+		slug      string
+		namespace string
+		name      string
+	}{
+		{"runbook", "", "runbook"},
+		{"sre/runbook", "sre", "runbook"},
+		{"sre/a/b", "sre", "a/b"},
+	}
+
+	for _, tt := range tests {
+		namespace, name := SplitNamespace(tt.slug)
+		if namespace != tt.namespace || name != tt.name {
+			t.Errorf("SplitNamespace(%q) = %q, %q, want %q, %q", tt.slug, namespace, name, tt.namespace, tt.name)
+		}
+	}
+}
+
+func TestReservedClaims(t *testing.T) {
+	db := setupTestDB(t)
+	db.Create(&models.Group{Name: "SRE", Namespace: "sre"})
+	db.Create(&models.Group{Name: "Tools", Namespace: "T"})
+	db.Create(&models.Group{Name: "No namespace"})
+
+	claims, err := ReservedClaims(db)
+	if err != nil {
+		t.Fatalf("ReservedClaims() error = %v", err)
+	}
+	if len(claims) != 1 || claims[0].Name != "Tools" {
+		t.Errorf("Expected only the group holding t, got %+v", claims)
+	}
+}
+
+func TestCreateLinkExcept(t *testing.T) {
+	db := setupTestDB(t)
+	gen := NewGenerator(db)
+	org := createTestOrg(t, db, "test-org", StrategyTitle, 0)
+
+	link := models.Link{OrganizationID: org.ID, GroupID: 1, CreatedByID: 1, URL: "https://example.com", Title: "Weekly Sync"}
+	skip := func(slug string) bool { return slug == "weekly-sync" }
+	if err := gen.CreateLinkExcept(&link, "", skip); err != nil {
+		t.Fatalf("CreateLinkExcept failed: %v", err)
+	}
+	if link.Slug != "weekly-sync-2" {
+		t.Errorf("Expected weekly-sync-2, got %q", link.Slug)
+	}
+}
@@ -0,0 +1,30 @@
+package slugs
+
+// Word lists for the words strategy. Words are short, lowercase, unambiguous
+// when read aloud, and none of the pairs spell a reserved slug.
+var adjectives = []string{
+	"amber", "bold", "brave", "brisk", "calm", "clever", "cosmic", "crisp",
+	"daring", "eager", "early", "fancy", "fast", "fluffy", "gentle", "giant",
+	"golden", "grand", "happy", "hidden", "humble", "jolly", "keen", "kind",
+	"lively", "lucky", "mellow", "mighty", "misty", "noble", "odd", "olive",
+	"plucky", "polite", "proud", "quick", "quiet", "rapid", "rosy", "rusty",
+	"shiny", "silent", "silver", "sleepy", "snowy", "solid", "spicy", "steady",
+	"sunny", "swift", "tidy", "tiny", "upbeat", "vivid", "warm", "wild",
+	"windy", "wise", "witty", "young", "zany", "zesty", "breezy", "cheery",
+}
+
+var nouns = []string{
+	"badger", "beacon", "bison", "brook", "canyon", "cedar", "comet", "coral",
+	"crane", "delta", "dune", "eagle", "ember", "falcon", "fern", "fjord",
+	"forest", "fox", "garden", "glacier", "harbor", "hawk", "heron", "island",
+	"koala", "lagoon", "lantern", "lemur", "lynx", "maple", "meadow", "meteor",
+	"moose", "nebula", "orchid", "otter", "owl", "panda", "pebble", "pepper",
+	"pine", "planet", "quartz", "rabbit", "raven", "reef", "river", "robin",
+	"salmon", "spruce", "summit", "tiger", "tulip", "valley", "walrus", "willow",
+	"wombat", "yak", "zebra", "acorn", "bramble", "cactus", "dolphin", "gecko",
+}
+
+// wordPair returns a random adjective-noun pair, e.g. "brave-otter"
+func wordPair() string {
+	return adjectives[randomInt(int64(len(adjectives)))] + "-" + nouns[randomInt(int64(len(nouns)))]
+}
@@ -0,0 +1,115 @@
+package stale
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// Handler handles stale link report requests
+type Handler struct {
+	db          *gorm.DB
+	gracePeriod time.Duration
+}
+
+// NewHandler creates a new stale links handler
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db, gracePeriod: GracePeriodFromEnv()}
+}
+
+// StaleLinkResponse represents a stale link in the report
+type StaleLinkResponse struct {
+	LinkID        uint     `json:"link_id"`
+	Slug          string   `json:"slug"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	GroupID       uint     `json:"group_id"`
+	GroupName     string   `json:"group_name"`
+	CreatedByID   uint     `json:"created_by_id"`
+	CreatorEmail  string   `json:"creator_email,omitempty"` // Empty if the creator has been deleted
+	CreatorActive bool     `json:"creator_active"`
+	ClickCount    uint     `json:"click_count"`
+	LastClickedAt string   `json:"last_clicked_at,omitempty"`
+	FetchStatus   int      `json:"fetch_status"`
+	Score         int      `json:"score"`
+	Reasons       []string `json:"reasons"`
+	NotifiedAt    string   `json:"notified_at,omitempty"`   // When the group's admins were notified
+	ArchiveAfter  string   `json:"archive_after,omitempty"` // When the link will be archived if it is still stale
+	LinkCreatedAt string   `json:"link_created_at"`
+}
+
+func candidateToResponse(cand Candidate, gracePeriod time.Duration) StaleLinkResponse {
+	link := cand.Link
+	response := StaleLinkResponse{
+		LinkID:        link.ID,
+		Slug:          link.Slug,
+		URL:           link.URL,
+		Title:         link.Title,
+		GroupID:       link.GroupID,
+		GroupName:     link.Group.Name,
+		CreatedByID:   link.CreatedByID,
+		CreatorEmail:  link.CreatedBy.Email,
+		CreatorActive: link.CreatedBy.ID != 0 && link.CreatedBy.Active,
+		ClickCount:    link.ClickCount,
+		FetchStatus:   link.FetchStatus,
+		Score:         cand.Score,
+		Reasons:       cand.Reasons,
+		LinkCreatedAt: link.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if link.LastClickedAt != nil {
+		response.LastClickedAt = link.LastClickedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if link.StaleNotifiedAt != nil {
+		response.NotifiedAt = link.StaleNotifiedAt.Format("2006-01-02T15:04:05Z")
+		response.ArchiveAfter = link.StaleNotifiedAt.Add(gracePeriod).Format("2006-01-02T15:04:05Z")
+	}
+	return response
+}
+
+// Report lists an organization's stale links (admin only)
+// @Summary List stale links
+// @Description Get the organization's links that are candidates for archiving: links whose creator has left, that haven't been clicked for a year, or whose destination is broken. Scores of 50 or more are stale. (requires admin role)
+// @Tags organizations
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {array} StaleLinkResponse
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Security BearerAuth
+// @Router /organizations/{id}/stale-links [get]
+func (h *Handler) Report(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return
+	}
+
+	// Check admin membership
+	if err := h.db.Where("user_id = ? AND organization_id = ? AND role = ?", userID, orgID, models.OrgRoleAdmin).First(&models.OrganizationMembership{}).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	candidates, err := Candidates(h.db, uint(orgID), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stale links"})
+		return
+	}
+
+	responses := make([]StaleLinkResponse, len(candidates))
+	for i, cand := range candidates {
+		responses[i] = candidateToResponse(cand, h.gracePeriod)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// RegisterRoutes registers the stale link report on the organizations router group
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/:id/stale-links", h.Report)
+}
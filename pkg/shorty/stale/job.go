@@ -0,0 +1,179 @@
+package stale
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/mail"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockName is the JobLock that stops replicas running the job at once
+const lockName = "stale_links"
+
+// Job periodically notifies group admins about stale links and archives
+// links that are still stale once the grace period has passed
+type Job struct {
+	db          *gorm.DB
+	sender      mail.Sender
+	baseURL     string
+	holder      string // Identifies this instance in the job lock
+	GracePeriod time.Duration
+}
+
+// NewJob creates a stale link job. baseURL is used to link to slugs in notifications.
+func NewJob(db *gorm.DB, sender mail.Sender, baseURL string) *Job {
+	hostname, _ := os.Hostname()
+	return &Job{
+		db:          db,
+		sender:      sender,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		holder:      fmt.Sprintf("%s/%d", hostname, os.Getpid()),
+		GracePeriod: GracePeriodFromEnv(),
+	}
+}
+
+// Result summarizes a run
+type Result struct {
+	Notified int // Links newly found stale
+	Archived int // Links archived after the grace period
+	Cleared  int // Notified links that are no longer stale
+}
+
+// Run runs the job immediately and then at every interval until ctx is done.
+// When several instances share a database, only the one holding the job lock
+// runs it.
+func (j *Job) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := j.acquire(time.Now(), interval)
+		if err != nil {
+			log.Printf("Warning: Failed to acquire the stale link job lock: %v", err)
+		} else if ok {
+			if result, err := j.RunOnce(time.Now()); err != nil {
+				log.Printf("Warning: Stale link job failed: %v", err)
+			} else if result != (Result{}) {
+				log.Printf("Stale links: %d notified, %d archived, %d no longer stale", result.Notified, result.Archived, result.Cleared)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// acquire takes or renews the job lock. The lease outlasts the interval so
+// the holder keeps it between runs; if the holder stops, another instance
+// takes over once the lease lapses.
+func (j *Job) acquire(now time.Time, interval time.Duration) (bool, error) {
+	expires := now.Add(interval + interval/2)
+	if err := j.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.JobLock{Name: lockName, Holder: j.holder, ExpiresAt: expires}).Error; err != nil {
+		return false, err
+	}
+
+	result := j.db.Model(&models.JobLock{}).
+		Where("name = ? AND (holder = ? OR expires_at < ?)", lockName, j.holder, now).
+		Updates(map[string]interface{}{"holder": j.holder, "expires_at": expires})
+	return result.RowsAffected == 1, result.Error
+}
+
+// RunOnce assesses every link once
+func (j *Job) RunOnce(now time.Time) (Result, error) {
+	var result Result
+
+	candidates, err := Candidates(j.db, 0, now)
+	if err != nil {
+		return result, err
+	}
+
+	stale := make(map[uint]bool, len(candidates))
+	newlyStale := make(map[uint][]Candidate) // By group
+	for _, cand := range candidates {
+		link := cand.Link
+		stale[link.ID] = true
+
+		switch {
+		case link.StaleNotifiedAt == nil:
+			if err := j.db.Model(&link).Update("stale_notified_at", now).Error; err != nil {
+				return result, err
+			}
+			newlyStale[link.GroupID] = append(newlyStale[link.GroupID], cand)
+			result.Notified++
+		case now.Sub(*link.StaleNotifiedAt) >= j.GracePeriod:
+			if err := Archive(j.db, &link); err != nil {
+				return result, err
+			}
+			result.Archived++
+		}
+	}
+
+	// Links that were clicked, or whose creator was reactivated, since the
+	// notification start a fresh grace period if they go stale again
+	var notified []models.Link
+	if err := j.db.Where("stale_notified_at IS NOT NULL").Find(&notified).Error; err != nil {
+		return result, err
+	}
+	for _, link := range notified {
+		if stale[link.ID] {
+			continue
+		}
+		if err := j.db.Model(&link).Update("stale_notified_at", nil).Error; err != nil {
+			return result, err
+		}
+		result.Cleared++
+	}
+
+	groupIDs := make([]uint, 0, len(newlyStale))
+	for groupID := range newlyStale {
+		groupIDs = append(groupIDs, groupID)
+	}
+	sort.Slice(groupIDs, func(i, k int) bool { return groupIDs[i] < groupIDs[k] })
+	for _, groupID := range groupIDs {
+		if err := j.notify(groupID, newlyStale[groupID], now); err != nil {
+			log.Printf("Warning: Failed to notify admins of group %d about stale links: %v", groupID, err)
+		}
+	}
+
+	return result, nil
+}
+
+// notify emails the active admins of a group, including admins of the groups
+// above it, about links that have gone stale
+func (j *Job) notify(groupID uint, candidates []Candidate, now time.Time) error {
+	var to []string
+	if err := j.db.Model(&models.User{}).Where("id IN (?) AND active = ?", access.GroupAdmins(j.db, groupID), true).
+		Order("id").Pluck("email", &to).Error; err != nil {
+		return err
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "These links in %s look stale and will be archived on %s, releasing their slugs.\n",
+		candidates[0].Link.Group.Name, now.Add(j.GracePeriod).Format("2006-01-02"))
+	body.WriteString("Links that are used again before then are kept.\n\n")
+	for _, cand := range candidates {
+		fmt.Fprintf(&body, "  %s/%s -> %s (%s)\n", j.baseURL, cand.Link.Slug, cand.Link.URL, strings.Join(cand.Reasons, ", "))
+	}
+
+	return j.sender.Send(mail.Message{
+		To:      to,
+		Subject: fmt.Sprintf("%d stale links in %s", len(candidates), candidates[0].Link.Group.Name),
+		Body:    body.String(),
+	})
+}
@@ -0,0 +1,158 @@
+// Package stale finds links that are no longer used, notifies the admins of
+// their groups, and archives them after a grace period so their slugs can be
+// reused.
+package stale
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// Reasons a link is considered stale
+const (
+	ReasonCreatorInactive = "creator_inactive" // The creator has been deactivated or deleted, and the organization doesn't keep their links
+	ReasonNoClicks        = "no_clicks"        // No clicks within IdlePeriod
+	ReasonUnhealthy       = "unhealthy"        // The destination failed its last metadata fetch
+)
+
+// IdlePeriod is how long a link can go without a click before it is stale
+const IdlePeriod = 365 * 24 * time.Hour
+
+// DefaultGracePeriod is how long a stale link is kept after its group's admins
+// are notified, unless SHORTY_STALE_GRACE_DAYS is set
+const DefaultGracePeriod = 30 * 24 * time.Hour
+
+// Threshold is the score at which a link is stale. Either an inactive creator
+// or a year without clicks is enough on its own; a broken destination only
+// adds weight.
+const Threshold = 50
+
+var weights = map[string]int{
+	ReasonCreatorInactive: 50,
+	ReasonNoClicks:        50,
+	ReasonUnhealthy:       25,
+}
+
+// Assessment is a link's staleness score and the reasons behind it
+type Assessment struct {
+	Score   int
+	Reasons []string
+}
+
+// IsStale reports whether the score reaches Threshold
+func (a Assessment) IsStale() bool {
+	return a.Score >= Threshold
+}
+
+// Assess scores a link's staleness. The link's CreatedBy and Organization must
+// be preloaded; a creator that wasn't found has been deleted.
+func Assess(link *models.Link, now time.Time) Assessment {
+	var a Assessment
+	add := func(reason string) {
+		a.Score += weights[reason]
+		a.Reasons = append(a.Reasons, reason)
+	}
+
+	// Organizations that keep a departed user's links in place don't want
+	// them archived just because their creator left
+	creatorInactive := link.CreatedBy.ID == 0 || !link.CreatedBy.Active
+	if creatorInactive && link.Organization.DeparturePolicy != models.DeparturePolicyKeep {
+		add(ReasonCreatorInactive)
+	}
+
+	// Links clicked before click times were recorded have no LastClickedAt,
+	// so their idle time is unknown. Unclicked links are idle since they were
+	// added, not since CreatedAt, which imports set to the bookmark's date.
+	lastUsed := link.LastClickedAt
+	if lastUsed == nil && link.ClickCount == 0 {
+		lastUsed = link.TrackedSince
+		if lastUsed == nil {
+			lastUsed = &link.CreatedAt
+		}
+	}
+	if lastUsed != nil && now.Sub(*lastUsed) >= IdlePeriod {
+		add(ReasonNoClicks)
+	}
+
+	if link.FetchedAt != nil && (link.FetchStatus == 0 || link.FetchStatus >= 400) {
+		add(ReasonUnhealthy)
+	}
+
+	return a
+}
+
+// Candidate is a link together with its staleness assessment
+type Candidate struct {
+	Link models.Link
+	Assessment
+}
+
+// batchSize is how many links Candidates loads at a time
+const batchSize = 500
+
+// Candidates returns the stale links in an organization, or in every
+// organization if orgID is 0, including those already notified
+func Candidates(db *gorm.DB, orgID uint, now time.Time) ([]Candidate, error) {
+	// Only links with an inactive creator or a year without clicks can reach
+	// the threshold, so the rest are never loaded. Archived links are deleted.
+	cutoff := now.Add(-IdlePeriod)
+	query := db.Preload("CreatedBy").Preload("Group").Preload("Organization").
+		Where(db.Where("created_by_id NOT IN (?) AND organization_id NOT IN (?)",
+			db.Model(&models.User{}).Select("id").Where("active = ?", true),
+			db.Model(&models.Organization{}).Select("id").Where("departure_policy = ?", models.DeparturePolicyKeep)).
+			Or("last_clicked_at < ?", cutoff).
+			Or("last_clicked_at IS NULL AND click_count = 0 AND COALESCE(tracked_since, created_at) < ?", cutoff))
+	if orgID != 0 {
+		query = query.Where("organization_id = ?", orgID)
+	}
+
+	var candidates []Candidate
+	var links []models.Link
+	err := query.FindInBatches(&links, batchSize, func(tx *gorm.DB, batch int) error {
+		for _, link := range links {
+			if a := Assess(&link, now); a.IsStale() {
+				candidates = append(candidates, Candidate{Link: link, Assessment: a})
+			}
+		}
+		return nil
+	}).Error
+	return candidates, err
+}
+
+// Archive deletes a stale link and releases its slug for reuse. The original
+// slug is kept in ArchivedSlug.
+func Archive(db *gorm.DB, link *models.Link) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Soft-deleted links still hold their slug in the unique index, so it
+		// is swapped for one that can't clash with a valid slug
+		if err := tx.Model(link).Updates(map[string]interface{}{
+			"archived_slug": link.Slug,
+			"slug":          fmt.Sprintf("~archived-%d", link.ID),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(link).Error
+	})
+}
+
+// BackfillTrackedSince sets TrackedSince on links added before it was
+// recorded. The insert time isn't known, so the last update is used: it is
+// never earlier, so an imported bookmark's old CreatedAt can't make it stale.
+func BackfillTrackedSince(db *gorm.DB) error {
+	return db.Unscoped().Model(&models.Link{}).Where("tracked_since IS NULL").
+		UpdateColumn("tracked_since", gorm.Expr("updated_at")).Error
+}
+
+// GracePeriodFromEnv returns the grace period set by SHORTY_STALE_GRACE_DAYS,
+// or DefaultGracePeriod
+func GracePeriodFromEnv() time.Duration {
+	if days, err := strconv.Atoi(os.Getenv("SHORTY_STALE_GRACE_DAYS")); err == nil && days >= 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return DefaultGracePeriod
+}
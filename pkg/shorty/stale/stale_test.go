@@ -0,0 +1,333 @@
+package stale
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/importexport"
+	"github.com/mikepea/shorty/pkg/shorty/mail"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"github.com/mikepea/shorty/pkg/shorty/slugs"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	models.AutoMigrate(db)
+	return db
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, email string) models.User {
+	user := models.User{Email: email, Name: "Test User", SystemRole: models.SystemRoleUser}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	return user
+}
+
+func getAuthHeader(user models.User) string {
+	token, _ := auth.GenerateToken(user.ID, user.Email, string(user.SystemRole))
+	return "Bearer " + token
+}
+
+// recordingSender keeps the messages it is asked to send
+type recordingSender struct {
+	messages []mail.Message
+}
+
+func (s *recordingSender) Send(msg mail.Message) error {
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func TestAssess(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent := now.Add(-24 * time.Hour)
+	old := now.Add(-400 * 24 * time.Hour)
+	active := models.User{ID: 1, Active: true}
+	keep := models.Organization{ID: 1, DeparturePolicy: models.DeparturePolicyKeep}
+
+	tests := []struct {
+		name    string
+		link    models.Link
+		reasons []string
+		stale   bool
+	}{
+		{"recently clicked", models.Link{CreatedAt: old, ClickCount: 3, LastClickedAt: &recent, CreatedBy: active}, nil, false},
+		{"never clicked in a year", models.Link{CreatedAt: old, CreatedBy: active}, []string{ReasonNoClicks}, true},
+		{"new and unclicked", models.Link{CreatedAt: recent, CreatedBy: active}, nil, false},
+		{"imported old bookmark", models.Link{CreatedAt: old, TrackedSince: &recent, CreatedBy: active}, nil, false},
+		{"unclicked since it was added", models.Link{CreatedAt: recent, TrackedSince: &old, CreatedBy: active}, []string{ReasonNoClicks}, true},
+		{"clicked before click times were recorded", models.Link{CreatedAt: old, ClickCount: 5, CreatedBy: active}, nil, false},
+		{"deactivated creator", models.Link{CreatedAt: recent, CreatedBy: models.User{ID: 1}}, []string{ReasonCreatorInactive}, true},
+		{"deleted creator", models.Link{CreatedAt: recent}, []string{ReasonCreatorInactive}, true},
+		{"deactivated creator's link kept in place", models.Link{CreatedAt: recent, CreatedBy: models.User{ID: 1}, Organization: keep}, nil, false},
+		{"kept link never clicked in a year", models.Link{CreatedAt: old, CreatedBy: models.User{ID: 1}, Organization: keep}, []string{ReasonNoClicks}, true},
+		{"broken destination alone", models.Link{CreatedAt: recent, CreatedBy: active, FetchedAt: &recent, FetchStatus: 404}, []string{ReasonUnhealthy}, false},
+		{"broken and idle", models.Link{CreatedAt: old, CreatedBy: active, FetchedAt: &recent}, []string{ReasonNoClicks, ReasonUnhealthy}, true},
+	}
+
+	for _, tt := range tests {
+		a := Assess(&tt.link, now)
+		if !reflect.DeepEqual(a.Reasons, tt.reasons) {
+			t.Errorf("%s: expected reasons %v, got %v", tt.name, tt.reasons, a.Reasons)
+		}
+		if a.IsStale() != tt.stale {
+			t.Errorf("%s: expected stale %v, got %v (score %d)", tt.name, tt.stale, a.IsStale(), a.Score)
+		}
+	}
+}
+
+func TestJobNotifiesThenArchives(t *testing.T) {
+	db := setupTestDB(t)
+	admin := createTestUser(t, db, "admin@example.com")
+	leaver := createTestUser(t, db, "leaver@example.com")
+	db.Model(&leaver).Update("active", false)
+
+	group := models.Group{OrganizationID: 1, Name: "Platform"}
+	db.Create(&group)
+	db.Create(&models.GroupMembership{UserID: admin.ID, GroupID: group.ID, Role: models.GroupRoleAdmin})
+
+	now := time.Now()
+	stale := models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: leaver.ID, Slug: "old-wiki", URL: "https://wiki.example.com"}
+	kept := models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: admin.ID, Slug: "docs", URL: "https://docs.example.com"}
+	db.Create(&stale)
+	db.Create(&kept)
+
+	sender := &recordingSender{}
+	job := NewJob(db, sender, "https://go.example.com/")
+	job.GracePeriod = 7 * 24 * time.Hour
+
+	result, err := job.RunOnce(now)
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if result != (Result{Notified: 1}) {
+		t.Errorf("Expected 1 link notified, got %+v", result)
+	}
+	if len(sender.messages) != 1 || !reflect.DeepEqual(sender.messages[0].To, []string{"admin@example.com"}) {
+		t.Fatalf("Expected one message to the group admin, got %+v", sender.messages)
+	}
+	if !strings.Contains(sender.messages[0].Body, "https://go.example.com/old-wiki") {
+		t.Errorf("Expected the message to list the stale link, got %q", sender.messages[0].Body)
+	}
+
+	// Nothing happens again during the grace period
+	if result, _ := job.RunOnce(now.Add(24 * time.Hour)); result != (Result{}) {
+		t.Errorf("Expected no changes during the grace period, got %+v", result)
+	}
+	if len(sender.messages) != 1 {
+		t.Errorf("Expected admins to be notified once, got %d messages", len(sender.messages))
+	}
+
+	result, _ = job.RunOnce(now.Add(8 * 24 * time.Hour))
+	if result != (Result{Archived: 1}) {
+		t.Errorf("Expected 1 link archived, got %+v", result)
+	}
+
+	var archived models.Link
+	db.Unscoped().First(&archived, stale.ID)
+	if !archived.DeletedAt.Valid || archived.ArchivedSlug != "old-wiki" {
+		t.Errorf("Expected the link to be archived, got deleted=%v archived_slug=%q", archived.DeletedAt.Valid, archived.ArchivedSlug)
+	}
+
+	// The slug is free for reuse
+	if taken, _ := slugs.Taken(db, 1, "old-wiki", 0); taken {
+		t.Error("Expected the archived link's slug to be released")
+	}
+}
+
+func TestJobLeavesLinksKeptUnderDeparturePolicy(t *testing.T) {
+	db := setupTestDB(t)
+	admin := createTestUser(t, db, "admin@example.com")
+	leaver := createTestUser(t, db, "leaver@example.com")
+	db.Model(&leaver).Update("active", false)
+
+	org := models.Organization{Name: "Acme", Slug: "acme", DeparturePolicy: models.DeparturePolicyKeep}
+	db.Create(&org)
+	group := models.Group{OrganizationID: org.ID, Name: "Platform"}
+	db.Create(&group)
+	db.Create(&models.GroupMembership{UserID: admin.ID, GroupID: group.ID, Role: models.GroupRoleAdmin})
+	db.Create(&models.Link{OrganizationID: org.ID, GroupID: group.ID, CreatedByID: leaver.ID, Slug: "old-wiki", URL: "https://wiki.example.com"})
+
+	sender := &recordingSender{}
+	result, err := NewJob(db, sender, "https://go.example.com").RunOnce(time.Now())
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if result != (Result{}) || len(sender.messages) != 0 {
+		t.Errorf("Expected a kept link not to be stale because its creator left, got %+v", result)
+	}
+}
+
+func TestJobNotifiesParentGroupAdmins(t *testing.T) {
+	db := setupTestDB(t)
+	admin := createTestUser(t, db, "admin@example.com")
+	leaver := createTestUser(t, db, "leaver@example.com")
+	db.Model(&leaver).Update("active", false)
+
+	engineering := models.Group{OrganizationID: 1, Name: "Engineering"}
+	db.Create(&engineering)
+	sre := models.Group{OrganizationID: 1, Name: "SRE", ParentID: &engineering.ID}
+	db.Create(&sre)
+	db.Create(&models.GroupMembership{UserID: admin.ID, GroupID: engineering.ID, Role: models.GroupRoleAdmin})
+	db.Create(&models.Link{OrganizationID: 1, GroupID: sre.ID, CreatedByID: leaver.ID, Slug: "oncall", URL: "https://oncall.example.com"})
+
+	sender := &recordingSender{}
+	if _, err := NewJob(db, sender, "https://go.example.com").RunOnce(time.Now()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if len(sender.messages) != 1 || !reflect.DeepEqual(sender.messages[0].To, []string{"admin@example.com"}) {
+		t.Fatalf("Expected one message to the parent group's admin, got %+v", sender.messages)
+	}
+}
+
+func TestJobLock(t *testing.T) {
+	db := setupTestDB(t)
+	first := NewJob(db, &recordingSender{}, "https://go.example.com")
+	first.holder = "first"
+	second := NewJob(db, &recordingSender{}, "https://go.example.com")
+	second.holder = "second"
+
+	now := time.Now()
+	if ok, err := first.acquire(now, time.Hour); err != nil || !ok {
+		t.Fatalf("Expected the first instance to take the lock, got %v, %v", ok, err)
+	}
+	if ok, _ := second.acquire(now.Add(time.Minute), time.Hour); ok {
+		t.Error("Expected the second instance not to take a held lock")
+	}
+	if ok, _ := first.acquire(now.Add(time.Hour), time.Hour); !ok {
+		t.Error("Expected the holder to renew its lock")
+	}
+
+	// The lease lapses if the holder stops renewing it
+	if ok, _ := second.acquire(now.Add(3*time.Hour), time.Hour); !ok {
+		t.Error("Expected the second instance to take over a lapsed lock")
+	}
+	if ok, _ := first.acquire(now.Add(3*time.Hour), time.Hour); ok {
+		t.Error("Expected the first instance to lose the lock")
+	}
+}
+
+func TestAssessImportedBookmark(t *testing.T) {
+	db := setupTestDB(t)
+	user := createTestUser(t, db, "user@example.com")
+	db.Model(&user).Update("active", true)
+	group := models.Group{OrganizationID: 1, Name: "Platform"}
+	db.Create(&group)
+	db.Create(&models.GroupMembership{UserID: user.ID, GroupID: group.ID, Role: models.GroupRoleAdmin})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	api := r.Group("/api")
+	api.Use(auth.AuthMiddleware())
+	importexport.NewHandler(db).RegisterRoutes(api)
+
+	body, _ := json.Marshal(importexport.ImportRequest{GroupID: group.ID, Bookmarks: []importexport.PinboardBookmark{
+		{Href: "https://example.com/2015", Description: "Bookmarked years ago", Time: "2015-03-01T12:00:00Z"},
+	}})
+	req, _ := http.NewRequest("POST", "/api/import", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	// The bookmark keeps its date, but is only idle since it was imported
+	var link models.Link
+	db.Preload("CreatedBy").Where("url = ?", "https://example.com/2015").First(&link)
+	if link.CreatedAt.Year() != 2015 {
+		t.Errorf("Expected the bookmark's original date, got %v", link.CreatedAt)
+	}
+	if a := Assess(&link, time.Now()); a.IsStale() {
+		t.Errorf("Expected a newly imported bookmark not to be stale, got %+v", a)
+	}
+	if a := Assess(&link, time.Now().Add(IdlePeriod+time.Hour)); !a.IsStale() {
+		t.Errorf("Expected the bookmark to go stale a year after import, got %+v", a)
+	}
+}
+
+func TestJobClearsLinksThatRecover(t *testing.T) {
+	db := setupTestDB(t)
+	user := createTestUser(t, db, "user@example.com")
+	group := models.Group{OrganizationID: 1, Name: "Platform"}
+	db.Create(&group)
+
+	link := models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: user.ID, Slug: "idle", URL: "https://example.com"}
+	db.Create(&link)
+	db.Model(&link).Update("tracked_since", time.Now().Add(-400*24*time.Hour))
+
+	job := NewJob(db, &recordingSender{}, "https://go.example.com")
+	if result, _ := job.RunOnce(time.Now()); result.Notified != 1 {
+		t.Fatalf("Expected the idle link to be notified, got %+v", result)
+	}
+
+	// A click during the grace period keeps the link
+	db.Model(&link).Update("last_clicked_at", time.Now())
+	if result, _ := job.RunOnce(time.Now()); result != (Result{Cleared: 1}) {
+		t.Errorf("Expected the link to be cleared, got %+v", result)
+	}
+
+	var reloaded models.Link
+	db.First(&reloaded, link.ID)
+	if reloaded.StaleNotifiedAt != nil {
+		t.Error("Expected stale_notified_at to be cleared")
+	}
+}
+
+func TestReport(t *testing.T) {
+	db := setupTestDB(t)
+	admin := createTestUser(t, db, "admin@example.com")
+	member := createTestUser(t, db, "member@example.com")
+	db.Create(&models.OrganizationMembership{OrganizationID: 1, UserID: admin.ID, Role: models.OrgRoleAdmin})
+	db.Create(&models.OrganizationMembership{OrganizationID: 1, UserID: member.ID, Role: models.OrgRoleMember})
+
+	group := models.Group{OrganizationID: 1, Name: "Platform"}
+	db.Create(&group)
+	db.Delete(&models.User{}, member.ID)
+	db.Create(&models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: member.ID, Slug: "orphan", URL: "https://example.com"})
+	db.Create(&models.Link{OrganizationID: 1, GroupID: group.ID, CreatedByID: admin.ID, Slug: "fresh", URL: "https://example.com"})
+	db.Create(&models.Link{OrganizationID: 2, GroupID: 99, CreatedByID: member.ID, Slug: "elsewhere", URL: "https://example.com"})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	orgs := r.Group("/organizations")
+	orgs.Use(auth.AuthMiddleware())
+	NewHandler(db).RegisterRoutes(orgs)
+
+	req, _ := http.NewRequest("GET", "/organizations/1/stale-links", nil)
+	req.Header.Set("Authorization", getAuthHeader(admin))
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var report []StaleLinkResponse
+	json.Unmarshal(resp.Body.Bytes(), &report)
+	if len(report) != 1 || report[0].Slug != "orphan" || report[0].GroupName != "Platform" || report[0].CreatorActive {
+		t.Errorf("Expected only the orphaned link, got %+v", report)
+	}
+
+	req, _ = http.NewRequest("GET", "/organizations/1/stale-links", nil)
+	req.Header.Set("Authorization", getAuthHeader(member))
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,342 @@
+// Package tagquery parses boolean tag expressions used to filter links, like
+//
+//	tag=oncall AND (tag=sre OR tag=infra) AND NOT tag=deprecated
+//
+// and compiles them into SQL conditions on the links table. Tag names are
+// always passed as query arguments, never spliced into the SQL.
+package tagquery
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mikepea/shorty/pkg/shorty/tags"
+)
+
+const (
+	// MaxLength is the longest expression accepted, in bytes
+	MaxLength = 1000
+	// MaxTerms is the most tag=... terms an expression may have
+	MaxTerms = 20
+	// MaxDepth is the deepest parentheses and NOT may nest
+	MaxDepth = 10
+)
+
+// Expr is a parsed tag expression
+type Expr interface {
+	// SQL returns a condition on links matching the expression, and its arguments
+	SQL() (string, []interface{})
+	// String returns the expression in canonical form
+	String() string
+}
+
+// Tag returns an expression matching links with the tag or any tag below it,
+// so infra also matches infra/monitoring
+func Tag(name string) Expr {
+	return tagExpr{name: tags.Normalize(name)}
+}
+
+type tagExpr struct {
+	name string
+}
+
+func (e tagExpr) SQL() (string, []interface{}) {
+	return `links.id IN (SELECT link_tags.link_id FROM link_tags JOIN tags ON tags.id = link_tags.tag_id
+		WHERE tags.organization_id = links.organization_id AND tags.deleted_at IS NULL AND (tags.name = ? OR tags.name LIKE ? ESCAPE '\'))`,
+		[]interface{}{e.name, tags.DescendantPattern(e.name)}
+}
+
+func (e tagExpr) String() string {
+	if strings.ContainsAny(e.name, " \t()\"") {
+		return "tag=" + quote(e.name)
+	}
+	return "tag=" + e.name
+}
+
+type notExpr struct {
+	operand Expr
+}
+
+func (e notExpr) SQL() (string, []interface{}) {
+	sql, args := e.operand.SQL()
+	return "NOT (" + sql + ")", args
+}
+
+func (e notExpr) String() string {
+	if _, ok := e.operand.(listExpr); ok {
+		return "NOT (" + e.operand.String() + ")"
+	}
+	return "NOT " + e.operand.String()
+}
+
+// listExpr joins two or more operands with AND or OR
+type listExpr struct {
+	op       string
+	operands []Expr
+}
+
+func (e listExpr) SQL() (string, []interface{}) {
+	parts := make([]string, len(e.operands))
+	var args []interface{}
+	for i, operand := range e.operands {
+		sql, operandArgs := operand.SQL()
+		parts[i] = "(" + sql + ")"
+		args = append(args, operandArgs...)
+	}
+	return strings.Join(parts, " "+e.op+" "), args
+}
+
+func (e listExpr) String() string {
+	parts := make([]string, len(e.operands))
+	for i, operand := range e.operands {
+		parts[i] = operand.String()
+		if inner, ok := operand.(listExpr); ok && inner.op != e.op {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " "+e.op+" ")
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Error describes why an expression couldn't be parsed
+type Error struct {
+	Pos     int // Byte offset in the expression
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid tag query at position %d: %s", e.Pos, e.Message)
+}
+
+// Parse parses a tag expression. Terms are tag=name, with the name in double
+// quotes if it has spaces or parentheses, combined with AND, OR, NOT and
+// parentheses. AND binds tighter than OR. Keywords are case-insensitive.
+func Parse(input string) (Expr, error) {
+	if len(input) > MaxLength {
+		return nil, &Error{MaxLength, fmt.Sprintf("longer than %d characters", MaxLength)}
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, end: len(input)}
+	if len(tokens) == 0 {
+		return nil, p.errorf("empty expression")
+	}
+
+	expr, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected %s", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokenTag tokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind  tokenKind
+	value string // Tag name for tokenTag
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenTag:
+		return fmt.Sprintf("tag %q", t.value)
+	case tokenOpen:
+		return `"("`
+	case tokenClose:
+		return `")"`
+	}
+	return strings.ToUpper([]string{"", "and", "or", "not"}[t.kind])
+}
+
+// lex splits an expression into tokens
+func lex(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		switch ch := input[i]; {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '(':
+			tokens = append(tokens, token{kind: tokenOpen, pos: i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, token{kind: tokenClose, pos: i})
+			i++
+		default:
+			start := i
+			for i < len(input) && !strings.ContainsRune(" \t\n\r()=\"", rune(input[i])) {
+				i++
+			}
+			word := input[start:i]
+
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, token{kind: tokenAnd, pos: start})
+				continue
+			case "OR":
+				tokens = append(tokens, token{kind: tokenOr, pos: start})
+				continue
+			case "NOT":
+				tokens = append(tokens, token{kind: tokenNot, pos: start})
+				continue
+			case "TAG":
+			default:
+				return nil, &Error{start, "expected tag=name, AND, OR, NOT or parentheses"}
+			}
+
+			if i >= len(input) || input[i] != '=' {
+				return nil, &Error{i, `expected "=" after tag`}
+			}
+			i++
+
+			name, next, err := lexName(input, i)
+			if err != nil {
+				return nil, err
+			}
+			if tags.Normalize(name) == "" {
+				return nil, &Error{i, "expected a tag name"}
+			}
+			tokens = append(tokens, token{kind: tokenTag, value: name, pos: start})
+			i = next
+		}
+	}
+	return tokens, nil
+}
+
+// lexName reads a tag name, bare or in double quotes, starting at i
+func lexName(input string, i int) (string, int, error) {
+	if i < len(input) && input[i] == '"' {
+		var name strings.Builder
+		for j := i + 1; j < len(input); j++ {
+			switch input[j] {
+			case '\\':
+				if j+1 < len(input) {
+					j++
+					name.WriteByte(input[j])
+				}
+			case '"':
+				return name.String(), j + 1, nil
+			default:
+				name.WriteByte(input[j])
+			}
+		}
+		return "", 0, &Error{i, "unterminated quoted tag name"}
+	}
+
+	start := i
+	for i < len(input) && !strings.ContainsRune(" \t\n\r()\"", rune(input[i])) {
+		i++
+	}
+	return input[start:i], i, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	end    int // Length of the input, for errors at the end
+	terms  int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	pos := p.end
+	if p.pos < len(p.tokens) {
+		pos = p.tokens[p.pos].pos
+	}
+	return &Error{pos, fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) next(kind tokenKind) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == kind {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// parseOr parses operands joined by OR
+func (p *parser) parseOr(depth int) (Expr, error) {
+	return p.parseList(depth, tokenOr, "OR", p.parseAnd)
+}
+
+// parseAnd parses operands joined by AND
+func (p *parser) parseAnd(depth int) (Expr, error) {
+	return p.parseList(depth, tokenAnd, "AND", p.parseUnary)
+}
+
+func (p *parser) parseList(depth int, kind tokenKind, op string, operand func(int) (Expr, error)) (Expr, error) {
+	first, err := operand(depth)
+	if err != nil {
+		return nil, err
+	}
+	operands := []Expr{first}
+	for p.next(kind) {
+		next, err := operand(depth)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return listExpr{op: op, operands: operands}, nil
+}
+
+// parseUnary parses a term, a negation or a parenthesized expression
+func (p *parser) parseUnary(depth int) (Expr, error) {
+	if depth > MaxDepth {
+		return nil, p.errorf("nested more than %d deep", MaxDepth)
+	}
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("unexpected end of expression")
+	}
+
+	t := p.tokens[p.pos]
+	switch t.kind {
+	case tokenNot:
+		p.pos++
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{operand: operand}, nil
+
+	case tokenOpen:
+		p.pos++
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if !p.next(tokenClose) {
+			return nil, p.errorf(`expected ")"`)
+		}
+		return expr, nil
+
+	case tokenTag:
+		p.pos++
+		if p.terms++; p.terms > MaxTerms {
+			return nil, &Error{t.pos, fmt.Sprintf("more than %d tags", MaxTerms)}
+		}
+		return Tag(t.value), nil
+	}
+
+	return nil, p.errorf("unexpected %s", t)
+}
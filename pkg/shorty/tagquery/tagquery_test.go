@@ -0,0 +1,87 @@
+package tagquery
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"tag=oncall", "tag=oncall"},
+		{"tag=oncall AND (tag=sre OR tag=infra) AND NOT tag=deprecated", "tag=oncall AND (tag=sre OR tag=infra) AND NOT tag=deprecated"},
+		{"tag=a or tag=b and tag=c", "tag=a OR (tag=b AND tag=c)"},
+		{"(tag=a OR tag=b) AND tag=c", "(tag=a OR tag=b) AND tag=c"},
+		{"NOT (tag=a OR tag=b)", "NOT (tag=a OR tag=b)"},
+		{"not not tag=a", "NOT NOT tag=a"},
+		{`tag="on call" AND tag=infra/monitoring`, `tag="on call" AND tag=infra/monitoring`},
+		{`tag="say \"hi\""`, `tag="say \"hi\""`},
+		{"((tag=a))", "tag=a"},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got := expr.String(); got != tt.expected {
+			t.Errorf("Parse(%q) = %s, expected %s", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+	}{
+		{"", 0},
+		{"oncall", 0},
+		{"tag=", 4},
+		{"tag oncall", 3},
+		{"tag=a AND", 9},
+		{"tag=a tag=b", 6},
+		{"(tag=a OR tag=b", 15},
+		{"tag=a)", 5},
+		{`tag="unterminated`, 4},
+		{"tag=a; DROP TABLE links", 7},
+		{strings.Repeat("NOT ", MaxDepth+1) + "tag=a", 4 * (MaxDepth + 1)},
+		{strings.Repeat("tag=a OR ", MaxTerms) + "tag=a", 9 * MaxTerms},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		if err == nil {
+			t.Errorf("Expected Parse(%q) to fail", tt.input)
+			continue
+		}
+		if e, ok := err.(*Error); !ok || e.Pos != tt.pos {
+			t.Errorf("Parse(%q) = %v, expected an error at position %d", tt.input, err, tt.pos)
+		}
+	}
+}
+
+func TestSQL(t *testing.T) {
+	expr, err := Parse("tag=oncall AND NOT (tag=sre OR tag=deprecated)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sql, args := expr.SQL()
+	if strings.Count(sql, "links.id IN (SELECT") != 3 || !strings.Contains(sql, ") AND (NOT ((") || !strings.Contains(sql, ") OR (") {
+		t.Errorf("Unexpected SQL %s", sql)
+	}
+
+	expected := []interface{}{"oncall", "oncall/%", "sre", "sre/%", "deprecated", "deprecated/%"}
+	if len(args) != len(expected) {
+		t.Fatalf("Expected %d args, got %v", len(expected), args)
+	}
+	for i := range expected {
+		if args[i] != expected[i] {
+			t.Errorf("Expected arg %d to be %v, got %v", i, expected[i], args[i])
+		}
+	}
+}
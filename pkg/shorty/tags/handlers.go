@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
 	"github.com/mikepea/shorty/pkg/shorty/models"
 	"gorm.io/gorm"
@@ -22,9 +23,22 @@ func NewHandler(db *gorm.DB) *Handler {
 
 // TagResponse represents a tag in API responses
 type TagResponse struct {
-	ID        uint   `json:"id"`
-	Name      string `json:"name"`
-	LinkCount int    `json:"link_count,omitempty"`
+	ID             uint   `json:"id"`
+	OrganizationID uint   `json:"organization_id"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	Color          string `json:"color,omitempty"`
+	LinkCount      int    `json:"link_count,omitempty"`
+}
+
+func tagToResponse(tag models.Tag) TagResponse {
+	return TagResponse{
+		ID:             tag.ID,
+		OrganizationID: tag.OrganizationID,
+		Name:           tag.Name,
+		Description:    tag.Description,
+		Color:          tag.Color,
+	}
 }
 
 // SetTagsRequest represents the request to set tags on a link
@@ -32,57 +46,47 @@ type SetTagsRequest struct {
 	Tags []string `json:"tags" binding:"required"`
 }
 
-// getUserGroupIDs returns all group IDs the user is a member of
-func (h *Handler) getUserGroupIDs(userID uint) ([]uint, error) {
-	var memberships []models.GroupMembership
-	if err := h.db.Where("user_id = ?", userID).Find(&memberships).Error; err != nil {
-		return nil, err
-	}
-
-	groupIDs := make([]uint, len(memberships))
-	for i, m := range memberships {
-		groupIDs[i] = m.GroupID
-	}
-	return groupIDs, nil
-}
-
-// checkGroupMembership verifies the user is a member of the group
-func (h *Handler) checkGroupMembership(userID, groupID uint) error {
-	var membership models.GroupMembership
-	if err := h.db.Where("user_id = ? AND group_id = ?", userID, groupID).First(&membership).Error; err != nil {
-		return err
-	}
-	return nil
+// visibleTagQuery builds a query joining tags to the links using them that
+// the user can see: links in their groups and links shared with them
+func (h *Handler) visibleTagQuery(userID uint) *gorm.DB {
+	return h.db.Table("tags").
+		Joins("INNER JOIN link_tags ON tags.id = link_tags.tag_id").
+		Joins("INNER JOIN links ON link_tags.link_id = links.id AND links.organization_id = tags.organization_id AND links.deleted_at IS NULL").
+		Scopes(access.Visible(h.db, userID)).
+		Where("tags.deleted_at IS NULL")
 }
 
-// List returns all tags used across the user's groups
+// List returns all tags used across the user's groups. Tags belong to an
+// organization, so a name used in two organizations is listed twice unless
+// the organization_id query parameter picks one. With tree=true the tags are
+// nested by their path, with link counts rolled up to each parent.
 func (h *Handler) List(c *gin.Context) {
 	userID, _ := auth.GetUserID(c)
 
-	groupIDs, err := h.getUserGroupIDs(userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
-		return
+	// Get tags with link counts for links in the user's groups or shared with them
+	type tagWithCount struct {
+		models.Tag
+		LinkCount int
 	}
 
-	if len(groupIDs) == 0 {
-		c.JSON(http.StatusOK, []TagResponse{})
-		return
+	query := h.visibleTagQuery(userID)
+	if orgID, err := strconv.ParseUint(c.Query("organization_id"), 10, 32); err == nil {
+		query = query.Where("tags.organization_id = ?", orgID)
 	}
 
-	// Get tags with link counts for user's groups
-	type tagWithCount struct {
-		ID        uint
-		Name      string
-		LinkCount int
+	if c.Query("tree") == "true" {
+		var rows []taggedLink
+		if err := query.Select("tags.*, links.id AS link_id").Find(&rows).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+			return
+		}
+		c.JSON(http.StatusOK, buildTree(rows))
+		return
 	}
 
 	var results []tagWithCount
-	err = h.db.Table("tags").
-		Select("tags.id, tags.name, COUNT(DISTINCT links.id) as link_count").
-		Joins("INNER JOIN link_tags ON tags.id = link_tags.tag_id").
-		Joins("INNER JOIN links ON link_tags.link_id = links.id AND links.group_id IN ? AND links.deleted_at IS NULL", groupIDs).
-		Where("tags.deleted_at IS NULL").
+	err := query.
+		Select("tags.*, COUNT(DISTINCT links.id) as link_count").
 		Group("tags.id").
 		Order("link_count DESC").
 		Find(&results).Error
@@ -94,11 +98,8 @@ func (h *Handler) List(c *gin.Context) {
 
 	tags := make([]TagResponse, len(results))
 	for i, r := range results {
-		tags[i] = TagResponse{
-			ID:        r.ID,
-			Name:      r.Name,
-			LinkCount: r.LinkCount,
-		}
+		tags[i] = tagToResponse(r.Tag)
+		tags[i].LinkCount = r.LinkCount
 	}
 
 	c.JSON(http.StatusOK, tags)
@@ -114,22 +115,21 @@ func (h *Handler) ListByGroup(c *gin.Context) {
 	}
 
 	// Check membership
-	if err := h.checkGroupMembership(userID, uint(groupID)); err != nil {
+	if err := access.Authorize(h.db, userID, uint(groupID), access.ActionView); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
 		return
 	}
 
 	type tagWithCount struct {
-		ID        uint
-		Name      string
+		models.Tag
 		LinkCount int
 	}
 
 	var results []tagWithCount
 	err = h.db.Table("tags").
-		Select("tags.id, tags.name, COUNT(DISTINCT links.id) as link_count").
+		Select("tags.*, COUNT(DISTINCT links.id) as link_count").
 		Joins("INNER JOIN link_tags ON tags.id = link_tags.tag_id").
-		Joins("INNER JOIN links ON link_tags.link_id = links.id AND links.group_id = ? AND links.deleted_at IS NULL", groupID).
+		Joins("INNER JOIN links ON link_tags.link_id = links.id AND links.organization_id = tags.organization_id AND links.group_id = ? AND links.deleted_at IS NULL", groupID).
 		Where("tags.deleted_at IS NULL").
 		Group("tags.id").
 		Order("link_count DESC").
@@ -142,11 +142,8 @@ func (h *Handler) ListByGroup(c *gin.Context) {
 
 	tags := make([]TagResponse, len(results))
 	for i, r := range results {
-		tags[i] = TagResponse{
-			ID:        r.ID,
-			Name:      r.Name,
-			LinkCount: r.LinkCount,
-		}
+		tags[i] = tagToResponse(r.Tag)
+		tags[i].LinkCount = r.LinkCount
 	}
 
 	c.JSON(http.StatusOK, tags)
@@ -164,19 +161,14 @@ func (h *Handler) GetLinkTags(c *gin.Context) {
 	}
 
 	// Check access
-	if !link.IsPublic {
-		if err := h.checkGroupMembership(userID, link.GroupID); err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
-			return
-		}
+	if !access.CanView(h.db, userID, &link) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
 	}
 
 	tags := make([]TagResponse, len(link.Tags))
 	for i, t := range link.Tags {
-		tags[i] = TagResponse{
-			ID:   t.ID,
-			Name: t.Name,
-		}
+		tags[i] = tagToResponse(t)
 	}
 
 	c.JSON(http.StatusOK, tags)
@@ -193,8 +185,13 @@ func (h *Handler) SetLinkTags(c *gin.Context) {
 		return
 	}
 
-	// Check membership
-	if err := h.checkGroupMembership(userID, link.GroupID); err != nil {
+	// Check the user can edit the link
+	switch access.Role(h.db, userID, &link) {
+	case models.GrantRoleEditor:
+	case models.GrantRoleViewer:
+		c.JSON(http.StatusForbidden, gin.H{"error": "You have read-only access to this link"})
+		return
+	default:
 		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
 		return
 	}
@@ -205,24 +202,11 @@ func (h *Handler) SetLinkTags(c *gin.Context) {
 		return
 	}
 
-	// Get or create tags
-	var tags []models.Tag
-	for _, tagName := range req.Tags {
-		if tagName == "" {
-			continue
-		}
-
-		var tag models.Tag
-		// Try to find existing tag
-		if err := h.db.Where("name = ?", tagName).First(&tag).Error; err != nil {
-			// Create new tag
-			tag = models.Tag{Name: tagName}
-			if err := h.db.Create(&tag).Error; err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
-				return
-			}
-		}
-		tags = append(tags, tag)
+	// Get or create tags in the link's organization
+	tags, err := FindOrCreate(h.db, link.OrganizationID, req.Tags)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
+		return
 	}
 
 	// Replace link's tags
@@ -234,10 +218,7 @@ func (h *Handler) SetLinkTags(c *gin.Context) {
 	// Return updated tags
 	tagResponses := make([]TagResponse, len(tags))
 	for i, t := range tags {
-		tagResponses[i] = TagResponse{
-			ID:   t.ID,
-			Name: t.Name,
-		}
+		tagResponses[i] = tagToResponse(t)
 	}
 
 	c.JSON(http.StatusOK, tagResponses)
@@ -255,21 +236,28 @@ func (h *Handler) AddLinkTag(c *gin.Context) {
 		return
 	}
 
-	// Check membership
-	if err := h.checkGroupMembership(userID, link.GroupID); err != nil {
+	// Check the user can edit the link
+	switch access.Role(h.db, userID, &link) {
+	case models.GrantRoleEditor:
+	case models.GrantRoleViewer:
+		c.JSON(http.StatusForbidden, gin.H{"error": "You have read-only access to this link"})
+		return
+	default:
 		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
 		return
 	}
 
-	// Get or create tag
-	var tag models.Tag
-	if err := h.db.Where("name = ?", tagName).First(&tag).Error; err != nil {
-		tag = models.Tag{Name: tagName}
-		if err := h.db.Create(&tag).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
-			return
-		}
+	// Get or create tag in the link's organization
+	found, err := FindOrCreate(h.db, link.OrganizationID, []string{tagName})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
+		return
+	}
+	if len(found) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tag name is required"})
+		return
 	}
+	tag := found[0]
 
 	// Add tag to link
 	if err := h.db.Model(&link).Association("Tags").Append(&tag); err != nil {
@@ -277,10 +265,7 @@ func (h *Handler) AddLinkTag(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, TagResponse{
-		ID:   tag.ID,
-		Name: tag.Name,
-	})
+	c.JSON(http.StatusOK, tagToResponse(tag))
 }
 
 // RemoveLinkTag removes a tag from a link
@@ -295,15 +280,20 @@ func (h *Handler) RemoveLinkTag(c *gin.Context) {
 		return
 	}
 
-	// Check membership
-	if err := h.checkGroupMembership(userID, link.GroupID); err != nil {
+	// Check the user can edit the link
+	switch access.Role(h.db, userID, &link) {
+	case models.GrantRoleEditor:
+	case models.GrantRoleViewer:
+		c.JSON(http.StatusForbidden, gin.H{"error": "You have read-only access to this link"})
+		return
+	default:
 		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
 		return
 	}
 
 	// Find tag
 	var tag models.Tag
-	if err := h.db.Where("name = ?", tagName).First(&tag).Error; err != nil {
+	if err := h.db.Where("organization_id = ? AND name = ?", link.OrganizationID, tagName).First(&tag).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
 		return
 	}
@@ -321,12 +311,14 @@ func (h *Handler) RemoveLinkTag(c *gin.Context) {
 func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	// List all tags across user's groups
 	rg.GET("/tags", h.List)
+	rg.GET("/tags/suggest", h.Suggest)
 
 	// List tags in a specific group
 	rg.GET("/groups/:id/tags", h.ListByGroup)
 
 	// Link tag operations
 	rg.GET("/links/:slug/tags", h.GetLinkTags)
+	rg.GET("/links/:slug/tags/suggestions", h.SuggestForLink)
 	rg.PUT("/links/:slug/tags", h.SetLinkTags)
 	rg.POST("/links/:slug/tags/:tag", h.AddLinkTag)
 	rg.DELETE("/links/:slug/tags/:tag", h.RemoveLinkTag)
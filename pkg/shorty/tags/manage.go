@@ -0,0 +1,302 @@
+package tags
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/history"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+var colorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// UpdateTagRequest represents the request to rename, describe or color a tag
+type UpdateTagRequest struct {
+	Name        string  `json:"name" binding:"omitempty,max=100"`
+	Description *string `json:"description"`
+	Color       *string `json:"color"` // #rrggbb, or empty to clear
+}
+
+// MergeTagsRequest represents the request to merge tags into another
+type MergeTagsRequest struct {
+	SourceIDs []uint `json:"source_ids" binding:"required,min=1"`
+	TargetID  uint   `json:"target_id" binding:"required"`
+}
+
+// requireOrgAdmin parses the organization ID and checks the user administers it
+func (h *Handler) requireOrgAdmin(c *gin.Context) (uint, uint, bool) {
+	userID, _ := auth.GetUserID(c)
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return 0, 0, false
+	}
+
+	// Check admin membership
+	if err := h.db.Where("user_id = ? AND organization_id = ? AND role = ?", userID, orgID, models.OrgRoleAdmin).First(&models.OrganizationMembership{}).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return 0, 0, false
+	}
+
+	return userID, uint(orgID), true
+}
+
+// findOrgTag looks up one of the organization's tags by ID
+func (h *Handler) findOrgTag(c *gin.Context, orgID uint) (*models.Tag, bool) {
+	var tag models.Tag
+	if err := h.db.Where("id = ? AND organization_id = ?", c.Param("tagId"), orgID).First(&tag).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return nil, false
+	}
+	return &tag, true
+}
+
+// taggedLinkIDs returns the IDs of links with the tag
+func taggedLinkIDs(db *gorm.DB, tagID uint) ([]uint, error) {
+	var linkIDs []uint
+	err := db.Table("link_tags").Where("tag_id = ?", tagID).Order("link_id").Pluck("link_id", &linkIDs).Error
+	return linkIDs, err
+}
+
+// ListOrgTags returns every tag in an organization, including unused ones (admin only)
+func (h *Handler) ListOrgTags(c *gin.Context) {
+	_, orgID, ok := h.requireOrgAdmin(c)
+	if !ok {
+		return
+	}
+
+	type tagWithCount struct {
+		models.Tag
+		LinkCount int
+	}
+
+	var results []tagWithCount
+	if err := h.db.Model(&models.Tag{}).
+		Select("tags.*, COUNT(DISTINCT links.id) as link_count").
+		Joins("LEFT JOIN link_tags ON tags.id = link_tags.tag_id").
+		Joins("LEFT JOIN links ON link_tags.link_id = links.id AND links.deleted_at IS NULL").
+		Where("tags.organization_id = ?", orgID).
+		Group("tags.id").
+		Order("tags.name").
+		Find(&results).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	tags := make([]TagResponse, len(results))
+	for i, r := range results {
+		tags[i] = tagToResponse(r.Tag)
+		tags[i].LinkCount = r.LinkCount
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// UpdateTag renames a tag or changes its description or color (admin only).
+// Renaming a tag moves the tags below it too, so renaming infra to platform
+// turns infra/monitoring into platform/monitoring. Renames are recorded in the
+// history of every link with a moved tag.
+func (h *Handler) UpdateTag(c *gin.Context) {
+	userID, orgID, ok := h.requireOrgAdmin(c)
+	if !ok {
+		return
+	}
+
+	tag, ok := h.findOrgTag(c, orgID)
+	if !ok {
+		return
+	}
+
+	var req UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Color != nil && *req.Color != "" && !colorRegex.MatchString(*req.Color) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Color must be a hex value like #1f77b4"})
+		return
+	}
+
+	oldName := tag.Name
+	newName := Normalize(req.Name)
+	renamed := newName != "" && newName != oldName
+
+	// The tag and the tags below it, shortest name first so parents move
+	// before their children
+	var moving []models.Tag
+	if renamed {
+		if IsDescendant(newName, oldName) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A tag can't be moved below itself"})
+			return
+		}
+
+		if err := h.db.Where("organization_id = ? AND (id = ? OR name LIKE ? ESCAPE '\\')", orgID, tag.ID, DescendantPattern(oldName)).
+			Order("LENGTH(name), name").
+			Find(&moving).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+			return
+		}
+
+		movingIDs := make([]uint, len(moving))
+		newNames := make([]string, len(moving))
+		for i, t := range moving {
+			movingIDs[i] = t.ID
+			newNames[i] = newName + strings.TrimPrefix(t.Name, oldName)
+		}
+
+		var count int64
+		h.db.Model(&models.Tag{}).Where("organization_id = ? AND name IN ? AND id NOT IN ?", orgID, newNames, movingIDs).Count(&count)
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "A tag with this name already exists; merge the tags instead"})
+			return
+		}
+	}
+	if req.Description != nil {
+		tag.Description = *req.Description
+	}
+	if req.Color != nil {
+		tag.Color = strings.ToLower(*req.Color)
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		for _, t := range moving {
+			movedName := newName + strings.TrimPrefix(t.Name, oldName)
+			if err := tx.Model(&models.Tag{}).Where("id = ?", t.ID).Update("name", movedName).Error; err != nil {
+				return err
+			}
+			linkIDs, err := taggedLinkIDs(tx, t.ID)
+			if err != nil {
+				return err
+			}
+			if err := history.Record(tx, linkIDs, userID, models.LinkEventTagRenamed, fmt.Sprintf("Tag %q renamed to %q", t.Name, movedName)); err != nil {
+				return err
+			}
+		}
+		if renamed {
+			tag.Name = newName
+		}
+		return tx.Save(tag).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tagToResponse(*tag))
+}
+
+// MergeTags moves every link from the source tags to the target tag and
+// deletes the source tags, in one transaction (admin only)
+func (h *Handler) MergeTags(c *gin.Context) {
+	userID, orgID, ok := h.requireOrgAdmin(c)
+	if !ok {
+		return
+	}
+
+	var req MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var target models.Tag
+	if err := h.db.Where("id = ? AND organization_id = ?", req.TargetID, orgID).First(&target).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Target tag not found"})
+		return
+	}
+
+	var sources []models.Tag
+	if err := h.db.Where("id IN ? AND organization_id = ?", req.SourceIDs, orgID).Find(&sources).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+	if len(sources) != len(req.SourceIDs) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Source tag not found"})
+		return
+	}
+	for _, source := range sources {
+		if source.ID == target.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A tag can't be merged into itself"})
+			return
+		}
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		for _, source := range sources {
+			linkIDs, err := taggedLinkIDs(tx, source.ID)
+			if err != nil {
+				return err
+			}
+
+			// Links that already have the target tag just lose the source tag
+			if err := tx.Exec("DELETE FROM link_tags WHERE tag_id = ? AND link_id IN (SELECT link_id FROM link_tags WHERE tag_id = ?)", source.ID, target.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE link_tags SET tag_id = ? WHERE tag_id = ?", target.ID, source.ID).Error; err != nil {
+				return err
+			}
+			// Deleted for good so the name can be used again
+			if err := tx.Unscoped().Delete(&source).Error; err != nil {
+				return err
+			}
+
+			if err := history.Record(tx, linkIDs, userID, models.LinkEventTagMerged, fmt.Sprintf("Tag %q merged into %q", source.Name, target.Name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tagToResponse(target))
+}
+
+// DeleteTag removes a tag from every link and deletes it (admin only)
+func (h *Handler) DeleteTag(c *gin.Context) {
+	userID, orgID, ok := h.requireOrgAdmin(c)
+	if !ok {
+		return
+	}
+
+	tag, ok := h.findOrgTag(c, orgID)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		linkIDs, err := taggedLinkIDs(tx, tag.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM link_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(tag).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, linkIDs, userID, models.LinkEventTagDeleted, fmt.Sprintf("Tag %q deleted", tag.Name))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted"})
+}
+
+// RegisterOrgRoutes registers tag management routes on the organizations router group
+func (h *Handler) RegisterOrgRoutes(rg *gin.RouterGroup) {
+	rg.GET("/:id/tags", h.ListOrgTags)
+	rg.POST("/:id/tags/merge", h.MergeTags)
+	rg.PUT("/:id/tags/:tagId", h.UpdateTag)
+	rg.DELETE("/:id/tags/:tagId", h.DeleteTag)
+}
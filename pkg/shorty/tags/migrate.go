@@ -0,0 +1,80 @@
+package tags
+
+import (
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// legacyNameIndex is the unique index on tag names from before tags were
+// scoped to organizations
+const legacyNameIndex = "idx_tags_name"
+
+// ScopeToOrganizations upgrades tags created before they were scoped to
+// organizations. A tag used by links in several organizations is split into
+// one tag per organization, and tags no link uses are removed. It is safe to
+// run on every start.
+func ScopeToOrganizations(db *gorm.DB) error {
+	// Names only need to be unique within an organization now
+	if db.Migrator().HasIndex(&models.Tag{}, legacyNameIndex) {
+		if err := db.Migrator().DropIndex(&models.Tag{}, legacyNameIndex); err != nil {
+			return err
+		}
+	}
+
+	var legacy []models.Tag
+	if err := db.Where("organization_id = 0 OR organization_id IS NULL").Order("id").Find(&legacy).Error; err != nil {
+		return err
+	}
+
+	for _, tag := range legacy {
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return splitTag(tx, tag)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitTag gives a legacy tag to the first organization whose links use it,
+// and a copy to every other organization
+func splitTag(tx *gorm.DB, tag models.Tag) error {
+	// Deleted links keep their tags in case they are restored
+	var orgIDs []uint
+	if err := tx.Table("link_tags").
+		Joins("JOIN links ON links.id = link_tags.link_id").
+		Where("link_tags.tag_id = ?", tag.ID).
+		Distinct().Order("links.organization_id").
+		Pluck("links.organization_id", &orgIDs).Error; err != nil {
+		return err
+	}
+
+	if len(orgIDs) == 0 {
+		return tx.Unscoped().Delete(&tag).Error
+	}
+
+	if err := tx.Model(&tag).Update("organization_id", orgIDs[0]).Error; err != nil {
+		return err
+	}
+
+	for _, orgID := range orgIDs[1:] {
+		copies, err := FindOrCreate(tx, orgID, []string{tag.Name})
+		if err != nil {
+			return err
+		}
+		orgLinks := tx.Model(&models.Link{}).Unscoped().Select("id").Where("organization_id = ?", orgID)
+
+		// Links that somehow have both tags keep one
+		if err := tx.Exec("DELETE FROM link_tags WHERE tag_id = ? AND link_id IN (?) AND link_id IN (SELECT link_id FROM link_tags WHERE tag_id = ?)",
+			tag.ID, orgLinks, copies[0].ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE link_tags SET tag_id = ? WHERE tag_id = ? AND link_id IN (?)",
+			copies[0].ID, tag.ID, orgLinks).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package tags
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mikepea/shorty/pkg/shorty/access"
+	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/models"
+)
+
+const (
+	// similarLinkLimit caps how many similar links are considered when
+	// suggesting tags for a link
+	similarLinkLimit = 200
+	// prefixMatchLimit caps how many matching tags are ranked for completion
+	prefixMatchLimit = 200
+	// Similarity scores: a link on the same domain counts for more than one
+	// shared title word
+	sameDomainScore = 3
+	titleWordScore  = 1
+)
+
+// stopWords are left out when comparing titles
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true,
+	"your": true, "how": true, "what": true, "why": true, "are": true, "our": true,
+	"this": true, "that": true, "use": true, "using": true, "home": true, "page": true,
+}
+
+// TagSuggestion represents a suggested tag. For link suggestions LinkCount is
+// the number of similar links with the tag, and Score adds up how similar they are.
+type TagSuggestion struct {
+	TagResponse
+	Score int `json:"score"`
+}
+
+// suggestionLimit reads the limit query parameter (default 10, max 50)
+func suggestionLimit(c *gin.Context) int {
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 50 {
+		return l
+	}
+	return 10
+}
+
+// Suggest completes a partly typed tag name. Tags starting with the prefix
+// come before tags with a level starting with it, so "mon" suggests
+// monitoring before infra/monitoring, and then the most used come first.
+func (h *Handler) Suggest(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	prefix := strings.ToLower(strings.TrimSpace(c.Query("prefix")))
+
+	query := h.visibleTagQuery(userID)
+	if orgID, err := strconv.ParseUint(c.Query("organization_id"), 10, 32); err == nil {
+		query = query.Where("tags.organization_id = ?", orgID)
+	}
+	if prefix != "" {
+		pattern := likeEscaper.Replace(prefix) + "%"
+		query = query.Where(`tags.name LIKE ? ESCAPE '\' OR tags.name LIKE ? ESCAPE '\'`, pattern, "%"+Separator+pattern)
+	}
+
+	type tagWithCount struct {
+		models.Tag
+		LinkCount int
+	}
+
+	var results []tagWithCount
+	if err := query.
+		Select("tags.*, COUNT(DISTINCT links.id) as link_count").
+		Group("tags.id").
+		Order("link_count DESC").
+		Limit(prefixMatchLimit).
+		Find(&results).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	suggestions := make([]TagSuggestion, len(results))
+	for i, r := range results {
+		suggestions[i] = TagSuggestion{TagResponse: tagToResponse(r.Tag), Score: r.LinkCount}
+		suggestions[i].LinkCount = r.LinkCount
+	}
+
+	// Whole-name matches first, then by usage
+	startsWith := func(s TagSuggestion) bool { return strings.HasPrefix(strings.ToLower(s.Name), prefix) }
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if a, b := startsWith(suggestions[i]), startsWith(suggestions[j]); a != b {
+			return a
+		}
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	if limit := suggestionLimit(c); len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// SuggestForLink proposes tags for a link from the tags on similar links:
+// links the user can see in the same organization on the same domain or with
+// words in common in the title. Tags on more, and more similar, links score higher.
+func (h *Handler) SuggestForLink(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+
+	var link models.Link
+	if err := h.db.Preload("Tags").Where("slug = ?", c.Param("slug")).First(&link).Error; err != nil || !access.CanView(h.db, userID, &link) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+
+	domain := linkDomain(link.URL)
+	words := titleWords(link.Title)
+
+	// Find links sharing the domain or a title word
+	similar := h.db.Where("1 = 0")
+	if domain != "" {
+		similar = similar.Or("links.url LIKE ?", "%"+domain+"%")
+	}
+	for _, word := range words {
+		similar = similar.Or("links.title LIKE ?", "%"+word+"%")
+	}
+
+	var candidates []models.Link
+	if err := h.db.Model(&models.Link{}).
+		Scopes(access.Visible(h.db, userID)).
+		Where("links.organization_id = ? AND links.id != ?", link.OrganizationID, link.ID).
+		Where(similar).
+		Order("links.id DESC").
+		Limit(similarLinkLimit).
+		Find(&candidates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find similar links"})
+		return
+	}
+
+	// Score each candidate, since LIKE also matches partial words and hosts
+	wordSet := make(map[string]bool, len(words))
+	for _, word := range words {
+		wordSet[word] = true
+	}
+	scores := make(map[uint]int)
+	var similarIDs []uint
+	for _, candidate := range candidates {
+		score := 0
+		if domain != "" && linkDomain(candidate.URL) == domain {
+			score += sameDomainScore
+		}
+		for _, word := range titleWords(candidate.Title) {
+			if wordSet[word] {
+				score += titleWordScore
+			}
+		}
+		if score > 0 {
+			scores[candidate.ID] = score
+			similarIDs = append(similarIDs, candidate.ID)
+		}
+	}
+
+	suggestions := []TagSuggestion{}
+	if len(similarIDs) > 0 {
+		var rows []taggedLink
+		if err := h.visibleTagQuery(userID).
+			Select("tags.*, links.id AS link_id").
+			Where("links.id IN ?", similarIDs).
+			Find(&rows).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+			return
+		}
+
+		existing := make(map[uint]bool, len(link.Tags))
+		for _, tag := range link.Tags {
+			existing[tag.ID] = true
+		}
+
+		byTag := make(map[uint]*TagSuggestion)
+		for _, row := range rows {
+			if existing[row.ID] {
+				continue
+			}
+			s, ok := byTag[row.ID]
+			if !ok {
+				s = &TagSuggestion{TagResponse: tagToResponse(row.Tag)}
+				byTag[row.ID] = s
+			}
+			s.Score += scores[row.LinkID]
+			s.LinkCount++
+		}
+		for _, s := range byTag {
+			suggestions = append(suggestions, *s)
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		if suggestions[i].LinkCount != suggestions[j].LinkCount {
+			return suggestions[i].LinkCount > suggestions[j].LinkCount
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	if limit := suggestionLimit(c); len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// linkDomain returns the host of a link's URL without any www. prefix, or ""
+// for pages and unparseable URLs
+func linkDomain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// titleWords returns the distinct lowercase words of three or more letters in
+// a title, leaving out stop words
+func titleWords(title string) []string {
+	seen := make(map[string]bool)
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) < 3 || stopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	return words
+}
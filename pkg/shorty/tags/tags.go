@@ -0,0 +1,80 @@
+package tags
+
+import (
+	"strings"
+
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// Separator divides the levels of hierarchical tag names, as in
+// infra/monitoring/grafana
+const Separator = "/"
+
+// Normalize tidies a tag name, trimming spaces and dropping empty levels, so
+// " infra//monitoring/ " becomes "infra/monitoring"
+func Normalize(name string) string {
+	var levels []string
+	for _, level := range strings.Split(name, Separator) {
+		if level = strings.TrimSpace(level); level != "" {
+			levels = append(levels, level)
+		}
+	}
+	return strings.Join(levels, Separator)
+}
+
+// IsDescendant reports whether name is below ancestor in the tag hierarchy
+func IsDescendant(name, ancestor string) bool {
+	return strings.HasPrefix(name, ancestor+Separator)
+}
+
+// likeEscaper escapes the wildcards in LIKE patterns used with ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// DescendantPattern returns a LIKE pattern, to be used with ESCAPE '\', that
+// matches the names of every tag below name
+func DescendantPattern(name string) string {
+	return likeEscaper.Replace(name) + Separator + "%"
+}
+
+// FindOrCreate returns the organization's tags with the given names, creating
+// any that don't exist. Tags are scoped to an organization, so the same name
+// in two organizations is two different tags.
+func FindOrCreate(db *gorm.DB, orgID uint, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	for _, name := range names {
+		name = Normalize(name)
+		if name == "" {
+			continue
+		}
+
+		var tag models.Tag
+		if err := db.Where("organization_id = ? AND name = ?", orgID, name).First(&tag).Error; err != nil {
+			tag = models.Tag{OrganizationID: orgID, Name: name}
+			if err := db.Create(&tag).Error; err != nil {
+				return nil, err
+			}
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// Find returns the organization's tags with the given names. Names without a
+// tag are ignored.
+func Find(db *gorm.DB, orgID uint, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	if err := db.Where("organization_id = ? AND name IN ?", orgID, names).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Names returns the names of tags
+func Names(tags []models.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
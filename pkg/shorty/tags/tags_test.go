@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
@@ -75,6 +78,7 @@ func setupTestRouter(db *gorm.DB) *gin.Engine {
 	api := r.Group("/api")
 	api.Use(auth.AuthMiddleware())
 	handler.RegisterRoutes(api)
+	handler.RegisterOrgRoutes(api.Group("/organizations"))
 
 	return r
 }
@@ -327,3 +331,509 @@ func TestTagAccessControl(t *testing.T) {
 		t.Errorf("Expected status 404, got %d", resp.Code)
 	}
 }
+
+func TestSharedLinkTags(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	owner := createTestUser(t, db, "owner@example.com")
+	viewer := createTestUser(t, db, "viewer@example.com")
+	group := createTestGroup(t, db, "Test Group", owner.ID)
+	link := createTestLink(t, db, group.ID, owner.ID, "test-link")
+	tag := models.Tag{Name: "golang"}
+	db.Create(&tag)
+	db.Model(&link).Association("Tags").Append(&tag)
+	grant := models.LinkGrant{LinkID: link.ID, UserID: &viewer.ID, Role: models.GrantRoleViewer, GrantedByID: owner.ID}
+	db.Create(&grant)
+
+	// Tags on shared links are listed
+	req, _ := http.NewRequest("GET", "/api/tags", nil)
+	req.Header.Set("Authorization", getAuthHeader(viewer))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	var tags []TagResponse
+	json.Unmarshal(resp.Body.Bytes(), &tags)
+	if len(tags) != 1 || tags[0].Name != "golang" {
+		t.Errorf("Expected the shared link's tag, got %+v", tags)
+	}
+
+	// Viewers can't change them
+	req, _ = http.NewRequest("POST", "/api/links/test-link/tags/mine", nil)
+	req.Header.Set("Authorization", getAuthHeader(viewer))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+
+	// Editors can
+	db.Model(&grant).Update("role", models.GrantRoleEditor)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestTagsAreScopedToOrganizations(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	acme := createTestGroup(t, db, "Acme", user.ID)
+	other := createTestGroup(t, db, "Other", user.ID)
+	db.Model(&acme).Update("organization_id", 1)
+	db.Model(&other).Update("organization_id", 2)
+	acmeLink := createTestLink(t, db, acme.ID, user.ID, "acme-link")
+	otherLink := createTestLink(t, db, other.ID, user.ID, "other-link")
+	db.Model(&acmeLink).Update("organization_id", 1)
+	db.Model(&otherLink).Update("organization_id", 2)
+
+	addTag := func(slug string) TagResponse {
+		req, _ := http.NewRequest("POST", "/api/links/"+slug+"/tags/secret-project", nil)
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+		}
+		var tag TagResponse
+		json.Unmarshal(resp.Body.Bytes(), &tag)
+		return tag
+	}
+
+	acmeTag := addTag("acme-link")
+	otherTag := addTag("other-link")
+	if acmeTag.ID == otherTag.ID || acmeTag.OrganizationID != 1 || otherTag.OrganizationID != 2 {
+		t.Errorf("Expected a separate tag per organization, got %+v and %+v", acmeTag, otherTag)
+	}
+
+	// Each organization's tag is listed with its own count
+	req, _ := http.NewRequest("GET", "/api/tags?organization_id=2", nil)
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	var tags []TagResponse
+	json.Unmarshal(resp.Body.Bytes(), &tags)
+	if len(tags) != 1 || tags[0].ID != otherTag.ID || tags[0].LinkCount != 1 {
+		t.Errorf("Expected only the other organization's tag, got %+v", tags)
+	}
+}
+
+func TestScopeToOrganizations(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	// Tags from before organizations, with globally unique names
+	type legacyTag struct {
+		ID        uint `gorm:"primarykey"`
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt gorm.DeletedAt `gorm:"index"`
+		Name      string         `gorm:"uniqueIndex;not null"`
+	}
+	if err := db.Table("tags").AutoMigrate(&legacyTag{}); err != nil {
+		t.Fatalf("Failed to create legacy tags table: %v", err)
+	}
+	db.Table("tags").Create(&legacyTag{Name: "shared"})
+	db.Table("tags").Create(&legacyTag{Name: "unused"})
+	if err := models.AutoMigrate(db); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	var shared models.Tag
+	db.Where("name = ?", "shared").First(&shared)
+	links := []models.Link{
+		{OrganizationID: 1, GroupID: 1, Slug: "one"},
+		{OrganizationID: 2, GroupID: 2, Slug: "two"},
+		{OrganizationID: 2, GroupID: 2, Slug: "three"},
+	}
+	for i := range links {
+		links[i].CreatedByID = 1
+		links[i].URL = "https://example.com"
+		db.Create(&links[i])
+		db.Model(&links[i]).Association("Tags").Append(&shared)
+	}
+
+	if err := ScopeToOrganizations(db); err != nil {
+		t.Fatalf("ScopeToOrganizations failed: %v", err)
+	}
+	// Running it again changes nothing
+	if err := ScopeToOrganizations(db); err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+
+	var tags []models.Tag
+	db.Order("organization_id").Find(&tags)
+	if len(tags) != 2 || tags[0].ID != shared.ID || tags[0].OrganizationID != 1 || tags[1].OrganizationID != 2 || tags[1].Name != "shared" {
+		t.Fatalf("Expected the shared tag split between organizations and the unused tag removed, got %+v", tags)
+	}
+
+	for _, link := range links {
+		var linkTags []models.Tag
+		db.Model(&link).Association("Tags").Find(&linkTags)
+		if len(linkTags) != 1 || linkTags[0].OrganizationID != link.OrganizationID {
+			t.Errorf("Expected %s to have its organization's tag, got %+v", link.Slug, linkTags)
+		}
+	}
+
+	// Names are now only unique within an organization
+	if err := db.Create(&models.Tag{OrganizationID: 3, Name: "shared"}).Error; err != nil {
+		t.Errorf("Expected a third organization to get its own tag: %v", err)
+	}
+}
+
+func TestManageTags(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	admin := createTestUser(t, db, "admin@example.com")
+	member := createTestUser(t, db, "member@example.com")
+	db.Create(&models.OrganizationMembership{OrganizationID: 1, UserID: admin.ID, Role: models.OrgRoleAdmin})
+	db.Create(&models.OrganizationMembership{OrganizationID: 1, UserID: member.ID, Role: models.OrgRoleMember})
+	group := createTestGroup(t, db, "Platform", member.ID)
+	db.Model(&group).Update("organization_id", 1)
+
+	k8s := models.Tag{OrganizationID: 1, Name: "k8s"}
+	kube := models.Tag{OrganizationID: 1, Name: "kube"}
+	kubernetes := models.Tag{OrganizationID: 1, Name: "kubernetes"}
+	other := models.Tag{OrganizationID: 2, Name: "other"}
+	for _, tag := range []*models.Tag{&k8s, &kube, &kubernetes, &other} {
+		db.Create(tag)
+	}
+	one := createTestLink(t, db, group.ID, member.ID, "one")
+	two := createTestLink(t, db, group.ID, member.ID, "two")
+	db.Model(&one).Association("Tags").Append(&k8s, &kubernetes)
+	db.Model(&two).Association("Tags").Append(&kube)
+
+	do := func(user models.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(user))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	tagPath := func(tag models.Tag) string {
+		return "/api/organizations/1/tags/" + strconv.FormatUint(uint64(tag.ID), 10)
+	}
+
+	// Only org admins manage tags
+	if resp := do(member, "PUT", tagPath(k8s), UpdateTagRequest{Name: "kubernetes-typo"}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.Code)
+	}
+	// Tags from other organizations aren't found
+	if resp := do(admin, "DELETE", tagPath(other), nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+
+	// Renaming onto an existing name needs a merge instead
+	if resp := do(admin, "PUT", tagPath(kube), UpdateTagRequest{Name: "kubernetes"}); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", resp.Code)
+	}
+	color, badColor, description := "#326CE5", "blue", "Container orchestration"
+	if resp := do(admin, "PUT", tagPath(kubernetes), UpdateTagRequest{Color: &badColor}); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.Code)
+	}
+	resp := do(admin, "PUT", tagPath(kubernetes), UpdateTagRequest{Name: "k8s-cluster", Description: &description, Color: &color})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var updated TagResponse
+	json.Unmarshal(resp.Body.Bytes(), &updated)
+	if updated.Name != "k8s-cluster" || updated.Color != "#326ce5" || updated.Description != description {
+		t.Errorf("Expected the tag to be renamed, described and colored, got %+v", updated)
+	}
+
+	// Merging moves links to the target without duplicating it
+	resp = do(admin, "POST", "/api/organizations/1/tags/merge", MergeTagsRequest{SourceIDs: []uint{k8s.ID, kube.ID}, TargetID: kubernetes.ID})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	for _, link := range []models.Link{one, two} {
+		var linkTags []models.Tag
+		db.Model(&link).Association("Tags").Find(&linkTags)
+		if len(linkTags) != 1 || linkTags[0].ID != kubernetes.ID {
+			t.Errorf("Expected %s to have only the merged tag, got %+v", link.Slug, linkTags)
+		}
+	}
+	var count int64
+	db.Unscoped().Model(&models.Tag{}).Where("id IN ?", []uint{k8s.ID, kube.ID}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected the source tags to be deleted, %d left", count)
+	}
+
+	// The merged name is free to use again
+	if resp := do(admin, "PUT", tagPath(kubernetes), UpdateTagRequest{Name: "k8s"}); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	if resp := do(admin, "DELETE", tagPath(kubernetes), nil); resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.Code)
+	}
+	db.Table("link_tags").Count(&count)
+	if count != 0 {
+		t.Errorf("Expected the tag to be removed from every link, %d left", count)
+	}
+
+	// Every change is in the history of the links it touched
+	var events []models.LinkEvent
+	db.Where("link_id = ?", one.ID).Order("id").Find(&events)
+	actions := make([]string, len(events))
+	for i, e := range events {
+		actions[i] = e.Action
+		if e.ActorID != admin.ID {
+			t.Errorf("Expected the admin as actor, got %d", e.ActorID)
+		}
+	}
+	expected := []string{models.LinkEventTagRenamed, models.LinkEventTagMerged, models.LinkEventTagRenamed, models.LinkEventTagDeleted}
+	if strings.Join(actions, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected history %v, got %v", expected, actions)
+	}
+	if events[1].Detail != `Tag "k8s" merged into "k8s-cluster"` {
+		t.Errorf("Unexpected detail %q", events[1].Detail)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"golang":                     "golang",
+		" infra//monitoring/ ":       "infra/monitoring",
+		"infra / monitoring/grafana": "infra/monitoring/grafana",
+		"/":                          "",
+	}
+	for input, expected := range tests {
+		if got := Normalize(input); got != expected {
+			t.Errorf("Normalize(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestListTagsTree(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+	dashboards := createTestLink(t, db, group.ID, user.ID, "dashboards")
+	alerts := createTestLink(t, db, group.ID, user.ID, "alerts")
+	runbook := createTestLink(t, db, group.ID, user.ID, "runbook")
+
+	grafana := models.Tag{Name: "infra/monitoring/grafana"}
+	monitoring := models.Tag{Name: "infra/monitoring"}
+	golang := models.Tag{Name: "golang"}
+	for _, tag := range []*models.Tag{&grafana, &monitoring, &golang} {
+		db.Create(tag)
+	}
+	db.Model(&dashboards).Association("Tags").Append(&grafana, &monitoring)
+	db.Model(&alerts).Association("Tags").Append(&monitoring)
+	db.Model(&runbook).Association("Tags").Append(&golang)
+
+	req, _ := http.NewRequest("GET", "/api/tags?tree=true", nil)
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var roots []TagNode
+	json.Unmarshal(resp.Body.Bytes(), &roots)
+	if len(roots) != 2 || roots[0].Name != "golang" || roots[1].Name != "infra" {
+		t.Fatalf("Expected roots golang and infra, got %+v", roots)
+	}
+
+	// infra has no tag of its own but counts the links below it once each
+	infra := roots[1]
+	if infra.ID != 0 || infra.LinkCount != 0 || infra.TotalLinkCount != 2 {
+		t.Errorf("Expected infra to roll up 2 links, got %+v", infra)
+	}
+	if len(infra.Children) != 1 {
+		t.Fatalf("Expected infra to have 1 child, got %d", len(infra.Children))
+	}
+	mon := infra.Children[0]
+	if mon.ID != monitoring.ID || mon.Path != "infra/monitoring" || mon.LinkCount != 2 || mon.TotalLinkCount != 2 {
+		t.Errorf("Unexpected monitoring node %+v", mon)
+	}
+	if len(mon.Children) != 1 || mon.Children[0].Name != "grafana" || mon.Children[0].TotalLinkCount != 1 {
+		t.Errorf("Expected grafana below monitoring, got %+v", mon.Children)
+	}
+}
+
+func TestRenameTagSubtree(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	admin := createTestUser(t, db, "admin@example.com")
+	db.Create(&models.OrganizationMembership{OrganizationID: 1, UserID: admin.ID, Role: models.OrgRoleAdmin})
+	group := createTestGroup(t, db, "Platform", admin.ID)
+	db.Model(&group).Update("organization_id", 1)
+
+	infra := models.Tag{OrganizationID: 1, Name: "infra"}
+	monitoring := models.Tag{OrganizationID: 1, Name: "infra/monitoring"}
+	grafana := models.Tag{OrganizationID: 1, Name: "infra/monitoring/grafana"}
+	infrastructure := models.Tag{OrganizationID: 1, Name: "infrastructure"}
+	taken := models.Tag{OrganizationID: 1, Name: "ops/monitoring"}
+	for _, tag := range []*models.Tag{&infra, &monitoring, &grafana, &infrastructure, &taken} {
+		db.Create(tag)
+	}
+	link := createTestLink(t, db, group.ID, admin.ID, "dashboards")
+	db.Model(&link).Association("Tags").Append(&grafana)
+
+	rename := func(tag models.Tag, name string) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(UpdateTagRequest{Name: name})
+		req, _ := http.NewRequest("PUT", "/api/organizations/1/tags/"+strconv.FormatUint(uint64(tag.ID), 10), bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", getAuthHeader(admin))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	if resp := rename(infra, "infra/legacy"); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 moving a tag below itself, got %d", resp.Code)
+	}
+	// A moved child would clash with ops/monitoring
+	if resp := rename(infra, "ops"); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", resp.Code)
+	}
+
+	if resp := rename(infra, " platform/ "); resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	expected := map[uint]string{
+		infra.ID:          "platform",
+		monitoring.ID:     "platform/monitoring",
+		grafana.ID:        "platform/monitoring/grafana",
+		infrastructure.ID: "infrastructure",
+	}
+	for id, name := range expected {
+		var tag models.Tag
+		db.First(&tag, id)
+		if tag.Name != name {
+			t.Errorf("Expected tag %d to be named %q, got %q", id, name, tag.Name)
+		}
+	}
+
+	// Moving a subtree up a level
+	if resp := rename(monitoring, "monitoring"); resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var moved models.Tag
+	db.First(&moved, grafana.ID)
+	if moved.Name != "monitoring/grafana" {
+		t.Errorf("Expected monitoring/grafana, got %q", moved.Name)
+	}
+
+	var events []models.LinkEvent
+	db.Where("link_id = ?", link.ID).Order("id").Find(&events)
+	if len(events) != 2 || events[1].Detail != `Tag "platform/monitoring/grafana" renamed to "monitoring/grafana"` {
+		t.Errorf("Expected both moves in the link's history, got %+v", events)
+	}
+}
+
+func TestSuggestTags(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	other := createTestUser(t, db, "other@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+	otherGroup := createTestGroup(t, db, "Other Group", other.ID)
+
+	monitoring := models.Tag{Name: "monitoring"}
+	infraMonitoring := models.Tag{Name: "infra/monitoring"}
+	money := models.Tag{Name: "money"}
+	secret := models.Tag{Name: "mongo-secret"}
+	for _, tag := range []*models.Tag{&monitoring, &infraMonitoring, &money, &secret} {
+		db.Create(tag)
+	}
+	for i, tag := range []*models.Tag{&infraMonitoring, &infraMonitoring, &infraMonitoring, &money, &money, &monitoring} {
+		link := createTestLink(t, db, group.ID, user.ID, "suggest-"+strconv.Itoa(i))
+		db.Model(&link).Association("Tags").Append(tag)
+	}
+	hidden := createTestLink(t, db, otherGroup.ID, other.ID, "hidden")
+	db.Model(&hidden).Association("Tags").Append(&secret)
+
+	req, _ := http.NewRequest("GET", "/api/tags/suggest?prefix=MON", nil)
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var suggestions []TagSuggestion
+	json.Unmarshal(resp.Body.Bytes(), &suggestions)
+	names := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		names[i] = s.Name
+	}
+
+	// Names starting with the prefix rank by usage, then levels starting with it;
+	// tags only on other groups' links aren't suggested
+	if strings.Join(names, ",") != "money,monitoring,infra/monitoring" {
+		t.Errorf("Unexpected suggestions %v", names)
+	}
+	if suggestions[0].LinkCount != 2 {
+		t.Errorf("Expected money to be used twice, got %d", suggestions[0].LinkCount)
+	}
+}
+
+func TestSuggestTagsForLink(t *testing.T) {
+	db := setupTestDB(t)
+	router := setupTestRouter(db)
+	user := createTestUser(t, db, "test@example.com")
+	group := createTestGroup(t, db, "Test Group", user.ID)
+
+	create := func(slug, url, title string, tagNames ...string) models.Link {
+		link := models.Link{GroupID: group.ID, CreatedByID: user.ID, Slug: slug, URL: url, Title: title}
+		db.Create(&link)
+		found, _ := FindOrCreate(db, 0, tagNames)
+		if len(found) > 0 {
+			db.Model(&link).Association("Tags").Append(found)
+		}
+		return link
+	}
+	create("grafana-prod", "https://www.grafana.example.com/d/prod", "Production dashboards", "monitoring", "prod")
+	create("grafana-staging", "https://grafana.example.com/d/staging", "Staging dashboards", "monitoring", "staging")
+	create("kibana-logs", "https://kibana.example.com", "Search the logs", "logging")
+	create("alerts", "https://alerts.example.com", "Alert dashboards", "oncall")
+	link := create("grafana-dev", "https://grafana.example.com/d/dev", "Development dashboards", "staging")
+
+	req, _ := http.NewRequest("GET", "/api/links/"+link.Slug+"/tags/suggestions", nil)
+	req.Header.Set("Authorization", getAuthHeader(user))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var suggestions []TagSuggestion
+	json.Unmarshal(resp.Body.Bytes(), &suggestions)
+	names := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		names[i] = s.Name
+	}
+
+	// monitoring is on two links on the same domain; tags the link already
+	// has and tags on unrelated links are left out
+	if strings.Join(names, ",") != "monitoring,prod,oncall" {
+		t.Errorf("Unexpected suggestions %v", names)
+	}
+	if suggestions[0].LinkCount != 2 || suggestions[0].Score != 8 {
+		t.Errorf("Expected monitoring on 2 links scoring 8, got %+v", suggestions[0])
+	}
+
+	// Links the user can't see have no suggestions
+	stranger := createTestUser(t, db, "stranger@example.com")
+	req, _ = http.NewRequest("GET", "/api/links/"+link.Slug+"/tags/suggestions", nil)
+	req.Header.Set("Authorization", getAuthHeader(stranger))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,94 @@
+package tags
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mikepea/shorty/pkg/shorty/models"
+)
+
+// TagNode is a tag in the hierarchy formed by tag names like
+// infra/monitoring/grafana
+type TagNode struct {
+	ID             uint       `json:"id,omitempty"` // 0 for a level with no tag of its own, like infra when only infra/monitoring is used
+	OrganizationID uint       `json:"organization_id"`
+	Name           string     `json:"name"` // The last level of the path
+	Path           string     `json:"path"` // The full tag name
+	Description    string     `json:"description,omitempty"`
+	Color          string     `json:"color,omitempty"`
+	LinkCount      int        `json:"link_count"`       // Links with exactly this tag
+	TotalLinkCount int        `json:"total_link_count"` // Links with this tag or any tag below it, counted once each
+	Children       []*TagNode `json:"children,omitempty"`
+
+	links map[uint]bool // Every link in the subtree
+}
+
+// taggedLink pairs a tag with one of the links using it
+type taggedLink struct {
+	models.Tag
+	LinkID uint
+}
+
+// buildTree arranges tags into a tree per organization, rolling link counts
+// up to every ancestor
+func buildTree(rows []taggedLink) []*TagNode {
+	type key struct {
+		orgID uint
+		path  string
+	}
+	nodes := make(map[key]*TagNode)
+	var roots []*TagNode
+
+	// node returns the node for a path, creating it and its ancestors as needed
+	var node func(orgID uint, path string) *TagNode
+	node = func(orgID uint, path string) *TagNode {
+		if n, ok := nodes[key{orgID, path}]; ok {
+			return n
+		}
+		n := &TagNode{OrganizationID: orgID, Name: path, Path: path, links: make(map[uint]bool)}
+		nodes[key{orgID, path}] = n
+		if i := strings.LastIndex(path, Separator); i >= 0 {
+			n.Name = path[i+1:]
+			parent := node(orgID, path[:i])
+			parent.Children = append(parent.Children, n)
+		} else {
+			roots = append(roots, n)
+		}
+		return n
+	}
+
+	for _, row := range rows {
+		n := node(row.OrganizationID, row.Name)
+		n.ID = row.ID
+		n.Description = row.Description
+		n.Color = row.Color
+		n.LinkCount++
+
+		// Roll the link up to every ancestor
+		for path := row.Name; ; {
+			nodes[key{row.OrganizationID, path}].links[row.LinkID] = true
+			i := strings.LastIndex(path, Separator)
+			if i < 0 {
+				break
+			}
+			path = path[:i]
+		}
+	}
+
+	var finish func([]*TagNode)
+	finish = func(level []*TagNode) {
+		sort.Slice(level, func(i, j int) bool {
+			if level[i].OrganizationID != level[j].OrganizationID {
+				return level[i].OrganizationID < level[j].OrganizationID
+			}
+			return level[i].Name < level[j].Name
+		})
+		for _, n := range level {
+			n.TotalLinkCount = len(n.links)
+			finish(n.Children)
+		}
+	}
+	finish(roots)
+
+	return roots
+}
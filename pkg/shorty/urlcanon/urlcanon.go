@@ -0,0 +1,162 @@
+// Package urlcanon normalizes link destinations so that URLs which differ only
+// cosmetically (host case, default ports, query parameter order, tracking
+// parameters) can be recognized as the same destination.
+package urlcanon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/mikepea/shorty/pkg/shorty/models"
+	"gorm.io/gorm"
+)
+
+// DefaultTrackingParams are query parameters dropped from every URL.
+// Entries ending in "*" match any parameter with that prefix.
+var DefaultTrackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"yclid",
+	"igshid",
+	"mc_cid",
+	"mc_eid",
+	"_ga",
+	"_gl",
+}
+
+// ErrInvalidURL is returned for URLs without a scheme or host
+var ErrInvalidURL = errors.New("URL must have a scheme and host")
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Canonicalize returns the canonical form of rawURL: the scheme and host are
+// lowercased, default ports are stripped, an empty path becomes "/", query
+// parameters are sorted and tracking parameters are removed. trackingParams
+// are dropped in addition to DefaultTrackingParams.
+func Canonicalize(rawURL string, trackingParams []string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", ErrInvalidURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	port := u.Port()
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]" // IPv6 literal
+	default:
+		u.Host = host
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	// url.Values.Encode sorts by key and keeps the order of repeated values
+	query, _ := url.ParseQuery(u.RawQuery)
+	for key := range query {
+		if isTrackingParam(key, DefaultTrackingParams) || isTrackingParam(key, trackingParams) {
+			delete(query, key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// isTrackingParam reports whether a query parameter matches one of the patterns
+func isTrackingParam(key string, patterns []string) bool {
+	key = strings.ToLower(key)
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Hash returns a hex-encoded SHA-256 of the canonical form of rawURL,
+// or an empty string if the URL can't be canonicalized
+func Hash(rawURL string, trackingParams []string) string {
+	canonical, err := Canonicalize(rawURL, trackingParams)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashForOrg hashes rawURL using the organization's extra tracking parameters
+func HashForOrg(db *gorm.DB, orgID uint, rawURL string) string {
+	var org models.Organization
+	if err := db.First(&org, orgID).Error; err != nil {
+		return Hash(rawURL, nil)
+	}
+	return Hash(rawURL, org.TrackingParamList())
+}
+
+// RehashOrganization recomputes the canonical hash of every link in an organization.
+// Used when the organization's tracking parameters change.
+func RehashOrganization(db *gorm.DB, orgID uint) error {
+	return rehash(db, db.Where("organization_id = ?", orgID))
+}
+
+// Backfill computes canonical hashes for links that don't have one yet
+func Backfill(db *gorm.DB) error {
+	return rehash(db, db.Where("canonical_hash = '' OR canonical_hash IS NULL"))
+}
+
+func rehash(db *gorm.DB, scope *gorm.DB) error {
+	params := make(map[uint][]string)
+
+	var links []models.Link
+	return scope.Select("id", "organization_id", "url", "canonical_hash").
+		FindInBatches(&links, 200, func(tx *gorm.DB, batch int) error {
+			for _, link := range links {
+				trackingParams, ok := params[link.OrganizationID]
+				if !ok {
+					var org models.Organization
+					if err := db.First(&org, link.OrganizationID).Error; err == nil {
+						trackingParams = org.TrackingParamList()
+					}
+					params[link.OrganizationID] = trackingParams
+				}
+
+				hash := Hash(link.URL, trackingParams)
+				if hash == link.CanonicalHash {
+					continue
+				}
+				if err := db.Model(&models.Link{}).Where("id = ?", link.ID).
+					UpdateColumn("canonical_hash", hash).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
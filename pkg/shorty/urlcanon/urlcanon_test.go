@@ -0,0 +1,63 @@
+package urlcanon
+
+import "testing"
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		extra    []string
+		expected string
+	}{
+		{"lowercases scheme and host", "HTTPS://Example.COM/Path", nil, "https://example.com/Path"},
+		{"strips default https port", "https://example.com:443/a", nil, "https://example.com/a"},
+		{"strips default http port", "http://example.com:80/a", nil, "http://example.com/a"},
+		{"keeps non-default port", "https://example.com:8443/a", nil, "https://example.com:8443/a"},
+		{"adds root path", "https://example.com", nil, "https://example.com/"},
+		{"strips trailing dot from host", "https://example.com./a", nil, "https://example.com/a"},
+		{"sorts query params", "https://example.com/d?b=2&a=1&c=3", nil, "https://example.com/d?a=1&b=2&c=3"},
+		{"keeps repeated value order", "https://example.com/d?x=2&x=1", nil, "https://example.com/d?x=2&x=1"},
+		{"drops default tracking params", "https://example.com/d?utm_source=mail&utm_medium=x&id=7&fbclid=abc", nil, "https://example.com/d?id=7"},
+		{"drops empty query", "https://example.com/d?", nil, "https://example.com/d"},
+		{"drops extra tracking params", "https://example.com/d?ref=home&id=7", []string{"ref"}, "https://example.com/d?id=7"},
+		{"drops extra prefix params", "https://example.com/d?src_a=1&src_b=2&id=7", []string{"src_*"}, "https://example.com/d?id=7"},
+		{"keeps fragment", "https://example.com/d#panel-2", nil, "https://example.com/d#panel-2"},
+		{"handles IPv6 hosts", "http://[::1]:80/a", nil, "http://[::1]/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Canonicalize(tt.input, tt.extra)
+			if err != nil {
+				t.Fatalf("Canonicalize(%q) failed: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Canonicalize(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeInvalid(t *testing.T) {
+	for _, input := range []string{"", "not a url", "/relative/path", "://missing-scheme"} {
+		if _, err := Canonicalize(input, nil); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	a := Hash("https://Example.com:443/dash?b=2&a=1&utm_campaign=x", nil)
+	b := Hash("https://example.com/dash?a=1&b=2", nil)
+	if a == "" || a != b {
+		t.Errorf("Expected equivalent URLs to hash the same, got %q and %q", a, b)
+	}
+
+	if c := Hash("https://example.com/dash?a=1&b=3", nil); c == a {
+		t.Error("Expected different URLs to hash differently")
+	}
+
+	if h := Hash("not a url", nil); h != "" {
+		t.Errorf("Expected empty hash for invalid URL, got %q", h)
+	}
+}
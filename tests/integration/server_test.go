@@ -8,6 +8,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mikepea/shorty/pkg/shorty/apikeys"
 	"github.com/mikepea/shorty/pkg/shorty/auth"
+	"github.com/mikepea/shorty/pkg/shorty/collections"
+	"github.com/mikepea/shorty/pkg/shorty/comments"
 	"github.com/mikepea/shorty/pkg/shorty/groups"
 	"github.com/mikepea/shorty/pkg/shorty/importexport"
 	"github.com/mikepea/shorty/pkg/shorty/links"
@@ -46,6 +48,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 func setupFullServer(db *gorm.DB) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	r.UseRawPath = true
 
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
@@ -88,11 +91,23 @@ func setupFullServer(db *gorm.DB) *gin.Engine {
 		tagsHandler := tags.NewHandler(db)
 		tagsHandler.RegisterRoutes(api.Group("", combinedAuth))
 
+		// Comments routes (protected - accepts JWT or API key)
+		commentsHandler := comments.NewHandler(db)
+		commentsHandler.RegisterRoutes(api.Group("", combinedAuth))
+
+		// Collections routes (protected - accepts JWT or API key)
+		collectionsHandler := collections.NewHandler(db)
+		collectionsHandler.RegisterRoutes(api.Group("", combinedAuth))
+
 		// Import/Export routes (protected - accepts JWT or API key)
 		importExportHandler := importexport.NewHandler(db)
 		importExportHandler.RegisterRoutes(api.Group("", combinedAuth))
 	}
 
+	// Public collection pages (must be registered before redirects)
+	publicCollectionsHandler := collections.NewHandler(db)
+	publicCollectionsHandler.RegisterPublicRoutes(r)
+
 	// Redirect routes (public, must be registered LAST to avoid conflicts)
 	redirectHandler := redirect.NewHandler(db)
 	redirectHandler.RegisterRoutes(r)